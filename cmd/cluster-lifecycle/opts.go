@@ -10,6 +10,7 @@ import (
 
 	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/installconfig"
 	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/signature"
 	"k8s.io/klog"
 )
 
@@ -28,17 +29,19 @@ type release struct {
 }
 
 type inputOpts struct {
-	dryRun            bool
-	installConfigPath string
-	enableTechPreview bool
-	pullSecretPath    string
-	release           release
-	sshKeyPath        string
-	preinstallcfg     string
-	prefix            string
-	workDir           string
-	writeLogFile      bool
-	variant           string
+	dryRun               bool
+	installConfigPath    string
+	enableTechPreview    bool
+	pullSecretPath       string
+	release              release
+	requireSignedRelease bool
+	signature            signature.Opts
+	sshKeyPath           string
+	preinstallcfg        string
+	prefix               string
+	workDir              string
+	writeLogFile         bool
+	variant              string
 }
 
 func (i *inputOpts) appendWorkDir(path string) string {
@@ -168,6 +171,14 @@ func (i *inputOpts) validateForSetup() error {
 		}
 	}
 
+	if i.requireSignedRelease {
+		if i.signature.PublicKeyPath == "" {
+			return fmt.Errorf("--release-signature-key must be provided when --require-signed-release is used")
+		}
+
+		i.signature.AuthfilePath = i.pullSecretPath
+	}
+
 	if i.release.pullspec == "" {
 		if i.release.kind == "okd-scos" && !strings.Contains(i.release.stream, "scos") {
 			return fmt.Errorf("invalid release stream %q for kind okd-scos", i.release.stream)
@@ -11,6 +11,7 @@ import (
 
 	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/installconfig"
 	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/signature"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog"
@@ -35,13 +36,17 @@ func init() {
 	setupCmd.PersistentFlags().StringVar(&setupOpts.release.pullspec, "release-pullspec", "", "An arbitrary release pullspec to spin up.")
 	setupCmd.PersistentFlags().StringVar(&setupOpts.release.arch, "release-arch", "amd64", fmt.Sprintf("Release arch, one of: %v", sets.List(installconfig.GetSupportedArches())))
 	setupCmd.PersistentFlags().StringVar(&setupOpts.release.kind, "release-kind", "ocp", fmt.Sprintf("Release kind, one of: %v", sets.List(installconfig.GetSupportedKinds())))
-	setupCmd.PersistentFlags().StringVar(&setupOpts.release.stream, "release-stream", "4.14.0-0.ci", "The release stream to use")
+	setupCmd.PersistentFlags().StringVar(&setupOpts.release.stream, "release-stream", "4.14.0-0.ci", "The release stream to use. May also be a release tag or a minor version (e.g., 4.18) to use the latest accepted z-stream release.")
 	setupCmd.PersistentFlags().StringVar(&setupOpts.sshKeyPath, "ssh-key-path", "", "Path to an SSH key to embed in the installation config.")
 	setupCmd.PersistentFlags().StringVar(&setupOpts.prefix, "prefix", "", "Prefix to add to the cluster name; will use current system user if not set.")
 	setupCmd.PersistentFlags().StringVar(&setupOpts.workDir, "work-dir", "", "The directory to use for running openshift-install. Enables vacation and persistent install mode when used in a cron job.")
 	setupCmd.PersistentFlags().BoolVar(&setupOpts.enableTechPreview, "enable-tech-preview", false, "Enables Tech Preview features")
 	setupCmd.PersistentFlags().StringVar(&setupOpts.variant, "variant", "", fmt.Sprintf("A cluster variant to bring up. One of: %v", sets.List(installconfig.GetSupportedVariants())))
 	setupCmd.PersistentFlags().StringVar(&setupOpts.preinstallcfg, "preinstallcfg", "", "Path to a script or binary to run after creating manifests but before installation.")
+	setupCmd.PersistentFlags().BoolVar(&setupOpts.requireSignedRelease, "require-signed-release", false, "Refuse to install a release payload which does not have a valid signature.")
+	setupCmd.PersistentFlags().StringVar((*string)(&setupOpts.signature.Mode), "release-signature-mode", string(signature.SimpleSigningMode), fmt.Sprintf("Kind of release signature to verify, one of: %s, %s", signature.SimpleSigningMode, signature.SigstoreMode))
+	setupCmd.PersistentFlags().StringVar(&setupOpts.signature.PublicKeyPath, "release-signature-key", "", "Path to the GPG public keyring (simple-signing) or PEM public key (sigstore) to verify the release signature with.")
+	setupCmd.PersistentFlags().StringVar(&setupOpts.signature.SignatureStore, "release-signature-store", "", fmt.Sprintf("Signature store URL or directory for simple-signing (default %q), or repository to read the image and its attached signatures from for sigstore.", signature.DefaultSignatureStore))
 	setupCmd.PersistentFlags().BoolVar(&setupOpts.dryRun, "dry-run", false, "Prepare but do not actually perform the installation.")
 
 	rootCmd.AddCommand(setupCmd)
@@ -84,6 +89,17 @@ func runSetup(ctx context.Context, setupOpts inputOpts) error {
 
 	klog.Infof("Found release %s", setupOpts.release.pullspec)
 
+	if setupOpts.requireSignedRelease {
+		verifiedPullspec, err := verifyReleaseSignature(ctx, setupOpts)
+		if err != nil {
+			return err
+		}
+
+		// Install from the digest that was verified so that the release cannot
+		// change between verifying and installing it.
+		setupOpts.release.pullspec = verifiedPullspec
+	}
+
 	installCfg, err := writeInstallConfig(setupOpts)
 	if err != nil {
 		return err
@@ -180,26 +196,36 @@ func getRelease(ctx context.Context, opts *inputOpts) (string, error) {
 	}
 
 	if !releaseFileExists {
-		return getReleaseFromController(ctx, opts.release)
+		return getReleaseFromController(ctx, opts)
 	}
 
 	return getReleaseFromFile(ctx, opts)
 }
 
-func getReleaseFromController(ctx context.Context, rel release) (string, error) {
+func getReleaseFromController(ctx context.Context, opts *inputOpts) (string, error) {
+	rel := opts.release
+
 	rc, err := releasecontroller.GetReleaseController(rel.kind, rel.arch)
 	if err != nil {
 		return "", err
 	}
 
-	klog.Infof("Getting latest release for stream %s from %s", rel.stream, rc)
+	klog.Infof("Resolving release %s using %s", rel.stream, rc)
+
+	resolved, err := releasecontroller.Resolve(ctx, rel.stream, releasecontroller.ResolveOpts{
+		Controllers:  []*releasecontroller.ReleaseController{rc},
+		AuthfilePath: opts.pullSecretPath,
+	})
 
-	release, err := rc.ReleaseStream(rel.stream).Latest(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	return release.Pullspec, nil
+	klog.Infof("Resolved %s to %s (%s)", rel.stream, resolved.Tag, resolved.DigestedPullspec)
+
+	// Install by digest so that the release which was resolved is the one
+	// that gets installed, even if its tag moves in the meantime.
+	return resolved.DigestedPullspec, nil
 }
 
 func getReleaseFromFile(ctx context.Context, opts *inputOpts) (string, error) {
@@ -217,6 +243,20 @@ func getReleaseFromFile(ctx context.Context, opts *inputOpts) (string, error) {
 	return release, opts.inferArchAndKindFromPullspec(ctx, release)
 }
 
+// Verifies the signature of the release and returns the digested pullspec
+// which was verified.
+func verifyReleaseSignature(ctx context.Context, opts inputOpts) (string, error) {
+	klog.Infof("Verifying signature for release %s", opts.release.pullspec)
+
+	result, err := signature.Verify(ctx, opts.release.pullspec, opts.signature)
+	if err != nil {
+		return "", fmt.Errorf("refusing to install unsigned release: %w", err)
+	}
+
+	klog.Infof("Release %s has a valid signature, verified with %s", result.Pullspec, result.PublicKey)
+	return result.Pullspec, nil
+}
+
 func isInVacationMode(opts inputOpts) (bool, error) {
 	vacationFile := opts.vacationFilePath()
 	inVacationMode, err := isFileExists(vacationFile)
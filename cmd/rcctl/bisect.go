@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/bisect"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/spf13/cobra"
+	"k8s.io/klog"
+)
+
+func bisectCmd() *cobra.Command {
+	var statePath string
+
+	bisectCmd := &cobra.Command{
+		Use:   "bisect",
+		Short: "Bisects the release tags between a good and bad release to find which one introduced a regression",
+		Long: `
+Bisects the accepted release tags between a known good and a known bad release
+tag within a releasestream. Each step proposes the midpoint tag to test. After
+marking it with 'rcctl bisect good' or 'rcctl bisect bad', the range is
+narrowed until the first bad tag is found, at which point the components and
+commits which changed between the last good and first bad tags are listed.
+
+The bisect state is kept in a local file between invocations.`,
+	}
+
+	bisectCmd.PersistentFlags().StringVar(&statePath, "state", "", "Path to the bisect state file (default $XDG_STATE_HOME/rcctl/bisect.json).")
+
+	var good string
+	var bad string
+
+	startCmd := &cobra.Command{
+		Use:   "start",
+		Short: "Starts a new bisect between a good and bad release tag.",
+		Args:  cobra.NoArgs,
+		Example: `
+	# Starts bisecting between two nightlies.
+	rcctl bisect start --good '4.19.0-0.nightly-2025-06-01-000000' --bad '4.19.0-0.nightly-2025-06-10-000000'`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			path, err := getBisectStatePath(statePath)
+			if err != nil {
+				return err
+			}
+
+			return doReleaseControllerOp(func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+				state, err := bisect.Start(ctx, rc, good, bad)
+				if err != nil {
+					return nil, err
+				}
+
+				if err := state.Save(path); err != nil {
+					return nil, err
+				}
+
+				klog.Infof("Bisecting %d accepted tags between %s and %s", len(state.Tags)-2, good, bad)
+
+				return getBisectStatus(ctx, rc, state)
+			})
+		},
+	}
+
+	startCmd.PersistentFlags().StringVar(&good, "good", "", "Release tag known not to have the regression.")
+	startCmd.PersistentFlags().StringVar(&bad, "bad", "", "Newer release tag known to have the regression.")
+	startCmd.MarkPersistentFlagRequired("good")
+	startCmd.MarkPersistentFlagRequired("bad")
+	startCmd.RegisterFlagCompletionFunc("good", completeReleaseTagFlag)
+	startCmd.RegisterFlagCompletionFunc("bad", completeReleaseTagFlag)
+
+	newMarkCmd := func(verdict bisect.Verdict) *cobra.Command {
+		return &cobra.Command{
+			Use:   fmt.Sprintf("%s [tag name]", verdict),
+			Short: fmt.Sprintf("Marks the proposed release tag, or the given one, as %s.", verdict),
+			Args:  cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				tag := ""
+				if len(args) == 1 {
+					tag = args[0]
+				}
+
+				return doBisectOp(statePath, func(ctx context.Context, rc *releasecontroller.ReleaseController, state *bisect.State, path string) (interface{}, error) {
+					if err := state.Mark(tag, verdict, time.Now()); err != nil {
+						return nil, err
+					}
+
+					if err := state.Save(path); err != nil {
+						return nil, err
+					}
+
+					return getBisectStatus(ctx, rc, state)
+				})
+			},
+		}
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Shows the current bisect range and the release tag to test next.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return doBisectOp(statePath, func(ctx context.Context, rc *releasecontroller.ReleaseController, state *bisect.State, _ string) (interface{}, error) {
+				return getBisectStatus(ctx, rc, state)
+			})
+		},
+	}
+
+	resetCmd := &cobra.Command{
+		Use:   "reset",
+		Short: "Ends the current bisect by removing its state file.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			path, err := getBisectStatePath(statePath)
+			if err != nil {
+				return err
+			}
+
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+
+			klog.Infof("Removed %s", path)
+
+			return nil
+		},
+	}
+
+	bisectCmd.AddCommand(startCmd)
+	bisectCmd.AddCommand(newMarkCmd(bisect.Good))
+	bisectCmd.AddCommand(newMarkCmd(bisect.Bad))
+	bisectCmd.AddCommand(statusCmd)
+	bisectCmd.AddCommand(resetCmd)
+
+	return bisectCmd
+}
+
+// Loads the bisect state and runs the given function against the release
+// controller the bisect was started on.
+func doBisectOp(statePath string, opFunc func(context.Context, *releasecontroller.ReleaseController, *bisect.State, string) (interface{}, error)) error {
+	path, err := getBisectStatePath(statePath)
+	if err != nil {
+		return err
+	}
+
+	state, err := bisect.Load(path)
+	if err != nil {
+		return err
+	}
+
+	rc, err := getReleaseControllerForHost(state.Controller)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	out, err := opFunc(ctx, rc, state, path)
+	if err != nil {
+		return err
+	}
+
+	return printOutput(out)
+}
+
+// Gets the bisect status and logs what to do next.
+func getBisectStatus(ctx context.Context, rc *releasecontroller.ReleaseController, state *bisect.State) (*bisect.Status, error) {
+	status, err := state.Status(ctx, rc)
+	if err != nil {
+		return nil, err
+	}
+
+	if status.Done {
+		klog.Infof("The first bad tag is %s, the last good tag is %s", status.Bad, status.Good)
+	} else {
+		klog.Infof("%d tags remaining, test %s next", status.Remaining, status.Next)
+	}
+
+	return status, nil
+}
+
+func getBisectStatePath(statePath string) (string, error) {
+	if statePath != "" {
+		return statePath, nil
+	}
+
+	return bisect.DefaultPath()
+}
+
+func init() {
+	rootCmd.AddCommand(bisectCmd())
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/filecache"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/printers"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// How long completion results are cached for. This is deliberately short
+	// since new tags appear frequently.
+	completionCacheTTL time.Duration = 5 * time.Minute
+	// How long a single completion lookup may take.
+	completionTimeout time.Duration = 30 * time.Second
+)
+
+// Completes release stream names for every positional argument.
+func completeReleaseStreams(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	streams, err := getReleasesForCompletion()
+	if err != nil {
+		cobra.CompErrorln(err.Error())
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return filterByPrefix(sortedKeys(streams), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// Completes a release stream name for the first positional argument only.
+func completeReleaseStreamArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return completeReleaseStreams(cmd, args, toComplete)
+}
+
+// Completes a release tag name for the first positional argument. Since the
+// tags in most release streams are prefixed with the name of their release
+// stream (e.g., 4.23.0-0.ci-2026-03-05-153752), those tags are collapsed into
+// the release stream name until it has been typed. Tags from other release
+// streams (e.g., 4.21.4 in 4-stable) are completed directly.
+func completeReleaseTagArg(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	streams, err := getReleasesForCompletion()
+	if err != nil {
+		cobra.CompErrorln(err.Error())
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return completeReleaseTags(streams, toComplete)
+}
+
+// Completes a release tag name for a flag value regardless of the positional
+// arguments.
+func completeReleaseTagFlag(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeReleaseTagArg(cmd, nil, toComplete)
+}
+
+func completeReleaseTags(streams map[string][]string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	out := []string{}
+	collapsed := false
+
+	for _, stream := range sortedKeys(streams) {
+		tags := streams[stream]
+
+		isPrefixed := len(tags) != 0 && strings.HasPrefix(tags[0], stream)
+
+		if isPrefixed && !strings.HasPrefix(toComplete, stream) {
+			if strings.HasPrefix(stream, toComplete) {
+				out = append(out, stream)
+				collapsed = true
+			}
+
+			continue
+		}
+
+		out = append(out, filterByPrefix(tags, toComplete)...)
+	}
+
+	// Do not add a trailing space after a release stream name so that its tags
+	// can be completed next.
+	if collapsed {
+		return out, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// Completes the component names for the release given as the first
+// positional argument.
+func completeComponents(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	rc, err := getReleaseController()
+	if err != nil {
+		cobra.CompErrorln(err.Error())
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	components, err := getCached(fmt.Sprintf("%s/components/%s", rc.Host(), args[0]), func(ctx context.Context) ([]string, error) {
+		ri, err := releasecontroller.NewReleaseInfoFetcher(rc).GetReleaseInfo(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		out := []string{}
+		if ri.References != nil {
+			for _, tag := range ri.References.Spec.Tags {
+				out = append(out, tag.Name)
+			}
+		}
+
+		sort.Strings(out)
+
+		return out, nil
+	})
+
+	if err != nil {
+		cobra.CompErrorln(err.Error())
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return filterByPrefix(components, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeControllers(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	hosts := []string{}
+	for _, rc := range releasecontroller.All() {
+		hosts = append(hosts, rc.Host())
+	}
+
+	return filterByPrefix(hosts, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeOutputFormats(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	formats := []string{printers.JSON, printers.YAML, printers.Table, printers.Name, printers.JSONPath + "=", printers.GoTemplate + "="}
+
+	out := filterByPrefix(formats, toComplete)
+
+	// Formats which take an expression should not have a space added after
+	// them.
+	for _, format := range out {
+		if !strings.HasSuffix(format, "=") {
+			return out, cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+
+	return out, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// Gets all release streams and their release names from the release
+// controller.
+func getReleasesForCompletion() (map[string][]string, error) {
+	rc, err := getReleaseController()
+	if err != nil {
+		return nil, err
+	}
+
+	return getCached(fmt.Sprintf("%s/releasestreams", rc.Host()), rc.ReleaseStreams().All)
+}
+
+// Completion is invoked for every tab press, so lookups are cached for a short
+// time to keep it fast. If the cache is unavailable, the lookup is performed
+// directly.
+func getCached[T any](key string, fetch func(context.Context) (T, error)) (T, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	fetchFunc := func() (T, error) {
+		return fetch(ctx)
+	}
+
+	cache, err := filecache.NewUserCache("rcctl", completionCacheTTL)
+	if err != nil {
+		return fetchFunc()
+	}
+
+	return filecache.GetOrFetch(cache, key, fetchFunc)
+}
+
+func filterByPrefix(in []string, prefix string) []string {
+	out := []string{}
+	for _, item := range in {
+		if strings.HasPrefix(item, prefix) {
+			out = append(out, item)
+		}
+	}
+
+	return out
+}
+
+func sortedKeys(in map[string][]string) []string {
+	out := []string{}
+	for key := range in {
+		out = append(out, key)
+	}
+
+	sort.Strings(out)
+
+	return out
+}
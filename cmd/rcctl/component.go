@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/filecache"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/spf13/cobra"
+	"k8s.io/klog"
+)
+
+// How long release info is cached for. The payload for a release tag never
+// changes, so this only bounds how large the cache grows.
+const releaseInfoCacheTTL time.Duration = 7 * 24 * time.Hour
+
+func componentCmd() *cobra.Command {
+	componentCmd := &cobra.Command{
+		Use:   "component",
+		Short: "Operations on a specific release payload component",
+	}
+
+	opts := releasecontroller.ComponentHistoryOpts{}
+	var stream string
+	var noCache bool
+	var timeout time.Duration
+
+	historyCmd := &cobra.Command{
+		Use:   "history [component name]",
+		Short: "Shows each point in a releasestream where a component's image or source commit changed.",
+		Args:  cobra.ExactArgs(1),
+		Example: `
+	# Shows when the machine-config-operator image changed in the last 50 nightlies.
+	rcctl component history machine-config-operator --stream 4.19.0-0.nightly -o table
+
+	# Walks further back in the releasestream.
+	rcctl component history machine-config-operator --stream 4.19.0-0.nightly --last 200 --concurrency 20`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !noCache {
+				cache, err := filecache.NewUserCache("rcctl/releaseinfo", releaseInfoCacheTTL)
+				if err != nil {
+					klog.Warningf("Release info will not be cached: %s", err)
+				}
+
+				opts.Cache = cache
+			}
+
+			return doReleaseControllerOpWithTimeout(timeout, func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+				return rc.ReleaseStream(stream).ComponentHistory(ctx, args[0], opts)
+			})
+		},
+	}
+
+	historyCmd.PersistentFlags().StringVar(&stream, "stream", "", "Releasestream to walk.")
+	historyCmd.PersistentFlags().IntVar(&opts.Last, "last", 50, "Number of most recent tags to walk.")
+	historyCmd.PersistentFlags().IntVar(&opts.Concurrency, "concurrency", 10, "Maximum number of concurrent release info lookups.")
+	historyCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Do not read or write the release info cache.")
+	historyCmd.PersistentFlags().DurationVar(&timeout, "timeout", 5*time.Minute, "Maximum amount of time to spend walking the releasestream.")
+	historyCmd.MarkPersistentFlagRequired("stream")
+	historyCmd.RegisterFlagCompletionFunc("stream", completeReleaseStreams)
+
+	componentCmd.AddCommand(historyCmd)
+
+	return componentCmd
+}
+
+func init() {
+	rootCmd.AddCommand(componentCmd())
+}
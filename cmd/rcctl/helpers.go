@@ -1,18 +1,23 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
+	"strings"
 	"time"
 
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/containers"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/proxy"
 	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/opencontainers/go-digest"
 )
 
 func doReleaseControllerOp(opFunc func(context.Context, *releasecontroller.ReleaseController) (interface{}, error)) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	return doReleaseControllerOpWithTimeout(60*time.Second, opFunc)
+}
+
+func doReleaseControllerOpWithTimeout(timeout time.Duration, opFunc func(context.Context, *releasecontroller.ReleaseController) (interface{}, error)) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	rc, err := getReleaseController()
@@ -25,36 +30,91 @@ func doReleaseControllerOp(opFunc func(context.Context, *releasecontroller.Relea
 		return err
 	}
 
-	return printJSON(out)
+	return printOutput(out)
 }
 
 func getReleaseController() (*releasecontroller.ReleaseController, error) {
-	allRCs := releasecontroller.All()
-	for _, rc := range allRCs {
-		if controller == rc.Host() {
+	return getReleaseControllerForHost(controller)
+}
+
+// Gets the release controller for commands which produce links that are shared
+// with others (e.g., reports and notifications) or which record what they see
+// under the release controller's name (e.g., history). See withUpstream.
+func getReleaseControllerForLinks(ctx context.Context) (*releasecontroller.ReleaseController, error) {
+	rc, err := getReleaseController()
+	if err != nil {
+		return nil, err
+	}
+
+	return withUpstream(ctx, rc)
+}
+
+// When the release controller is a cache served by rcctl serve, links point at
+// the release controller it caches (see WebController) since the cache is
+// usually only reachable locally and may be restarted against another release
+// controller.
+func withUpstream(ctx context.Context, rc *releasecontroller.ReleaseController) (*releasecontroller.ReleaseController, error) {
+	// The known release controllers are not caches.
+	for _, known := range releasecontroller.All() {
+		if rc.Host() == known.Host() {
 			return rc, nil
 		}
 	}
 
-	return nil, fmt.Errorf("invalid release controller %q: %v", controller, allRCs)
+	upstream, err := proxy.GetUpstream(ctx, rc)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine whether %s is a release controller cache: %w", rc, err)
+	}
+
+	if upstream == nil {
+		return rc, nil
+	}
+
+	return rc.WithWebController(upstream), nil
 }
 
-func printJSON(obj interface{}) error {
-	if b, ok := obj.([]byte); ok {
-		outBuf := bytes.NewBuffer([]byte{})
-		if err := json.Indent(outBuf, b, "", "    "); err != nil {
-			return err
+func getReleaseControllerForHost(host string) (*releasecontroller.ReleaseController, error) {
+	allRCs := releasecontroller.All()
+	for _, rc := range allRCs {
+		if host == rc.Host() {
+			return rc, nil
 		}
+	}
 
-		_, err := os.Stdout.Write(outBuf.Bytes())
-		return err
+	// URLs are accepted so that a release controller cache served by rcctl
+	// serve (or any other release controller) can be used.
+	if strings.Contains(host, "://") {
+		return releasecontroller.NewFromURL(host, nil)
+	}
+
+	return nil, fmt.Errorf("invalid release controller %q: %v", host, allRCs)
+}
+
+// Gets the digest for the given image digest or pullspec. Tagged pullspecs are
+// resolved against their registry.
+func getDigestForImage(image, authfilePath string) (digest.Digest, error) {
+	if d, err := releasecontroller.ParseImageDigest(image); err == nil {
+		return d, nil
 	}
 
-	jsonb, err := json.MarshalIndent(obj, "", "    ")
+	digestedPullspec, err := containers.ResolveToDigestedPullspec(image, authfilePath)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("could not resolve %q to a digest: %w", image, err)
 	}
 
-	_, err = os.Stdout.Write(jsonb)
-	return err
+	return releasecontroller.ParseImageDigest(digestedPullspec)
+}
+
+// exitCodeError causes rcctl to exit with the given exit code instead of 1.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
 }
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/history"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/spf13/cobra"
+)
+
+func recordCmd() *cobra.Command {
+	var dbPath string
+	var timeout time.Duration
+	opts := history.RecordOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "record [releasestream...]",
+		Short: "Records the releases in the given releasestreams along with their phases and job results into a local database.",
+		Long: `
+Records the releases in the given releasestreams along with their phases and
+verification job results into a local database so that they can be queried
+with 'rcctl history' after the release controller has forgotten them. All
+releasestreams are recorded if none are given. This is intended to be run
+periodically, e.g., from a cron job.`,
+		Example: `
+	# Records all releasestreams on the release controller.
+	rcctl record
+
+	# Records the given releasestreams into a specific database.
+	rcctl record '4.23.0-0.nightly' '4.23.0-0.ci' --db ./history.db`,
+		ValidArgsFunction: completeReleaseStreams,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doHistoryOp(dbPath, func(s *history.Store) error {
+				return doReleaseControllerOpWithTimeout(timeout, func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+					rc, err := withUpstream(ctx, rc)
+					if err != nil {
+						return nil, err
+					}
+
+					streams := args
+					if len(streams) == 0 {
+						all, err := newReleaseStreamsHelper(rc).AllReleaseStreamNames(ctx)
+						if err != nil {
+							return nil, err
+						}
+
+						streams = all
+					}
+
+					out := []*history.RecordResult{}
+
+					for _, stream := range streams {
+						result, err := history.Record(ctx, s, rc, stream, opts)
+						if err != nil {
+							return nil, err
+						}
+
+						out = append(out, result)
+					}
+
+					return out, nil
+				})
+			})
+		},
+	}
+
+	addHistoryDBFlag(cmd, &dbPath)
+	cmd.PersistentFlags().IntVar(&opts.Concurrency, "concurrency", 5, "Maximum number of releases to get the job results for at once.")
+	cmd.PersistentFlags().DurationVar(&timeout, "timeout", 30*time.Minute, "Maximum amount of time to spend recording.")
+
+	return cmd
+}
+
+func historyCmd() *cobra.Command {
+	var dbPath string
+	var since time.Duration
+	var period string
+
+	historyCmd := &cobra.Command{
+		Use:   "history",
+		Short: "Queries the releases recorded by 'rcctl record'.",
+	}
+
+	addHistoryDBFlag(historyCmd, &dbPath)
+	historyCmd.PersistentFlags().DurationVar(&since, "since", 28*24*time.Hour, "Only include releases created within this long.")
+	historyCmd.PersistentFlags().StringVar(&period, "period", string(history.Week), "Period to group results by, one of: day, week")
+	historyCmd.RegisterFlagCompletionFunc("period", cobra.FixedCompletions([]string{string(history.Day), string(history.Week)}, cobra.ShellCompDirectiveNoFileComp))
+
+	getQueryOpts := func(streams []string) history.QueryOpts {
+		return history.QueryOpts{
+			Streams: streams,
+			Since:   time.Now().Add(-since),
+			Period:  history.Period(period),
+		}
+	}
+
+	acceptanceCmd := &cobra.Command{
+		Use:   "acceptance [releasestream...]",
+		Short: "Shows the acceptance ratio of each releasestream over time.",
+		Example: `
+	# Shows the weekly acceptance ratio of all recorded releasestreams over the last 4 weeks.
+	rcctl history acceptance
+
+	# Shows the daily acceptance ratio of a given releasestream over the last week.
+	rcctl history acceptance '4.23.0-0.nightly' --period day --since 168h -o table`,
+		ValidArgsFunction: completeReleaseStreams,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doHistoryQuery(dbPath, func(s *history.Store) (interface{}, error) {
+				controller, err := getHistoryController()
+				if err != nil {
+					return nil, err
+				}
+
+				return s.AcceptanceTrends(controller, getQueryOpts(args))
+			})
+		},
+	}
+
+	jobsCmd := &cobra.Command{
+		Use:   "jobs [releasestream...]",
+		Short: "Shows the pass rate of each verification job over time.",
+		Example: `
+	# Shows the weekly pass rate of each job in a given releasestream over the last 4 weeks.
+	rcctl history jobs '4.23.0-0.nightly' -o table`,
+		ValidArgsFunction: completeReleaseStreams,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doHistoryQuery(dbPath, func(s *history.Store) (interface{}, error) {
+				controller, err := getHistoryController()
+				if err != nil {
+					return nil, err
+				}
+
+				return s.JobPassRates(controller, getQueryOpts(args))
+			})
+		},
+	}
+
+	historyCmd.AddCommand(acceptanceCmd)
+	historyCmd.AddCommand(jobsCmd)
+
+	return historyCmd
+}
+
+// Gets the name release tags are recorded under by rcctl record (see
+// history.Record) for the given release controller.
+func getHistoryController() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	rc, err := getReleaseControllerForLinks(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return rc.WebController().String(), nil
+}
+
+func addHistoryDBFlag(cmd *cobra.Command, dbPath *string) {
+	cmd.PersistentFlags().StringVar(dbPath, "db", "", "Path to the history database. (default \"$XDG_DATA_HOME/rcctl/history.db\")")
+}
+
+func doHistoryOp(dbPath string, fn func(*history.Store) error) error {
+	if dbPath == "" {
+		defaultPath, err := history.DefaultPath()
+		if err != nil {
+			return err
+		}
+
+		dbPath = defaultPath
+	}
+
+	s, err := history.Open(dbPath)
+	if err != nil {
+		return err
+	}
+
+	defer s.Close()
+
+	return fn(s)
+}
+
+func doHistoryQuery(dbPath string, fn func(*history.Store) (interface{}, error)) error {
+	return doHistoryOp(dbPath, func(s *history.Store) error {
+		out, err := fn(s)
+		if err != nil {
+			return err
+		}
+
+		return printOutput(out)
+	})
+}
+
+func init() {
+	rootCmd.AddCommand(recordCmd())
+	rootCmd.AddCommand(historyCmd())
+}
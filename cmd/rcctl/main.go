@@ -1,14 +1,18 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/printers"
 	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
 	"github.com/spf13/cobra"
 )
 
 var controller string
+var output string
 
 var rootCmd = &cobra.Command{
 	Use:   "rcctl",
@@ -16,16 +20,32 @@ var rootCmd = &cobra.Command{
 	Long: `
 The intent of this CLI tool is that it will be used as part of scripts and
 other automation which rely on querying the release controller. Therefore, all
-data returned from it will be returned as JSON to stdout.`,
+data returned from it will be returned as JSON to stdout by default. Other
+output formats may be selected with -o / --output.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Validate the output format before doing any potentially slow work.
+		_, err := printers.NewPrinter(output, toTable)
+		return err
+	},
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&controller, "controller", string(releasecontroller.Amd64OcpReleaseController), "Override the default release controller")
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", printers.JSON, fmt.Sprintf("Output format, one of: %s", strings.Join(printers.Formats(), ", ")))
+
+	rootCmd.RegisterFlagCompletionFunc("controller", completeControllers)
+	rootCmd.RegisterFlagCompletionFunc("output", completeOutputFormats)
 }
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
+
+		exitErr := &exitCodeError{}
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
+
 		os.Exit(1)
 	}
 }
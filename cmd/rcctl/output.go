@@ -0,0 +1,626 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/bisect"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/cincinnati"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/history"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/manifests"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/mirror"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/multiarch"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/printers"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/signature"
+)
+
+func printOutput(obj interface{}) error {
+	p, err := printers.NewPrinter(output, toTable)
+	if err != nil {
+		return err
+	}
+
+	return p.Print(os.Stdout, obj)
+}
+
+// Converts the objects returned by each command into the rows and columns
+// shown by the table and name output formats.
+func toTable(obj interface{}) (*printers.TableData, error) {
+	switch o := obj.(type) {
+	case *releasecontroller.ReleaseTags:
+		return tagsTable(o.Tags), nil
+	case *releasecontroller.Release:
+		return tagsTable([]releasecontroller.Release{*o}), nil
+	case []string:
+		return namesTable(o), nil
+	case map[string][]string:
+		return releasesTable(o), nil
+	case *releasecontroller.APIReleaseInfo:
+		return jobsTable(o), nil
+	case *releasecontroller.ReleaseInfoResults:
+		return componentsTableFromResults(o)
+	case *releasecontroller.MachineOSInfo:
+		return machineOSTable(o), nil
+	case []releasecontroller.ImageMatch:
+		return imageMatchesTable(o), nil
+	case *mirror.Result:
+		return mirrorTable(o), nil
+	case *signature.Result:
+		return signatureTable(o), nil
+	case *multiarch.Result:
+		return archesTable(o), nil
+	case *releasecontroller.ResolvedRelease:
+		return resolvedReleaseTable(o), nil
+	case *releasecontroller.IdentifiedRelease:
+		return identifiedReleaseTable(o), nil
+	case *cincinnati.Updates:
+		return updatesTable(o), nil
+	case []*releasecontroller.StreamHealth:
+		return streamHealthTable(o), nil
+	case []*history.RecordResult:
+		return recordResultsTable(o), nil
+	case []history.AcceptanceTrend:
+		return acceptanceTrendsTable(o), nil
+	case []history.JobPassRate:
+		return jobPassRatesTable(o), nil
+	case []manifests.Manifest:
+		return manifestsTable(o), nil
+	case []manifests.Match:
+		return manifestMatchesTable(o), nil
+	case *manifests.DiffResult:
+		return manifestsDiffTable(o), nil
+	case *manifests.FeatureGateMatrix:
+		return featureGateMatrixTable(o), nil
+	case *manifests.FeatureGateDiff:
+		return featureGateDiffTable(o), nil
+	case []releasecontroller.Component:
+		return componentSourcesTable(o), nil
+	case []releasecontroller.ComponentChange:
+		return componentChangesTable(o), nil
+	case *bisect.Status:
+		return bisectStatusTable(o), nil
+	case *releasecontroller.PayloadSize:
+		return payloadSizeTable(o), nil
+	case *releasecontroller.PayloadSizeDiff:
+		return payloadSizeDiffTable(o), nil
+	case *releasecontroller.CrossArchComparison:
+		return crossArchTable(o), nil
+	case *releasecontroller.ToolsDownload:
+		return toolsDownloadTable(o), nil
+	}
+
+	return nil, fmt.Errorf("table output is not supported for %T, try -o json or -o yaml", obj)
+}
+
+func tagsTable(tags []releasecontroller.Release) *printers.TableData {
+	out := &printers.TableData{Headers: []string{"NAME", "PHASE", "PULLSPEC"}}
+
+	for _, tag := range tags {
+		out.Rows = append(out.Rows, []string{tag.Name, tag.Phase, tag.Pullspec})
+		out.Names = append(out.Names, tag.Name)
+	}
+
+	return out
+}
+
+func namesTable(names []string) *printers.TableData {
+	out := &printers.TableData{Headers: []string{"NAME"}}
+
+	for _, name := range names {
+		out.Rows = append(out.Rows, []string{name})
+		out.Names = append(out.Names, name)
+	}
+
+	return out
+}
+
+func releasesTable(releases map[string][]string) *printers.TableData {
+	out := &printers.TableData{Headers: []string{"STREAM", "RELEASE"}}
+
+	streams := []string{}
+	for stream := range releases {
+		streams = append(streams, stream)
+	}
+
+	sort.Strings(streams)
+
+	for _, stream := range streams {
+		for _, release := range releases[stream] {
+			out.Rows = append(out.Rows, []string{stream, release})
+			out.Names = append(out.Names, release)
+		}
+	}
+
+	return out
+}
+
+func jobsTable(info *releasecontroller.APIReleaseInfo) *printers.TableData {
+	out := &printers.TableData{Headers: []string{"JOB", "KIND", "STATE", "RETRIES", "URL"}}
+
+	if info.Results == nil {
+		return out
+	}
+
+	jobKinds := []struct {
+		kind string
+		jobs releasecontroller.VerificationStatusMap
+	}{
+		{kind: "Blocking", jobs: info.Results.BlockingJobs},
+		{kind: "Informing", jobs: info.Results.InformingJobs},
+		{kind: "Pending", jobs: info.Results.PendingJobs},
+	}
+
+	for _, jobKind := range jobKinds {
+		names := []string{}
+		for name := range jobKind.jobs {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			status := jobKind.jobs[name]
+			if status == nil {
+				continue
+			}
+
+			out.Rows = append(out.Rows, []string{name, jobKind.kind, status.State, strconv.Itoa(status.Retries), status.URL})
+			out.Names = append(out.Names, name)
+		}
+	}
+
+	return out
+}
+
+func componentsTableFromResults(results *releasecontroller.ReleaseInfoResults) (*printers.TableData, error) {
+	ri := &releasecontroller.ReleaseInfo{}
+	if err := json.Unmarshal(results.ReleaseInfo, ri); err != nil {
+		return nil, fmt.Errorf("could not parse release info: %w", err)
+	}
+
+	out := &printers.TableData{Headers: []string{"COMPONENT", "IMAGE"}}
+
+	if ri.References == nil {
+		return out, nil
+	}
+
+	for _, tag := range ri.References.Spec.Tags {
+		image := ""
+		if tag.From != nil {
+			image = tag.From.Name
+		}
+
+		out.Rows = append(out.Rows, []string{tag.Name, image})
+		out.Names = append(out.Names, tag.Name)
+	}
+
+	return out, nil
+}
+
+func machineOSTable(info *releasecontroller.MachineOSInfo) *printers.TableData {
+	return &printers.TableData{
+		Headers: []string{"VERSION", "STREAM", "BUILD ID", "PULLSPEC"},
+		Rows:    [][]string{{info.Version, info.Stream, info.BuildID, info.RHELCoreOSPullspec}},
+		Names:   []string{info.Version},
+	}
+}
+
+func imageMatchesTable(matches []releasecontroller.ImageMatch) *printers.TableData {
+	out := &printers.TableData{Headers: []string{"STREAM", "TAG", "PHASE", "COMPONENT", "IMAGE"}}
+
+	for _, match := range matches {
+		out.Rows = append(out.Rows, []string{match.Stream, match.Tag, match.Phase, match.Component, match.Image})
+		out.Names = append(out.Names, match.Tag)
+	}
+
+	return out
+}
+
+func mirrorTable(result *mirror.Result) *printers.TableData {
+	out := &printers.TableData{Headers: []string{"NAME", "DIGEST", "SKIPPED", "DESTINATION"}}
+
+	for _, img := range result.Images {
+		out.Rows = append(out.Rows, []string{img.Name, img.Digest, strconv.FormatBool(img.Skipped), img.Destination})
+		out.Names = append(out.Names, img.Name)
+	}
+
+	return out
+}
+
+func signatureTable(result *signature.Result) *printers.TableData {
+	return &printers.TableData{
+		Headers: []string{"DIGEST", "MODE", "PUBLIC KEY", "IDENTITY"},
+		Rows:    [][]string{{result.Digest, string(result.Mode), result.PublicKey, result.Identity}},
+		Names:   []string{result.Pullspec},
+	}
+}
+
+func archesTable(result *multiarch.Result) *printers.TableData {
+	out := &printers.TableData{Headers: []string{"COMPONENT", "ARCHITECTURES", "MISSING"}}
+
+	for _, component := range result.Components {
+		missing := "<none>"
+		if len(component.Missing) != 0 {
+			missing = strings.Join(component.Missing, ",")
+		}
+
+		out.Rows = append(out.Rows, []string{component.Name, strings.Join(component.Architectures, ","), missing})
+		out.Names = append(out.Names, component.Name)
+	}
+
+	return out
+}
+
+func resolvedReleaseTable(resolved *releasecontroller.ResolvedRelease) *printers.TableData {
+	name := resolved.Tag
+	if name == "" {
+		name = resolved.DigestedPullspec
+	}
+
+	return &printers.TableData{
+		Headers: []string{"TAG", "STREAM", "CONTROLLER", "PHASE", "PULLSPEC"},
+		Rows:    [][]string{{resolved.Tag, resolved.Stream, resolved.Controller, resolved.Phase, resolved.DigestedPullspec}},
+		Names:   []string{name},
+	}
+}
+
+func identifiedReleaseTable(identified *releasecontroller.IdentifiedRelease) *printers.TableData {
+	return &printers.TableData{
+		Headers: []string{"TAG", "STREAM", "CONTROLLER", "PHASE", "TAG MATCHES DIGEST"},
+		Rows:    [][]string{{identified.Tag, identified.Stream, identified.Controller, identified.Phase, strconv.FormatBool(identified.TagMatchesDigest)}},
+		Names:   []string{identified.Tag},
+	}
+}
+
+func updatesTable(updates *cincinnati.Updates) *printers.TableData {
+	out := &printers.TableData{Headers: []string{"VERSION", "RECOMMENDED", "RISKS", "PAYLOAD"}}
+
+	for _, update := range updates.Recommended {
+		out.Rows = append(out.Rows, []string{update.Version, "true", "", update.Payload})
+		out.Names = append(out.Names, update.Version)
+	}
+
+	for _, update := range updates.Conditional {
+		risks := []string{}
+		for _, risk := range update.Risks {
+			risks = append(risks, risk.Name)
+		}
+
+		out.Rows = append(out.Rows, []string{update.Version, "false", strings.Join(risks, ","), update.Payload})
+		out.Names = append(out.Names, update.Version)
+	}
+
+	return out
+}
+
+func streamHealthTable(results []*releasecontroller.StreamHealth) *printers.TableData {
+	out := &printers.TableData{Headers: []string{"STREAM", "LAST ACCEPTED", "AGE", "ACCEPTED", "REJECTION STREAK", "MOST COMMON BLOCKING FAILURE", "STALE"}}
+
+	for _, result := range results {
+		mostCommonFailure := ""
+		if result.MostCommonBlockingFailure != "" {
+			mostCommonFailure = fmt.Sprintf("%s (%d)", result.MostCommonBlockingFailure, result.MostCommonBlockingFailureCount)
+		}
+
+		out.Rows = append(out.Rows, []string{
+			result.Stream,
+			result.LastAccepted,
+			result.SinceLastAccepted,
+			fmt.Sprintf("%d/%d", result.Accepted, result.Considered),
+			strconv.Itoa(result.RejectionStreak),
+			mostCommonFailure,
+			strconv.FormatBool(result.Stale),
+		})
+
+		out.Names = append(out.Names, result.Stream)
+	}
+
+	return out
+}
+
+func recordResultsTable(results []*history.RecordResult) *printers.TableData {
+	out := &printers.TableData{Headers: []string{"STREAM", "OBSERVED", "NEW", "UPDATED"}}
+
+	for _, result := range results {
+		out.Rows = append(out.Rows, []string{result.Stream, strconv.Itoa(result.Observed), strconv.Itoa(result.New), strconv.Itoa(result.Updated)})
+		out.Names = append(out.Names, result.Stream)
+	}
+
+	return out
+}
+
+func acceptanceTrendsTable(trends []history.AcceptanceTrend) *printers.TableData {
+	out := &printers.TableData{Headers: []string{"STREAM", "PERIOD", "ACCEPTED", "REJECTED", "RATIO"}}
+
+	for _, trend := range trends {
+		out.Rows = append(out.Rows, []string{
+			trend.Stream,
+			trend.Period.Format(time.DateOnly),
+			strconv.Itoa(trend.Accepted),
+			strconv.Itoa(trend.Rejected),
+			fmt.Sprintf("%.2f", trend.AcceptanceRatio),
+		})
+
+		out.Names = append(out.Names, trend.Stream)
+	}
+
+	return out
+}
+
+func jobPassRatesTable(rates []history.JobPassRate) *printers.TableData {
+	out := &printers.TableData{Headers: []string{"STREAM", "JOB", "KIND", "PERIOD", "PASSED", "FAILED", "PASS RATE"}}
+
+	for _, rate := range rates {
+		out.Rows = append(out.Rows, []string{
+			rate.Stream,
+			rate.Job,
+			rate.Kind,
+			rate.Period.Format(time.DateOnly),
+			strconv.Itoa(rate.Passed),
+			strconv.Itoa(rate.Failed),
+			fmt.Sprintf("%.2f", rate.PassRate),
+		})
+
+		out.Names = append(out.Names, rate.Job)
+	}
+
+	return out
+}
+
+func manifestsTable(in []manifests.Manifest) *printers.TableData {
+	td := &printers.TableData{
+		Headers: []string{"FILE", "COMPONENT", "KIND", "NAMESPACE", "NAME"},
+	}
+
+	for _, m := range in {
+		td.Rows = append(td.Rows, []string{m.File, m.Component, m.Kind, m.Namespace, m.Name})
+		td.Names = append(td.Names, m.String())
+	}
+
+	return td
+}
+
+func manifestMatchesTable(matches []manifests.Match) *printers.TableData {
+	td := &printers.TableData{
+		Headers: []string{"FILE", "LINE", "KIND", "NAME", "TEXT"},
+	}
+
+	for _, match := range matches {
+		td.Rows = append(td.Rows, []string{match.File, strconv.Itoa(match.Line), match.Kind, match.Name, strings.TrimSpace(match.Text)})
+		td.Names = append(td.Names, match.File+":"+strconv.Itoa(match.Line))
+	}
+
+	return td
+}
+
+func manifestsDiffTable(result *manifests.DiffResult) *printers.TableData {
+	td := &printers.TableData{
+		Headers: []string{"CHANGE", "OBJECT", "FIELD", "FROM", "TO"},
+	}
+
+	for _, ref := range result.Added {
+		td.Rows = append(td.Rows, []string{"added", ref.String(), "", "", ref.File})
+		td.Names = append(td.Names, ref.String())
+	}
+
+	for _, ref := range result.Removed {
+		td.Rows = append(td.Rows, []string{"removed", ref.String(), "", ref.File, ""})
+		td.Names = append(td.Names, ref.String())
+	}
+
+	for _, diff := range result.Changed {
+		for _, change := range diff.Changes {
+			td.Rows = append(td.Rows, []string{"changed", diff.String(), change.Path, fieldValueString(change.From), fieldValueString(change.To)})
+		}
+
+		td.Names = append(td.Names, diff.String())
+	}
+
+	return td
+}
+
+// Renders a field value compactly for a table cell.
+func fieldValueString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	if s, ok := value.(string); ok {
+		return s
+	}
+
+	out, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(out)
+}
+
+func featureGateMatrixTable(matrix *manifests.FeatureGateMatrix) *printers.TableData {
+	td := &printers.TableData{
+		Headers: append([]string{"GATE"}, matrix.FeatureSets...),
+	}
+
+	for _, gate := range matrix.Gates {
+		row := []string{gate.Name}
+		for _, featureSet := range matrix.FeatureSets {
+			row = append(row, gateStateString(gate.States[featureSet]))
+		}
+
+		td.Rows = append(td.Rows, row)
+		td.Names = append(td.Names, gate.Name)
+	}
+
+	return td
+}
+
+func featureGateDiffTable(diff *manifests.FeatureGateDiff) *printers.TableData {
+	td := &printers.TableData{
+		Headers: []string{"GATE", "FEATURE SET", "FROM", "TO"},
+	}
+
+	for _, change := range diff.Changes {
+		td.Rows = append(td.Rows, []string{change.Name, change.FeatureSet, gateStateString(change.From), gateStateString(change.To)})
+		td.Names = append(td.Names, change.Name)
+	}
+
+	return td
+}
+
+func gateStateString(state manifests.GateState) string {
+	if state == manifests.GateAbsent {
+		return "-"
+	}
+
+	return string(state)
+}
+
+func componentSourcesTable(components []releasecontroller.Component) *printers.TableData {
+	td := &printers.TableData{
+		Headers: []string{"COMPONENT", "REPO", "COMMIT", "COMMIT URL", "DIGEST"},
+	}
+
+	for _, component := range components {
+		td.Rows = append(td.Rows, []string{component.Name, component.Repo, component.Commit, component.CommitURL, component.Digest})
+		td.Names = append(td.Names, component.Name)
+	}
+
+	return td
+}
+
+func componentChangesTable(changes []releasecontroller.ComponentChange) *printers.TableData {
+	td := &printers.TableData{
+		Headers: []string{"TAG", "PHASE", "TAGS", "DIGEST", "COMMIT", "DIGEST CHANGED", "COMMIT CHANGED", "COMPARE URL"},
+	}
+
+	for _, change := range changes {
+		td.Rows = append(td.Rows, []string{change.Tag, change.Phase, strconv.Itoa(change.Tags), change.Digest, change.Commit, strconv.FormatBool(change.DigestChanged), strconv.FormatBool(change.CommitChanged), change.CompareURL})
+		td.Names = append(td.Names, change.Tag)
+	}
+
+	return td
+}
+
+// Shows the remaining range while bisecting and the changed components once
+// done.
+func bisectStatusTable(status *bisect.Status) *printers.TableData {
+	if !status.Done {
+		return &printers.TableData{
+			Headers: []string{"STREAM", "GOOD", "BAD", "REMAINING", "NEXT"},
+			Rows:    [][]string{{status.Stream, status.Good, status.Bad, strconv.Itoa(status.Remaining), status.Next}},
+			Names:   []string{status.Next},
+		}
+	}
+
+	td := &printers.TableData{
+		Headers: []string{"COMPONENT", "REPO", "FROM COMMIT", "TO COMMIT", "COMPARE URL"},
+	}
+
+	for _, change := range status.Changes {
+		td.Rows = append(td.Rows, []string{change.Name, change.Repo, change.FromCommit, change.ToCommit, change.CompareURL})
+		td.Names = append(td.Names, change.Name)
+	}
+
+	return td
+}
+
+// The totals are included as the last row so that they are visible in table
+// output.
+func payloadSizeTable(size *releasecontroller.PayloadSize) *printers.TableData {
+	td := &printers.TableData{
+		Headers: []string{"COMPONENT", "LAYERS", "SIZE", "UNIQUE SIZE"},
+	}
+
+	for _, component := range size.Components {
+		td.Rows = append(td.Rows, []string{component.Name, strconv.Itoa(component.Layers), formatBytes(component.Bytes), formatBytes(component.UniqueBytes)})
+		td.Names = append(td.Names, component.Name)
+	}
+
+	td.Rows = append(td.Rows, []string{"(total)", strconv.Itoa(size.Layers), formatBytes(size.TotalBytes), formatBytes(size.UniqueBytes)})
+
+	return td
+}
+
+func payloadSizeDiffTable(diff *releasecontroller.PayloadSizeDiff) *printers.TableData {
+	td := &printers.TableData{
+		Headers: []string{"COMPONENT", "FROM", "TO", "DELTA"},
+	}
+
+	for _, component := range diff.Components {
+		td.Rows = append(td.Rows, []string{component.Name, formatBytes(component.FromBytes), formatBytes(component.ToBytes), formatBytesDelta(component.Delta)})
+		td.Names = append(td.Names, component.Name)
+	}
+
+	td.Rows = append(td.Rows, []string{"(total unique)", formatBytes(diff.FromUniqueBytes), formatBytes(diff.ToUniqueBytes), formatBytesDelta(diff.UniqueBytesDelta)})
+
+	return td
+}
+
+// Components whose commits differ across architectures are not shown in the
+// table; they are logged as warnings by the command instead.
+func crossArchTable(comparison *releasecontroller.CrossArchComparison) *printers.TableData {
+	td := &printers.TableData{
+		Headers: []string{"ARCH", "STREAM", "TAG", "PHASE", "CREATED", "BLOCKING JOBS", "FAILED BLOCKING JOBS", "PULLSPEC"},
+	}
+
+	for _, release := range comparison.Releases {
+		failed := strings.Join(release.FailedBlockingJobs, ",")
+		if failed == "" {
+			failed = "-"
+		}
+
+		td.Rows = append(td.Rows, []string{release.Architecture, release.Stream, release.Tag, release.Phase, release.Created, release.BlockingJobs.String(), failed, release.Pullspec})
+		td.Names = append(td.Names, release.Architecture)
+	}
+
+	return td
+}
+
+func toolsDownloadTable(download *releasecontroller.ToolsDownload) *printers.TableData {
+	td := &printers.TableData{
+		Headers: []string{"TOOL", "SOURCE", "SHA256", "FILES", "FROM"},
+	}
+
+	for _, tool := range download.Tools {
+		td.Rows = append(td.Rows, []string{string(tool.Tool), tool.Source, tool.SHA256, strings.Join(tool.Files, ","), tool.From})
+		td.Names = append(td.Names, string(tool.Tool))
+	}
+
+	return td
+}
+
+// Formats a number of bytes using binary units, e.g., 1.5 GiB.
+func formatBytes(n int64) string {
+	const unit = 1024
+
+	if n < unit && n > -unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	value := float64(n)
+	suffixes := []string{"KiB", "MiB", "GiB", "TiB"}
+
+	i := -1
+	for (value >= unit || value <= -unit) && i < len(suffixes)-1 {
+		value /= unit
+		i++
+	}
+
+	return fmt.Sprintf("%.1f %s", value, suffixes[i])
+}
+
+// Formats a change in bytes with an explicit sign so that growth stands out.
+func formatBytesDelta(n int64) string {
+	if n > 0 {
+		return "+" + formatBytes(n)
+	}
+
+	return formatBytes(n)
+}
@@ -3,10 +3,24 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
 
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/containers"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/manifests"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/mirror"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/multiarch"
 	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/signature"
+	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
 )
 
 func releaseCmd() *cobra.Command {
@@ -36,7 +50,8 @@ func releaseCmd() *cobra.Command {
 
 	# Gets the release info and retrieves component image metadata only for the provided component images (comma-separated).
 	rcctl release oc-info '4.21.4-x86_64' --component 'machine-config-operator,rhel-coreos'`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeReleaseTagArg,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if allComponentMetadata && len(components) != 0 {
 				return fmt.Errorf("--all cannot be combined with --component")
@@ -61,6 +76,7 @@ func releaseCmd() *cobra.Command {
 
 	ocInfoCmd.PersistentFlags().StringSliceVar(&components, "component", []string{}, "Component(s) metadata to fetch.")
 	ocInfoCmd.PersistentFlags().BoolVar(&allComponentMetadata, "all-components", false, "Fetches all component image metadata.")
+	ocInfoCmd.RegisterFlagCompletionFunc("component", completeComponents)
 
 	infoCmd := &cobra.Command{
 		Use:   "info [tag name]",
@@ -69,6 +85,7 @@ func releaseCmd() *cobra.Command {
 		Example: `
 	# Gets release info from the release controller for a given release tag.
 	rcctl release info '4.21.4-x86_64'`,
+		ValidArgsFunction: completeReleaseTagArg,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return doReleaseControllerOp(func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
 				stream, release, err := rc.ReleaseStreams().FindReleaseNameAndStream(ctx, args[0])
@@ -81,12 +98,670 @@ func releaseCmd() *cobra.Command {
 		},
 	}
 
+	machineOSCmd := &cobra.Command{
+		Use:   "machine-os [tag name]",
+		Short: "Retrieves the machine-os (RHCOS) version info for a release using 'oc adm release info'.",
+		Args:  cobra.ExactArgs(1),
+		Example: `
+	# Gets the machine-os version info for a release tag.
+	rcctl release machine-os '4.21.4-x86_64'
+
+	# Gets the machine-os version info for a release image pullspec.
+	rcctl release machine-os 'quay.io/openshift-release-dev/ocp-release:4.21.4-x86_64'`,
+		ValidArgsFunction: completeReleaseTagArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doReleaseControllerOp(func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+				ri, err := releasecontroller.NewReleaseInfoFetcher(rc).GetReleaseInfo(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+
+				return ri.GetMachineOSInfo()
+			})
+		},
+	}
+
+	var whichImage string
+	var whichStreams []string
+	var whichLast int
+	var whichConcurrency int
+	var whichAuthfile string
+	var whichTimeout time.Duration
+
+	whichCmd := &cobra.Command{
+		Use:   "which",
+		Short: "Finds which releases contain a given component image.",
+		Args:  cobra.NoArgs,
+		Example: `
+	# Finds which of the last 10 tags in each releasestream contain the given image digest.
+	rcctl release which --image 'sha256:bec41abb841b042589766901962cf99bb7894bd673d4f71602523aa0c255a4f4'
+
+	# Finds which of the last 50 tags in a given releasestream contain the given image pullspec.
+	rcctl release which --image 'quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:bec41abb841b042589766901962cf99bb7894bd673d4f71602523aa0c255a4f4' --stream '4.23.0-0.nightly' --last 50
+
+	# Resolves a tagged image pullspec to its digest before searching for it.
+	rcctl release which --image 'registry.ci.openshift.org/ocp/4.23:machine-config-operator' --stream '4.23.0-0.ci' --authfile ~/.docker/config.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if whichImage == "" {
+				return fmt.Errorf("--image must be provided")
+			}
+
+			return doReleaseControllerOpWithTimeout(whichTimeout, func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+				d, err := getDigestForImage(whichImage, whichAuthfile)
+				if err != nil {
+					return nil, err
+				}
+
+				return rc.FindReleasesContainingImage(ctx, d, releasecontroller.FindImageOpts{
+					Streams:     whichStreams,
+					Last:        whichLast,
+					Concurrency: whichConcurrency,
+				})
+			})
+		},
+	}
+
+	whichCmd.PersistentFlags().StringVar(&whichImage, "image", "", "Image pullspec or digest to search for.")
+	whichCmd.PersistentFlags().StringSliceVar(&whichStreams, "stream", []string{}, "Releasestream(s) to search. Searches all releasestreams if not provided.")
+	whichCmd.PersistentFlags().IntVar(&whichLast, "last", 10, "Number of most recent tags to search in each releasestream.")
+	whichCmd.PersistentFlags().IntVar(&whichConcurrency, "concurrency", 10, "Maximum number of concurrent release info lookups.")
+	whichCmd.PersistentFlags().DurationVar(&whichTimeout, "timeout", 10*time.Minute, "Maximum amount of time to spend searching.")
+	whichCmd.PersistentFlags().StringVar(&whichAuthfile, "authfile", "", "Path to a registry auth file, used to resolve tagged image pullspecs to digests.")
+	whichCmd.RegisterFlagCompletionFunc("stream", completeReleaseStreams)
+
+	mirrorOpts := mirror.Opts{}
+	var mirrorManifestsDir string
+	var mirrorTimeout time.Duration
+
+	mirrorCmd := &cobra.Command{
+		Use:   "mirror [tag name or pullspec]",
+		Short: "Mirrors a release payload and all of its component images to a registry or OCI layout directory.",
+		Args:  cobra.ExactArgs(1),
+		Example: `
+	# Mirrors a release to a registry and writes ImageDigestMirrorSet / ImageContentSourcePolicy manifests to the current directory.
+	rcctl release mirror '4.21.4-x86_64' --to 'registry.example.com/ocp/release'
+
+	# Mirrors a release to a local insecure registry with a higher concurrency.
+	rcctl release mirror 'quay.io/openshift-release-dev/ocp-release:4.21.4-x86_64' --to 'localhost:5000/ocp/release' --dest-insecure --concurrency 20
+
+	# Mirrors a release to an OCI layout directory.
+	rcctl release mirror '4.21.4-x86_64' --to 'oci:/path/to/dir'`,
+		ValidArgsFunction: completeReleaseTagArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if mirrorOpts.To == "" {
+				return fmt.Errorf("--to must be provided")
+			}
+
+			return doReleaseControllerOpWithTimeout(mirrorTimeout, func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+				ri, err := releasecontroller.NewReleaseInfoFetcher(rc).GetReleaseInfo(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+
+				result, err := mirror.Release(ctx, ri, mirrorOpts)
+				if err != nil {
+					return nil, err
+				}
+
+				if containers.IsOCILayoutReference(mirrorOpts.To) {
+					return result, nil
+				}
+
+				return result, writeMirrorSets(mirrorManifestsDir, result)
+			})
+		},
+	}
+
+	mirrorCmd.PersistentFlags().StringVar(&mirrorOpts.To, "to", "", "Registry repository (registry.host/org/repo) or OCI layout directory (oci:path) to mirror to.")
+	mirrorCmd.PersistentFlags().IntVar(&mirrorOpts.Concurrency, "concurrency", 10, "Maximum number of images to copy concurrently.")
+	mirrorCmd.PersistentFlags().StringVar(&mirrorOpts.SourceAuthfilePath, "authfile", "", "Path to a registry auth file for pulling the release images.")
+	mirrorCmd.PersistentFlags().StringVar(&mirrorOpts.DestAuthfilePath, "dest-authfile", "", "Path to a registry auth file for pushing to the mirror registry.")
+	mirrorCmd.PersistentFlags().BoolVar(&mirrorOpts.DestInsecure, "dest-insecure", false, "Allow pushing to a mirror registry over plain HTTP or with an untrusted certificate.")
+	mirrorCmd.PersistentFlags().StringVar(&mirrorManifestsDir, "manifests-dir", ".", "Directory to write the ImageDigestMirrorSet and ImageContentSourcePolicy manifests to.")
+	mirrorCmd.PersistentFlags().DurationVar(&mirrorTimeout, "timeout", 2*time.Hour, "Maximum amount of time to spend mirroring.")
+
+	archesOpts := multiarch.Opts{}
+	var archesTimeout time.Duration
+
+	archesCmd := &cobra.Command{
+		Use:   "arches [tag name or pullspec]",
+		Short: "Shows the per-architecture digests of a multi-arch release payload and flags components which are missing an architecture.",
+		Args:  cobra.ExactArgs(1),
+		Example: `
+	# Shows the architectures of a multi-arch release payload and each of its components.
+	rcctl release arches 'quay.io/openshift-release-dev/ocp-release:4.21.4-multi'
+
+	# Shows the architectures of a multi-arch nightly.
+	rcctl --controller 'multi.ocp.releases.ci.openshift.org' release arches '4.23.0-0.nightly-multi-2026-03-05-153752'`,
+		ValidArgsFunction: completeReleaseTagArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doReleaseControllerOpWithTimeout(archesTimeout, func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+				ri, err := releasecontroller.NewReleaseInfoFetcher(rc).GetReleaseInfo(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+
+				return multiarch.Inspect(ctx, ri, archesOpts)
+			})
+		},
+	}
+
+	archesCmd.PersistentFlags().StringVar(&archesOpts.AuthfilePath, "authfile", "", "Path to a registry auth file.")
+	archesCmd.PersistentFlags().IntVar(&archesOpts.Concurrency, "concurrency", 10, "Maximum number of component images to inspect concurrently.")
+	archesCmd.PersistentFlags().DurationVar(&archesTimeout, "timeout", 10*time.Minute, "Maximum amount of time to spend inspecting images.")
+
+	verifyOpts := signature.Opts{}
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify [pullspec]",
+		Short: "Verifies the signature of a release payload.",
+		Args:  cobra.ExactArgs(1),
+		Example: `
+	# Verifies an OpenShift release payload signature from mirror.openshift.com using the given GPG public key.
+	rcctl release verify 'quay.io/openshift-release-dev/ocp-release:4.21.4-x86_64' --public-key ./redhat-release-key.asc
+
+	# Verifies a release payload signature against a local signature store directory.
+	rcctl release verify 'quay.io/openshift-release-dev/ocp-release@sha256:...' --public-key ./key.asc --signature-store ./signatures
+
+	# Verifies a sigstore signature attached to the release payload image.
+	rcctl release verify 'registry.example.com/ocp/release:4.21.4' --mode sigstore --public-key ./cosign.pub`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doReleaseControllerOp(func(ctx context.Context, _ *releasecontroller.ReleaseController) (interface{}, error) {
+				return signature.Verify(ctx, args[0], verifyOpts)
+			})
+		},
+	}
+
+	verifyCmd.PersistentFlags().StringVar((*string)(&verifyOpts.Mode), "mode", string(signature.SimpleSigningMode), fmt.Sprintf("Kind of signature to verify, one of: %s, %s", signature.SimpleSigningMode, signature.SigstoreMode))
+	verifyCmd.PersistentFlags().StringVar(&verifyOpts.PublicKeyPath, "public-key", "", "Path to the GPG public keyring (simple-signing) or PEM public key (sigstore) to verify with.")
+	verifyCmd.PersistentFlags().StringVar(&verifyOpts.SignatureStore, "signature-store", "", fmt.Sprintf("Signature store URL or directory for simple-signing (default %q), or repository to read the image and its attached signatures from for sigstore.", signature.DefaultSignatureStore))
+	verifyCmd.PersistentFlags().StringVar(&verifyOpts.AuthfilePath, "authfile", "", "Path to a registry auth file.")
+	verifyCmd.PersistentFlags().BoolVar(&verifyOpts.Insecure, "insecure", false, "Allow talking to the registry over plain HTTP or with an untrusted TLS certificate.")
+
+	var identifyAuthfile string
+
+	identifyCmd := &cobra.Command{
+		Use:   "identify [pullspec]",
+		Short: "Identifies which release controller, releasestream, tag and phase a release payload pullspec belongs to (requires oc).",
+		Args:  cobra.ExactArgs(1),
+		Example: `
+	# Identifies a digested release payload pullspec, searching all release controllers.
+	rcctl release identify 'quay.io/openshift-release-dev/ocp-release@sha256:...'
+
+	# Identifies a release payload pullspec on a specific release controller.
+	rcctl --controller 'arm64.ocp.releases.ci.openshift.org' release identify 'registry.ci.openshift.org/ocp-arm64/release-arm64@sha256:...'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doReleaseControllerOpWithTimeout(2*time.Minute, func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+				opts := releasecontroller.ResolveOpts{
+					AuthfilePath: identifyAuthfile,
+				}
+
+				// Only search the given release controller when one was
+				// explicitly provided.
+				if cmd.Flags().Changed("controller") {
+					opts.Controllers = []*releasecontroller.ReleaseController{rc}
+				}
+
+				return releasecontroller.Identify(ctx, args[0], opts)
+			})
+		},
+	}
+
+	identifyCmd.PersistentFlags().StringVar(&identifyAuthfile, "authfile", "", "Path to a registry auth file.")
+
+	manifestsFilter := manifests.Filter{}
+	var manifestsTo string
+	var manifestsAuthfile string
+
+	manifestsCmd := &cobra.Command{
+		Use:   "manifests [tag name or pullspec]",
+		Short: "Lists or extracts the release-manifests shipped in a release payload without requiring oc.",
+		Args:  cobra.ExactArgs(1),
+		Example: `
+	# Lists all of the manifests in a release payload.
+	rcctl release manifests '4.23.0-0.nightly-2026-03-05-153752'
+
+	# Extracts the machine-config-operator manifests to a directory.
+	rcctl release manifests '4.23.0-0.nightly-2026-03-05-153752' --component machine-config-operator --to ./manifests
+
+	# Extracts all CustomResourceDefinitions whose names end with openshift.io.
+	rcctl release manifests 'quay.io/openshift-release-dev/ocp-release:4.21.4-x86_64' --kind CustomResourceDefinition --name '*.openshift.io' --to ./crds`,
+		ValidArgsFunction: completeReleaseTagArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doReleaseControllerOpWithTimeout(10*time.Minute, func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+				selected, err := readReleaseManifests(ctx, rc, args[0], manifestsAuthfile, manifestsFilter)
+				if err != nil {
+					return nil, err
+				}
+
+				if manifestsTo == "" {
+					return selected, nil
+				}
+
+				if err := manifests.Write(manifestsTo, selected); err != nil {
+					return nil, err
+				}
+
+				fmt.Fprintf(os.Stderr, "Wrote %d manifests to %s\n", len(selected), manifestsTo)
+
+				return selected, nil
+			})
+		},
+	}
+
+	manifestsCmd.PersistentFlags().StringVar(&manifestsTo, "to", "", "Directory to extract the selected manifests to. When not given, the manifests are only listed.")
+	addManifestsFilterFlags(manifestsCmd, &manifestsFilter, &manifestsAuthfile)
+
+	grepFilter := manifests.Filter{}
+	var grepAuthfile string
+	var grepIgnoreCase bool
+
+	grepCmd := &cobra.Command{
+		Use:   "grep [tag name or pullspec] [pattern]",
+		Short: "Searches the release-manifests shipped in a release payload for a regular expression without requiring oc.",
+		Args:  cobra.ExactArgs(2),
+		Example: `
+	# Finds every manifest which refers to the machine-config-daemon.
+	rcctl release grep '4.23.0-0.nightly-2026-03-05-153752' 'machine-config-daemon'
+
+	# Searches only the ConfigMaps shipped by the machine-config-operator, ignoring case.
+	rcctl release grep 'quay.io/openshift-release-dev/ocp-release:4.21.4-x86_64' 'osimageurl' -i --component machine-config-operator --kind ConfigMap`,
+		ValidArgsFunction: completeReleaseTagArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			expr := args[1]
+			if grepIgnoreCase {
+				expr = "(?i)" + expr
+			}
+
+			pattern, err := regexp.Compile(expr)
+			if err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", args[1], err)
+			}
+
+			return doReleaseControllerOpWithTimeout(10*time.Minute, func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+				selected, err := readReleaseManifests(ctx, rc, args[0], grepAuthfile, grepFilter)
+				if err != nil {
+					return nil, err
+				}
+
+				return manifests.Grep(selected, pattern), nil
+			})
+		},
+	}
+
+	grepCmd.PersistentFlags().BoolVarP(&grepIgnoreCase, "ignore-case", "i", false, "Match the pattern case-insensitively.")
+	addManifestsFilterFlags(grepCmd, &grepFilter, &grepAuthfile)
+
+	diffFilter := manifests.Filter{}
+	var diffAuthfile string
+
+	manifestsDiffCmd := &cobra.Command{
+		Use:   "manifests-diff [from tag name or pullspec] [to tag name or pullspec]",
+		Short: "Shows which release-manifests objects were added, removed, or changed between two release payloads without requiring oc.",
+		Args:  cobra.ExactArgs(2),
+		Example: `
+	# Shows how the manifests changed between two nightlies.
+	rcctl release manifests-diff '4.23.0-0.nightly-2026-03-04-153752' '4.23.0-0.nightly-2026-03-05-153752' -o table
+
+	# Shows only how the machine-config-operator manifests changed between two z-stream releases.
+	rcctl release manifests-diff 'quay.io/openshift-release-dev/ocp-release:4.21.3-x86_64' 'quay.io/openshift-release-dev/ocp-release:4.21.4-x86_64' --component machine-config-operator`,
+		ValidArgsFunction: completeReleaseTagArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doReleaseControllerOpWithTimeout(10*time.Minute, func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+				read := make([][]manifests.Manifest, len(args))
+
+				g, gctx := errgroup.WithContext(ctx)
+
+				for i, arg := range args {
+					g.Go(func() error {
+						selected, err := readReleaseManifests(gctx, rc, arg, diffAuthfile, diffFilter)
+						if err != nil {
+							return fmt.Errorf("could not read manifests for %q: %w", arg, err)
+						}
+
+						read[i] = selected
+						return nil
+					})
+				}
+
+				if err := g.Wait(); err != nil {
+					return nil, err
+				}
+
+				result := manifests.Diff(read[0], read[1])
+				result.From = args[0]
+				result.To = args[1]
+
+				return result, nil
+			})
+		},
+	}
+
+	addManifestsFilterFlags(manifestsDiffCmd, &diffFilter, &diffAuthfile)
+
+	var featureGatesDiff string
+	var featureGatesProfile string
+	var featureGatesAuthfile string
+
+	featureGatesCmd := &cobra.Command{
+		Use:   "featuregates [tag name or pullspec]",
+		Short: "Shows which feature gates are enabled in each feature set of a release payload without requiring oc.",
+		Args:  cobra.ExactArgs(1),
+		Example: `
+	# Shows which feature gates are enabled in each feature set.
+	rcctl release featuregates '4.23.0-0.nightly-2026-03-05-153752' -o table
+
+	# Shows which feature gates flipped between two releases.
+	rcctl release featuregates '4.23.0-0.nightly-2026-03-05-153752' --diff '4.22.0-0.nightly-2026-03-05-153752' -o table
+
+	# Shows whether OnClusterBuild is enabled by default.
+	rcctl release featuregates '4.23.0-0.nightly-2026-03-05-153752' -o jsonpath='{.gates[?(@.name=="OnClusterBuild")].states.Default}'`,
+		ValidArgsFunction: completeReleaseTagArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doReleaseControllerOpWithTimeout(10*time.Minute, func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+				releases := []string{args[0]}
+				if featureGatesDiff != "" {
+					// The release given with --diff is the older one.
+					releases = []string{featureGatesDiff, args[0]}
+				}
+
+				matrices := make([]*manifests.FeatureGateMatrix, len(releases))
+
+				g, gctx := errgroup.WithContext(ctx)
+
+				for i, release := range releases {
+					g.Go(func() error {
+						featureGates, err := readReleaseManifests(gctx, rc, release, featureGatesAuthfile, manifests.Filter{Kinds: []string{"FeatureGate"}})
+						if err != nil {
+							return fmt.Errorf("could not read manifests for %q: %w", release, err)
+						}
+
+						matrix, err := manifests.FeatureGates(featureGates, featureGatesProfile)
+						if err != nil {
+							return fmt.Errorf("could not get feature gates for %q: %w", release, err)
+						}
+
+						matrix.Release = release
+						matrices[i] = matrix
+						return nil
+					})
+				}
+
+				if err := g.Wait(); err != nil {
+					return nil, err
+				}
+
+				if featureGatesDiff == "" {
+					return matrices[0], nil
+				}
+
+				return manifests.DiffFeatureGates(matrices[0], matrices[1]), nil
+			})
+		},
+	}
+
+	featureGatesCmd.PersistentFlags().StringVar(&featureGatesDiff, "diff", "", "Tag name or pullspec of an older release to show which feature gates flipped since.")
+	featureGatesCmd.PersistentFlags().StringVar(&featureGatesProfile, "cluster-profile", manifests.DefaultClusterProfile, "Cluster profile to show the feature gates for (e.g., ibm-cloud-managed for HyperShift).")
+	featureGatesCmd.PersistentFlags().StringVar(&featureGatesAuthfile, "authfile", "", "Path to a registry auth file.")
+	featureGatesCmd.RegisterFlagCompletionFunc("diff", completeReleaseTagFlag)
+
+	var componentsRepo string
+
+	componentsCmd := &cobra.Command{
+		Use:   "components [tag name or pullspec]",
+		Short: "Shows the source repository and commit each component image in a release was built from (requires oc).",
+		Args:  cobra.ExactArgs(1),
+		Example: `
+	# Shows the source repository and commit for each component image.
+	rcctl release components '4.23.0-0.nightly-2026-03-05-153752' -o table
+
+	# Finds the component images built from the machine-config-operator repository.
+	rcctl release components '4.23.0-0.nightly-2026-03-05-153752' --repo 'openshift/machine-config-operator' -o table`,
+		ValidArgsFunction: completeReleaseTagArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doReleaseControllerOp(func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+				ri, err := releasecontroller.NewReleaseInfoFetcher(rc).GetReleaseInfo(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+
+				if componentsRepo == "" {
+					return ri.Components(), nil
+				}
+
+				found := ri.ComponentsFromRepo(componentsRepo)
+				if len(found) == 0 {
+					cmd.SilenceUsage = true
+					return nil, fmt.Errorf("no components in %q were built from %q", args[0], componentsRepo)
+				}
+
+				return found, nil
+			})
+		},
+	}
+
+	componentsCmd.PersistentFlags().StringVar(&componentsRepo, "repo", "", "Only show the components built from the given source repository (e.g., openshift/machine-config-operator).")
+
+	var sizeDiff string
+	var sizeTop int
+
+	sizeCmd := &cobra.Command{
+		Use:   "size [tag name or pullspec]",
+		Short: "Shows the compressed size of a release payload and its largest component images (requires oc and skopeo).",
+		Args:  cobra.ExactArgs(1),
+		Example: `
+	# Shows the 20 largest component images and the total unique size of the payload.
+	rcctl release size '4.21.4-x86_64' -o table
+
+	# Shows which component images grew the most since an older release.
+	rcctl release size '4.21.4-x86_64' --diff '4.21.3-x86_64' -o table`,
+		ValidArgsFunction: completeReleaseTagArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doReleaseControllerOpWithTimeout(10*time.Minute, func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+				releases := []string{args[0]}
+				if sizeDiff != "" {
+					// The release given with --diff is the older one.
+					releases = []string{sizeDiff, args[0]}
+				}
+
+				sizes := make([]*releasecontroller.PayloadSize, len(releases))
+
+				g, gctx := errgroup.WithContext(ctx)
+
+				for i, release := range releases {
+					g.Go(func() error {
+						size, err := releasecontroller.NewReleaseInfoFetcher(rc).GetPayloadSize(gctx, release)
+						if err != nil {
+							return fmt.Errorf("could not get payload size for %q: %w", release, err)
+						}
+
+						sizes[i] = size
+						return nil
+					})
+				}
+
+				if err := g.Wait(); err != nil {
+					return nil, err
+				}
+
+				if sizeDiff == "" {
+					if sizeTop > 0 && len(sizes[0].Components) > sizeTop {
+						sizes[0].Components = sizes[0].Components[:sizeTop]
+					}
+
+					return sizes[0], nil
+				}
+
+				diff := releasecontroller.DiffPayloadSizes(sizes[0], sizes[1])
+				if sizeTop > 0 && len(diff.Components) > sizeTop {
+					diff.Components = diff.Components[:sizeTop]
+				}
+
+				return diff, nil
+			})
+		},
+	}
+
+	sizeCmd.PersistentFlags().StringVar(&sizeDiff, "diff", "", "Tag name or pullspec of an older release to compare component image sizes against.")
+	sizeCmd.PersistentFlags().IntVar(&sizeTop, "top", 20, "Number of component images to list, largest (or with --diff, most grown) first. Totals always include every component image. Use 0 to list all of them.")
+	sizeCmd.RegisterFlagCompletionFunc("diff", completeReleaseTagFlag)
+
+	crossArchCmd := &cobra.Command{
+		Use:   "cross-arch [version]",
+		Short: "Compares the same release version (not a nightly or CI build) across the release controllers for every architecture.",
+		Args:  cobra.ExactArgs(1),
+		Example: `
+	# Shows the phase, pullspec, creation time and blocking jobs of 4.21.4 for each architecture.
+	rcctl release cross-arch '4.21.4' -o table`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Every release controller is always queried, so --controller is
+			// not used here.
+			return doReleaseControllerOpWithTimeout(2*time.Minute, func(ctx context.Context, _ *releasecontroller.ReleaseController) (interface{}, error) {
+				comparison, err := releasecontroller.CompareAcrossArchitectures(ctx, args[0], nil)
+				if err != nil {
+					cmd.SilenceUsage = true
+					return nil, err
+				}
+
+				for _, mismatch := range comparison.MismatchedComponents {
+					commits := []string{}
+					for _, arch := range sets.List(sets.KeySet(mismatch.Commits)) {
+						commits = append(commits, fmt.Sprintf("%s=%s", arch, mismatch.Commits[arch]))
+					}
+
+					klog.Warningf("Component %q was built from different commits: %s", mismatch.Name, strings.Join(commits, ", "))
+				}
+
+				return comparison, nil
+			})
+		},
+	}
+
+	downloadOpts := releasecontroller.DownloadOpts{}
+	var downloadTools []string
+
+	downloadCmd := &cobra.Command{
+		Use:   "download [tag name or release payload pullspec]",
+		Short: "Downloads, verifies and unpacks the client tools (oc, openshift-install, ccoctl) for a release.",
+		Args:  cobra.ExactArgs(1),
+		Example: `
+	# Downloads oc for the current OS and architecture into the current directory.
+	rcctl release download '4.21.4'
+
+	# Downloads oc and openshift-install for linux/arm64 into ./bin.
+	rcctl release download '4.21.4' --tool oc --tool openshift-install --os linux --arch arm64 --to ./bin
+
+	# Extracts ccoctl from the release payload images instead of the download page.
+	rcctl release download '4.23.0-0.nightly-2026-03-05-153752' --tool ccoctl --from-payload
+
+	# Extracts oc from a release payload image without using the release controller.
+	rcctl release download 'quay.io/openshift-release-dev/ocp-release:4.21.4-x86_64' --tool oc --os linux --arch amd64`,
+		ValidArgsFunction: completeReleaseTagArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doReleaseControllerOpWithTimeout(30*time.Minute, func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+				downloadOpts.Tools = []releasecontroller.Tool{}
+				for _, tool := range downloadTools {
+					downloadOpts.Tools = append(downloadOpts.Tools, releasecontroller.Tool(tool))
+				}
+
+				cmd.SilenceUsage = true
+				return rc.DownloadTools(ctx, args[0], downloadOpts)
+			})
+		},
+	}
+
+	downloadCmd.PersistentFlags().StringSliceVar(&downloadTools, "tool", []string{string(releasecontroller.OcTool)}, fmt.Sprintf("Client tool(s) to download, any of: %v", releasecontroller.Tools()))
+	downloadCmd.PersistentFlags().StringVar(&downloadOpts.OS, "os", runtime.GOOS, "Operating system to download the client tools for, one of: linux, mac (or darwin), windows.")
+	downloadCmd.PersistentFlags().StringVar(&downloadOpts.Arch, "arch", runtime.GOARCH, "Architecture to download the client tools for, e.g., amd64 or arm64.")
+	downloadCmd.PersistentFlags().StringVar(&downloadOpts.Dir, "to", ".", "Directory to unpack the client tools into.")
+	downloadCmd.PersistentFlags().BoolVar(&downloadOpts.FromPayload, "from-payload", false, "Extract the client tools from the release payload images instead of the release controller download page (linux only).")
+	downloadCmd.PersistentFlags().StringVar(&downloadOpts.AuthfilePath, "authfile", "", "Path to a registry auth file, used when extracting the client tools from the release payload images.")
+	downloadCmd.RegisterFlagCompletionFunc("tool", cobra.FixedCompletions(releasecontroller.Tools(), cobra.ShellCompDirectiveNoFileComp))
+
 	releaseCmd.AddCommand(ocInfoCmd)
 	releaseCmd.AddCommand(infoCmd)
+	releaseCmd.AddCommand(machineOSCmd)
+	releaseCmd.AddCommand(whichCmd)
+	releaseCmd.AddCommand(mirrorCmd)
+	releaseCmd.AddCommand(verifyCmd)
+	releaseCmd.AddCommand(archesCmd)
+	releaseCmd.AddCommand(identifyCmd)
+	releaseCmd.AddCommand(manifestsCmd)
+	releaseCmd.AddCommand(grepCmd)
+	releaseCmd.AddCommand(manifestsDiffCmd)
+	releaseCmd.AddCommand(featureGatesCmd)
+	releaseCmd.AddCommand(componentsCmd)
+	releaseCmd.AddCommand(sizeCmd)
+	releaseCmd.AddCommand(crossArchCmd)
+	releaseCmd.AddCommand(downloadCmd)
 
 	return releaseCmd
 }
 
+// Writes the ImageDigestMirrorSet and ImageContentSourcePolicy manifests
+// describing the mirror into the given directory.
+func writeMirrorSets(dir string, result *mirror.Result) error {
+	name := mirror.MirrorSetName(result.To)
+
+	idms, err := mirror.ImageDigestMirrorSet(name, result)
+	if err != nil {
+		return err
+	}
+
+	icsp, err := mirror.ImageContentSourcePolicy(name, result)
+	if err != nil {
+		return err
+	}
+
+	toWrite := map[string]interface{}{
+		"imagedigestmirrorset.yaml":     idms,
+		"imagecontentsourcepolicy.yaml": icsp,
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for filename, obj := range toWrite {
+		out, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(dir, filename)
+		if err := os.WriteFile(path, out, 0o644); err != nil {
+			return fmt.Errorf("could not write %s: %w", path, err)
+		}
+
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+	}
+
+	return nil
+}
+
+func addManifestsFilterFlags(cmd *cobra.Command, filter *manifests.Filter, authfile *string) {
+	cmd.PersistentFlags().StringSliceVar(&filter.Components, "component", []string{}, "Only include manifests shipped by the given components (e.g., machine-config-operator).")
+	cmd.PersistentFlags().StringSliceVar(&filter.Kinds, "kind", []string{}, "Only include manifests of the given kinds (e.g., ConfigMap).")
+	cmd.PersistentFlags().StringSliceVar(&filter.Names, "name", []string{}, "Only include manifests whose names match the given glob patterns (e.g., 'machine-config-*').")
+	cmd.PersistentFlags().StringVar(authfile, "authfile", "", "Path to a registry auth file.")
+}
+
+// Reads the release-manifests from the release payload for the given tag or
+// pullspec and selects those matched by the filter.
+func readReleaseManifests(ctx context.Context, rc *releasecontroller.ReleaseController, tagOrPullspec, authfile string, filter manifests.Filter) ([]manifests.Manifest, error) {
+	pullspec, err := releasecontroller.NewReleaseInfoFetcher(rc).GetPullspec(ctx, tagOrPullspec)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := manifests.Read(ctx, pullspec, authfile)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifests.Select(all, filter), nil
+}
+
 func init() {
 	rootCmd.AddCommand(releaseCmd())
 }
@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
 )
 
 func releaseStreamsNamesCmd() *cobra.Command {
@@ -71,6 +74,7 @@ func releaseStreamsNamesCmd() *cobra.Command {
 	}
 
 	for _, cmd := range cmds {
+		cmd.ValidArgsFunction = completeReleaseStreams
 		namesCmd.AddCommand(cmd)
 	}
 
@@ -79,8 +83,9 @@ func releaseStreamsNamesCmd() *cobra.Command {
 
 func releaseStreamCmd() *cobra.Command {
 	rsCmd := &cobra.Command{
-		Use:   "releasestreams",
-		Short: "Query releasestreams",
+		Use:     "releasestreams",
+		Aliases: []string{"stream"},
+		Short:   "Query releasestreams",
 	}
 
 	rsListCmd := &cobra.Command{
@@ -94,9 +99,10 @@ func releaseStreamCmd() *cobra.Command {
 	}
 
 	rsConfigCmd := &cobra.Command{
-		Use:   "config [releasestream]",
-		Short: "Shows the configuration for the given releasestream",
-		Args:  cobra.ExactArgs(1),
+		Use:               "config [releasestream]",
+		Short:             "Shows the configuration for the given releasestream",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeReleaseStreamArg,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return doReleaseControllerOp(func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
 				return rc.ReleaseStream(args[0]).Config(ctx)
@@ -107,10 +113,94 @@ func releaseStreamCmd() *cobra.Command {
 	rsCmd.AddCommand(rsListCmd)
 	rsCmd.AddCommand(releaseStreamsNamesCmd())
 	rsCmd.AddCommand(rsConfigCmd)
+	rsCmd.AddCommand(releaseStreamHealthCmd())
 
 	return rsCmd
 }
 
+func releaseStreamHealthCmd() *cobra.Command {
+	opts := releasecontroller.StreamHealthOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "health [releasestream...]",
+		Short: "Reports how stale each releasestream is and how often its releases are accepted.",
+		Example: `
+	# Reports the health of all releasestreams.
+	rcctl stream health
+
+	# Reports the health of the given releasestreams over their last 50 releases.
+	rcctl stream health '4.23.0-0.nightly' '4.23.0-0.ci' --window 50
+
+	# Exits non-zero if either releasestream has not had a release accepted within the last 12 hours.
+	rcctl stream health '4.23.0-0.nightly' '4.23.0-0.ci' --max-age 12h -o table`,
+		ValidArgsFunction: completeReleaseStreams,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var results []*releasecontroller.StreamHealth
+
+			err := doReleaseControllerOpWithTimeout(5*time.Minute, func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+				var err error
+				results, err = getStreamHealth(ctx, rc, args, opts)
+				return results, err
+			})
+
+			if err != nil {
+				return err
+			}
+
+			stale := []string{}
+			for _, result := range results {
+				if result.Stale {
+					stale = append(stale, result.Stream)
+				}
+			}
+
+			if len(stale) != 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("releasestream(s) have not had a release accepted within %s: %v", opts.MaxAge, stale)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().IntVar(&opts.Window, "window", 20, "Number of most recent accepted or rejected releases to consider.")
+	cmd.PersistentFlags().DurationVar(&opts.MaxAge, "max-age", 0, "Exit non-zero if any releasestream has not had a release accepted within this long.")
+
+	return cmd
+}
+
+// Gets the health of the given releasestreams, or of all releasestreams if
+// none are given.
+func getStreamHealth(ctx context.Context, rc *releasecontroller.ReleaseController, streams []string, opts releasecontroller.StreamHealthOpts) ([]*releasecontroller.StreamHealth, error) {
+	if len(streams) == 0 {
+		all, err := newReleaseStreamsHelper(rc).AllReleaseStreamNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		streams = all
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(5)
+
+	out := make([]*releasecontroller.StreamHealth, len(streams))
+
+	for i, stream := range streams {
+		g.Go(func() error {
+			health, err := rc.ReleaseStream(stream).Health(ctx, opts)
+			if err != nil {
+				return err
+			}
+
+			out[i] = health
+			return nil
+		})
+	}
+
+	return out, g.Wait()
+}
+
 func init() {
 	rootCmd.AddCommand(releaseStreamCmd())
 }
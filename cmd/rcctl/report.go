@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/report"
+	"github.com/spf13/cobra"
+	"k8s.io/klog"
+)
+
+func reportCmd() *cobra.Command {
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Generates reports about releasestreams.",
+	}
+
+	opts := report.Opts{}
+	var outDir string
+	var timeout time.Duration
+
+	htmlCmd := &cobra.Command{
+		Use:   "html",
+		Short: "Renders a self-contained static HTML site showing the latest releases in the given releasestreams.",
+		Long: `
+Renders a self-contained static HTML site showing the latest releases in the
+given releasestreams along with their phases, blocking job results, upgrade
+results and links to their changelogs. The site has no external dependencies,
+so the output directory can be published as-is to any web server, e.g., from a
+cron job.`,
+		Args: cobra.NoArgs,
+		Example: `
+	# Renders a report for the given releasestreams into ./site.
+	rcctl report html --streams '4.23.0-0.nightly,4.23.0-0.ci' --out ./site
+
+	# Renders a report including the last 25 releases in each releasestream.
+	rcctl report html --streams '4.23.0-0.nightly' --tags 25 --out /var/www/html/releases`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if len(opts.Streams) == 0 {
+				return fmt.Errorf("at least one releasestream must be given with --streams")
+			}
+
+			if outDir == "" {
+				return fmt.Errorf("--out must be given")
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			rc, err := getReleaseControllerForLinks(ctx)
+			if err != nil {
+				return err
+			}
+
+			site, err := report.Collect(ctx, rc, opts)
+			if err != nil {
+				return err
+			}
+
+			if err := report.Render(site, outDir); err != nil {
+				return err
+			}
+
+			klog.Infof("Wrote report to %s", filepath.Join(outDir, "index.html"))
+
+			return nil
+		},
+	}
+
+	htmlCmd.PersistentFlags().StringSliceVar(&opts.Streams, "streams", []string{}, "Releasestream(s) to include in the report.")
+	htmlCmd.PersistentFlags().StringVar(&outDir, "out", "", "Directory to write the report into.")
+	htmlCmd.PersistentFlags().IntVar(&opts.Tags, "tags", 10, "Number of most recent releases to include for each releasestream.")
+	htmlCmd.PersistentFlags().IntVar(&opts.Concurrency, "concurrency", 5, "Maximum number of releases to get the job results for at once.")
+	htmlCmd.PersistentFlags().DurationVar(&timeout, "timeout", 10*time.Minute, "Maximum amount of time to spend generating the report.")
+
+	htmlCmd.RegisterFlagCompletionFunc("streams", completeReleaseStreams)
+	htmlCmd.MarkPersistentFlagDirname("out")
+
+	reportCmd.AddCommand(htmlCmd)
+
+	return reportCmd
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd())
+}
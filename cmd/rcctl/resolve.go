@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/spf13/cobra"
+)
+
+func resolveCmd() *cobra.Command {
+	var authfile string
+
+	cmd := &cobra.Command{
+		Use:   "resolve [minor version, releasestream, tag name or pullspec]",
+		Short: "Resolves a release given in any form to its tag, releasestream, release controller, phase and pullspecs.",
+		Args:  cobra.ExactArgs(1),
+		Example: `
+	# Resolves the latest accepted 4.18 z-stream release.
+	rcctl resolve '4.18'
+
+	# Resolves the latest accepted release in a given releasestream.
+	rcctl resolve '4.23.0-0.nightly'
+
+	# Resolves a release tag, searching all release controllers.
+	rcctl resolve '4.23.0-0.nightly-arm64-2026-03-05-153752'
+
+	# Resolves a release tag on a specific release controller.
+	rcctl --controller 'arm64.ocp.releases.ci.openshift.org' resolve '4.23.0-0.nightly-arm64-2026-03-05-153752'
+
+	# Resolves a tagged pullspec to its digest.
+	rcctl resolve 'quay.io/openshift-release-dev/ocp-release:4.21.4-x86_64'`,
+		ValidArgsFunction: completeReleaseTagArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doReleaseControllerOpWithTimeout(2*time.Minute, func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+				opts := releasecontroller.ResolveOpts{
+					AuthfilePath: authfile,
+				}
+
+				// Only search the given release controller when one was
+				// explicitly provided.
+				if cmd.Flags().Changed("controller") {
+					opts.Controllers = []*releasecontroller.ReleaseController{rc}
+				}
+
+				return releasecontroller.Resolve(ctx, args[0], opts)
+			})
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&authfile, "authfile", "", "Path to a registry auth file, used to resolve tagged pullspecs to digests.")
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(resolveCmd())
+}
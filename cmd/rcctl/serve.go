@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/proxy"
+	"github.com/spf13/cobra"
+	"k8s.io/klog"
+)
+
+func serveCmd() *cobra.Command {
+	var listen string
+	opts := proxy.ServerOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serves a caching proxy for the release controller so that many clients can share one cache.",
+		Long: `
+Serves the same endpoints as the release controller given with --controller
+from an in-memory cache which is refreshed in the background. Point other rcctl
+invocations (or anything else which talks to a release controller) at it with
+--controller http://<host>:<port>.
+
+In addition to the release controller endpoints, the following are served:
+
+  /rcctl/v1/latest    The latest accepted release tag for each releasestream.
+  /rcctl/v1/upstream  The release controller being cached.
+  /healthz            Returns ok while the proxy is running.
+  /metrics            Prometheus metrics for the proxy and its cache.
+
+Responses include an X-Rcctl-Cache header of hit, miss, bypass or error.`,
+		Args: cobra.NoArgs,
+		Example: `
+	# Serves a cache of the amd64 OCP release controller on port 8080.
+	rcctl serve --listen :8080
+
+	# Uses the cache.
+	rcctl --controller http://localhost:8080 tags latest '4-stable'`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if opts.RefreshInterval <= 0 {
+				return fmt.Errorf("--refresh must be positive")
+			}
+
+			rc, err := getReleaseController()
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			srv := proxy.NewServer(rc, opts)
+
+			httpServer := &http.Server{
+				Addr:              listen,
+				Handler:           srv,
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			go func() {
+				<-ctx.Done()
+
+				shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer shutdownCancel()
+
+				if err := httpServer.Shutdown(shutdownCtx); err != nil {
+					klog.Warningf("Could not shut down cleanly: %s", err)
+				}
+			}()
+
+			go func() {
+				//nolint:errcheck // Run only returns once the context is done.
+				srv.Run(ctx)
+			}()
+
+			klog.Infof("Serving a cache of %s on %s, refreshing every %s", rc, listen, opts.RefreshInterval)
+
+			if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&listen, "listen", ":8080", "Address to listen on.")
+	cmd.PersistentFlags().DurationVar(&opts.RefreshInterval, "refresh", time.Minute, "How often to refresh the cached responses which were requested since the previous refresh.")
+	cmd.PersistentFlags().DurationVar(&opts.EvictAfter, "evict-after", time.Hour, "Evict cached responses which have not been requested for this long. Use 0 to never evict them.")
+	cmd.PersistentFlags().IntVar(&opts.MaxEntries, "max-entries", 10000, "Maximum number of cached responses; the least recently requested is evicted to make room. Use 0 for no limit.")
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd())
+}
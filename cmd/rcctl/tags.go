@@ -83,6 +83,7 @@ func tagsCmd() *cobra.Command {
 	}
 
 	for _, cmd := range cmds {
+		cmd.ValidArgsFunction = completeReleaseStreamArg
 		tagsCmd.AddCommand(cmd)
 	}
 
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/cincinnati"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/spf13/cobra"
+)
+
+func upgradesCmd() *cobra.Command {
+	var channel string
+	var from string
+	var arch string
+	var graphURL string
+
+	cmd := &cobra.Command{
+		Use:   "upgrades",
+		Short: "Lists the recommended and conditional upgrade targets for a release from the OpenShift Update Service.",
+		Args:  cobra.NoArgs,
+		Example: `
+	# Lists the upgrade targets from 4.17.10 in the stable-4.18 channel.
+	rcctl upgrades --channel 'stable-4.18' --from '4.17.10'
+
+	# Lists the upgrade targets for arm64 clusters.
+	rcctl upgrades --channel 'stable-4.18' --from '4.17.10' --arch 'arm64'
+
+	# Lists the upgrade targets from a different update service.
+	rcctl upgrades --channel 'stable-4.18' --from '4.17.10' --graph-url 'https://osus.example.com/api/upgrades_info/graph'`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return doReleaseControllerOp(func(ctx context.Context, _ *releasecontroller.ReleaseController) (interface{}, error) {
+				return cincinnati.New(&cincinnati.Config{URL: graphURL}).Updates(ctx, channel, arch, from)
+			})
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&channel, "channel", "", "Update channel, e.g., stable-4.18.")
+	cmd.PersistentFlags().StringVar(&from, "from", "", "Version to list the upgrade targets from.")
+	cmd.PersistentFlags().StringVar(&arch, "arch", cincinnati.DefaultArch, "Architecture of the upgrade graph.")
+	cmd.PersistentFlags().StringVar(&graphURL, "graph-url", cincinnati.DefaultURL, "Graph endpoint of the update service.")
+
+	cmd.MarkPersistentFlagRequired("channel")
+	cmd.MarkPersistentFlagRequired("from")
+
+	cmd.RegisterFlagCompletionFunc("arch", cobra.FixedCompletions([]string{"amd64", "arm64", "ppc64le", "s390x", "multi"}, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(upgradesCmd())
+}
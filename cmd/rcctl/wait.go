@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/spf13/cobra"
+	"k8s.io/klog"
+)
+
+const (
+	// Exit code used when the release tag was rejected.
+	exitCodeRejected int = 2
+	// Exit code used when the release tag did not reach the desired phase in
+	// time.
+	exitCodeTimeout int = 3
+)
+
+func waitCmd() *cobra.Command {
+	var waitFor string
+	var timeout time.Duration
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "wait [tag name]",
+		Short: "Waits for a release tag to be accepted or rejected.",
+		Long: fmt.Sprintf(`
+Waits for a release tag to be accepted or rejected, printing the progress of
+its verification jobs to stderr. Once the wait is over, the release tag is
+printed to stdout.
+
+The exit code indicates the outcome:
+  0: The release tag was accepted.
+  %d: The release tag was rejected.
+  %d: The release tag did not reach the desired phase before the timeout.
+
+Any other non-zero exit code indicates an error.`, exitCodeRejected, exitCodeTimeout),
+		Args: cobra.ExactArgs(1),
+		Example: `
+	# Waits for a release tag to be either accepted or rejected.
+	rcctl wait '4.23.0-0.nightly-2026-03-05-153752'
+
+	# Waits up to 6 hours for a release tag to be accepted, including if it is manually accepted after being rejected.
+	rcctl wait '4.23.0-0.nightly-2026-03-05-153752' --for accepted --timeout 6h`,
+		ValidArgsFunction: completeReleaseTagArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			phases, err := getPhasesToWaitFor(waitFor)
+			if err != nil {
+				return err
+			}
+
+			// Errors past this point are not caused by incorrect usage.
+			cmd.SilenceUsage = true
+
+			var info *releasecontroller.APIReleaseInfo
+			var timedOut bool
+
+			err = doReleaseControllerOpWithTimeout(timeout, func(ctx context.Context, rc *releasecontroller.ReleaseController) (interface{}, error) {
+				stream, release, err := rc.ReleaseStreams().FindReleaseNameAndStream(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+
+				opts := releasecontroller.WaitOpts{
+					Phases:          phases,
+					InitialInterval: interval,
+					Progress:        newWaitProgressPrinter(release).print,
+				}
+
+				info, err = rc.ReleaseStream(stream).WaitForTag(ctx, release, opts)
+
+				// Only the expiry of the --timeout itself means that the release
+				// tag did not reach the phase in time; a request to the release
+				// controller may time out on its own.
+				timedOut = err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded)
+
+				return info, err
+			})
+
+			if timedOut {
+				return &exitCodeError{code: exitCodeTimeout, err: err}
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if info.Phase == string(releasecontroller.PhaseRejected) {
+				return &exitCodeError{code: exitCodeRejected, err: fmt.Errorf("release tag %q was rejected", info.Name)}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&waitFor, "for", "any", "Phase to wait for, one of: accepted, rejected, any")
+	cmd.PersistentFlags().DurationVar(&timeout, "timeout", 4*time.Hour, "Maximum amount of time to wait.")
+	cmd.PersistentFlags().DurationVar(&interval, "interval", 15*time.Second, "Initial polling interval. This grows with each poll up to 2 minutes.")
+
+	cmd.RegisterFlagCompletionFunc("for", cobra.FixedCompletions([]string{"accepted", "rejected", "any"}, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+func getPhasesToWaitFor(waitFor string) ([]releasecontroller.Phase, error) {
+	switch strings.ToLower(waitFor) {
+	case "accepted":
+		return []releasecontroller.Phase{releasecontroller.PhaseAccepted}, nil
+	case "rejected":
+		return []releasecontroller.Phase{releasecontroller.PhaseRejected}, nil
+	case "any":
+		return []releasecontroller.Phase{releasecontroller.PhaseAccepted, releasecontroller.PhaseRejected}, nil
+	}
+
+	return nil, fmt.Errorf("invalid --for %q, must be one of: accepted, rejected, any", waitFor)
+}
+
+// Prints the progress of a release tag each time it changes. Individual
+// verification job state changes are printed along with a summary.
+type waitProgressPrinter struct {
+	tag       string
+	jobStates map[string]string
+	summary   string
+}
+
+func newWaitProgressPrinter(tag string) *waitProgressPrinter {
+	return &waitProgressPrinter{
+		tag:       tag,
+		jobStates: map[string]string{},
+	}
+}
+
+func (w *waitProgressPrinter) print(info *releasecontroller.APIReleaseInfo, err error) {
+	if err != nil {
+		klog.Warningf("Could not get release tag %s, will retry: %s", w.tag, err)
+		return
+	}
+
+	results := info.Results
+	if results == nil {
+		results = &releasecontroller.VerificationJobsSummary{}
+	}
+
+	for _, kind := range []struct {
+		name string
+		jobs releasecontroller.VerificationStatusMap
+	}{
+		{name: "blocking", jobs: results.BlockingJobs},
+		{name: "informing", jobs: results.InformingJobs},
+	} {
+		jobs := kind.jobs
+		names := []string{}
+		for name := range jobs {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			status := jobs[name]
+			if status == nil {
+				// The release controller has not reported anything for the job yet.
+				status = &releasecontroller.VerificationStatus{State: "Pending"}
+			}
+
+			if w.jobStates[name] != status.State {
+				klog.Infof("%s job %s: %s %s", kind.name, name, status.State, status.URL)
+				w.jobStates[name] = status.State
+			}
+		}
+	}
+
+	summary := fmt.Sprintf("%s is %s, blocking jobs: %s, informing jobs: %s", w.tag, info.Phase, results.BlockingJobs.Counts(), results.InformingJobs.Counts())
+	if summary != w.summary {
+		klog.Info(summary)
+		w.summary = summary
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(waitCmd())
+}
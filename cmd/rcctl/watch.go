@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/notify"
+	"github.com/spf13/cobra"
+	"k8s.io/klog"
+)
+
+func watchCmd() *cobra.Command {
+	var configPath string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Watches releasestreams and sends notifications when releases are accepted, rejected or a releasestream goes stale.",
+		Long: `
+Watches the releasestreams given in the config file and sends events to the
+configured sinks according to the configured rules. Events are produced when a
+release is accepted, when a release is rejected (along with its failed
+blocking jobs), and when a releasestream has not had a release accepted within
+the configured staleAfter duration. Releases which already exist when the watch
+starts do not produce events.
+
+Example config:
+
+  streams:
+  - 4.23.0-0.nightly
+  interval: 5m
+  staleAfter: 24h
+  sinks:
+  - name: team-chat
+    slack:
+      url: https://hooks.slack.com/services/...
+  - name: ci-hook
+    webhook:
+      url: https://ci.example.com/hooks/release
+      headers:
+        Authorization: Bearer ...
+      body: '{"release": {{ json .Tag }}, "phase": {{ json .Phase }}}'
+  - name: script
+    exec:
+      command: ["/usr/local/bin/on-release-event"]
+  rules:
+  - events: [rejected, stale]
+    sinks: [team-chat]
+  - streams: [4.23.0-0.nightly]
+    events: [accepted]
+    sinks: [ci-hook, script]`,
+		Args: cobra.NoArgs,
+		Example: `
+	# Watches the releasestreams in the given config.
+	rcctl watch --config ./watch.yaml
+
+	# Logs the events which would be sent without sending them.
+	rcctl watch --config ./watch.yaml --dry-run`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if configPath == "" {
+				return fmt.Errorf("--config must be given")
+			}
+
+			cfg, err := notify.LoadConfig(configPath)
+			if err != nil {
+				return err
+			}
+
+			notifierCfg := cfg
+			if dryRun {
+				notifierCfg = &notify.Config{}
+			}
+
+			n, err := notify.NewNotifier(notifierCfg, nil)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			rc, err := getReleaseControllerForLinks(ctx)
+			if err != nil {
+				return err
+			}
+
+			klog.Infof("Watching %v on %s every %s", cfg.Streams, rc, cfg.Interval.Duration)
+
+			err = notify.NewWatcher(rc, cfg.Streams, cfg.StaleAfter.Duration).Run(ctx, cfg.Interval.Duration, n)
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+
+			return err
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to the YAML config describing the releasestreams to watch, sinks and rules.")
+	cmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Log events without sending them to any sinks.")
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd())
+}
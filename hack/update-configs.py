@@ -23,6 +23,11 @@ def create_build(name):
         "env": [
             "CGO_ENABLED=0",
         ],
+        # Use the pure-Go OpenPGP implementation in containers/image rather
+        # than gpgme, which requires cgo.
+        "tags": [
+            "containers_image_openpgp",
+        ],
         "ldflags": [
             "-s -w -X main.version={{.Version}} -X main.commit={{.Commit}} -X main.date={{.Date}} -X main.builtBy=goreleaser".replace(
                 "main",
@@ -0,0 +1,276 @@
+package bisect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+)
+
+// Verdict is the outcome of testing a release tag.
+type Verdict string
+
+const (
+	Good Verdict = "good"
+	Bad  Verdict = "bad"
+)
+
+// Step records a verdict given during the bisect.
+type Step struct {
+	Tag     string    `json:"tag"`
+	Verdict Verdict   `json:"verdict"`
+	Time    time.Time `json:"time"`
+}
+
+// State is the persisted state of a bisect. Tags holds the candidate release
+// tags from oldest to newest, starting with the original good tag and ending
+// with the original bad tag. The regression was introduced somewhere after
+// Tags[Good] and at or before Tags[Bad].
+type State struct {
+	Controller string   `json:"controller"`
+	Stream     string   `json:"stream"`
+	Tags       []string `json:"tags"`
+	Good       int      `json:"good"`
+	Bad        int      `json:"bad"`
+	Steps      []Step   `json:"steps"`
+}
+
+// Status summarizes the progress of a bisect.
+type Status struct {
+	Controller string `json:"controller"`
+	Stream     string `json:"stream"`
+	Good       string `json:"good"`
+	Bad        string `json:"bad"`
+	// Remaining is the number of untested tags between Good and Bad.
+	Remaining int `json:"remaining"`
+	// Next is the tag to test next. It is empty once the bisect is done.
+	Next string `json:"next,omitempty"`
+	Done bool   `json:"done"`
+	// Changes are the components which changed between Good and Bad. They are
+	// only populated once the bisect is done.
+	Changes []releasecontroller.ComponentDiff `json:"changes,omitempty"`
+}
+
+// DefaultPath returns the default path for the bisect state file, which is
+// within $XDG_STATE_HOME (defaulting to ~/.local/state).
+func DefaultPath() (string, error) {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("could not determine home dir: %w", err)
+		}
+
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+
+	return filepath.Join(stateHome, "rcctl", "bisect.json"), nil
+}
+
+// Start begins a bisect between the given good and bad release tags, which
+// must be in the same release stream with the good tag being older. The
+// candidates are the accepted tags between them in release stream order.
+func Start(ctx context.Context, rc *releasecontroller.ReleaseController, good, bad string) (*State, error) {
+	goodStream, _, err := rc.ReleaseStreams().FindReleaseNameAndStream(ctx, good)
+	if err != nil {
+		return nil, err
+	}
+
+	badStream, _, err := rc.ReleaseStreams().FindReleaseNameAndStream(ctx, bad)
+	if err != nil {
+		return nil, err
+	}
+
+	if goodStream != badStream {
+		return nil, fmt.Errorf("good tag %q is in release stream %q but bad tag %q is in release stream %q", good, goodStream, bad, badStream)
+	}
+
+	tags, err := rc.ReleaseStream(goodStream).Tags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get tags for release stream %q: %w", goodStream, err)
+	}
+
+	candidates, err := candidatesBetween(tags.Tags, good, bad)
+	if err != nil {
+		return nil, err
+	}
+
+	// The URL is stored rather than the hostname so that release controllers
+	// other than the known ones (e.g., a cache served by rcctl serve) can be
+	// used again when the bisect is resumed.
+	return &State{
+		Controller: rc.URL(),
+		Stream:     goodStream,
+		Tags:       candidates,
+		Good:       0,
+		Bad:        len(candidates) - 1,
+		Steps:      []Step{},
+	}, nil
+}
+
+// Returns the good tag, the accepted tags after it, and the bad tag, in
+// oldest-first order. The given tags are in newest-first order.
+func candidatesBetween(tags []releasecontroller.Release, good, bad string) ([]string, error) {
+	goodIndex, badIndex := -1, -1
+
+	for i, tag := range tags {
+		switch tag.Name {
+		case good:
+			goodIndex = i
+		case bad:
+			badIndex = i
+		}
+	}
+
+	if goodIndex == -1 {
+		return nil, fmt.Errorf("good tag %q not found", good)
+	}
+
+	if badIndex == -1 {
+		return nil, fmt.Errorf("bad tag %q not found", bad)
+	}
+
+	if badIndex >= goodIndex {
+		return nil, fmt.Errorf("good tag %q must be older than bad tag %q", good, bad)
+	}
+
+	out := []string{good}
+
+	for i := goodIndex - 1; i > badIndex; i-- {
+		if tags[i].Phase == string(releasecontroller.PhaseAccepted) {
+			out = append(out, tags[i].Name)
+		}
+	}
+
+	return append(out, bad), nil
+}
+
+// Load reads the bisect state from the given path.
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no bisect in progress, use 'rcctl bisect start' to begin one")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	out := &State{}
+	if err := json.Unmarshal(data, out); err != nil {
+		return nil, fmt.Errorf("could not parse bisect state %s: %w", path, err)
+	}
+
+	if len(out.Tags) < 2 || out.Good < 0 || out.Bad >= len(out.Tags) || out.Good >= out.Bad {
+		return nil, fmt.Errorf("bisect state %s is invalid", path)
+	}
+
+	return out, nil
+}
+
+// Save writes the bisect state to the given path, creating its directory if
+// needed.
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Done returns true once the good and bad tags are adjacent.
+func (s *State) Done() bool {
+	return s.Bad-s.Good <= 1
+}
+
+// Next returns the midpoint tag to test next, or an empty string once the
+// bisect is done.
+func (s *State) Next() string {
+	if s.Done() {
+		return ""
+	}
+
+	return s.Tags[(s.Good+s.Bad)/2]
+}
+
+// Mark records the verdict for the given tag, or for the tag returned by Next
+// if tag is empty, and narrows the range accordingly. The tag must be within
+// the current range.
+func (s *State) Mark(tag string, verdict Verdict, now time.Time) error {
+	if s.Done() {
+		return fmt.Errorf("bisect is done, the first bad tag is %q", s.Tags[s.Bad])
+	}
+
+	if tag == "" {
+		tag = s.Next()
+	}
+
+	index := -1
+	for i := s.Good + 1; i < s.Bad; i++ {
+		if s.Tags[i] == tag {
+			index = i
+			break
+		}
+	}
+
+	if index == -1 {
+		return fmt.Errorf("tag %q is not between the current good tag %q and bad tag %q", tag, s.Tags[s.Good], s.Tags[s.Bad])
+	}
+
+	switch verdict {
+	case Good:
+		s.Good = index
+	case Bad:
+		s.Bad = index
+	default:
+		return fmt.Errorf("unknown verdict %q", verdict)
+	}
+
+	s.Steps = append(s.Steps, Step{Tag: tag, Verdict: verdict, Time: now})
+
+	return nil
+}
+
+// Status summarizes the bisect. Once the bisect is done, the components which
+// changed between the final good and bad tags are looked up from the release
+// controller.
+func (s *State) Status(ctx context.Context, rc *releasecontroller.ReleaseController) (*Status, error) {
+	out := &Status{
+		Controller: s.Controller,
+		Stream:     s.Stream,
+		Good:       s.Tags[s.Good],
+		Bad:        s.Tags[s.Bad],
+		Remaining:  s.Bad - s.Good - 1,
+		Next:       s.Next(),
+		Done:       s.Done(),
+	}
+
+	if !out.Done {
+		return out, nil
+	}
+
+	good, err := rc.GetReleaseInfo(ctx, out.Good)
+	if err != nil {
+		return nil, fmt.Errorf("could not get release info for %q: %w", out.Good, err)
+	}
+
+	bad, err := rc.GetReleaseInfo(ctx, out.Bad)
+	if err != nil {
+		return nil, fmt.Errorf("could not get release info for %q: %w", out.Bad, err)
+	}
+
+	out.Changes = releasecontroller.DiffComponents(good, bad)
+
+	return out, nil
+}
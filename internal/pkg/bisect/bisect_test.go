@@ -0,0 +1,222 @@
+package bisect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	imagev1 "github.com/openshift/api/image/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const stream string = "4.19.0-0.nightly"
+
+func tagName(day int) string {
+	return fmt.Sprintf("%s-2025-01-%02d-000000", stream, day)
+}
+
+// Serves a release stream with tags for days 1 through 10, where day 5 is
+// rejected. The machine-config-operator commit changes on day 7.
+func newFakeReleaseController(t *testing.T) *releasecontroller.ReleaseController {
+	t.Helper()
+
+	tags := &releasecontroller.ReleaseTags{Name: stream}
+	for day := 10; day >= 1; day-- {
+		phase := string(releasecontroller.PhaseAccepted)
+		if day == 5 {
+			phase = string(releasecontroller.PhaseRejected)
+		}
+
+		tags.Tags = append(tags.Tags, releasecontroller.Release{Name: tagName(day), Phase: phase})
+	}
+
+	tags.Tags = append(tags.Tags, releasecontroller.Release{Name: "other", Phase: string(releasecontroller.PhaseAccepted)})
+
+	releaseInfo := func(tag string) *releasecontroller.ReleaseInfo {
+		commit := "aaaa"
+		if tag >= tagName(7) {
+			commit = "bbbb"
+		}
+
+		return &releasecontroller.ReleaseInfo{
+			References: &imagev1.ImageStream{
+				Spec: imagev1.ImageStreamSpec{
+					Tags: []imagev1.TagReference{
+						{
+							Name: "machine-config-operator",
+							Annotations: map[string]string{
+								"io.openshift.build.source-location": "https://github.com/openshift/machine-config-operator",
+								"io.openshift.build.commit.id":       commit,
+							},
+							From: &corev1.ObjectReference{Kind: "DockerImage", Name: "quay.io/mco:" + commit},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+
+		var out interface{}
+
+		switch {
+		case strings.Join(parts, "/") == "api/v1/releasestreams/all":
+			all := map[string][]string{stream: {}, "4.18.0-0.nightly": {"other"}}
+			for _, tag := range tags.Tags[:10] {
+				all[stream] = append(all[stream], tag.Name)
+			}
+
+			out = all
+		case len(parts) == 5 && parts[3] == stream && parts[4] == "tags":
+			out = tags
+		case len(parts) == 3 && parts[0] == "releasetag" && strings.HasPrefix(parts[1], stream):
+			out = releaseInfo(parts[1])
+		}
+
+		if out == nil {
+			http.NotFound(w, req)
+			return
+		}
+
+		//nolint:errcheck // This is test code.
+		json.NewEncoder(w).Encode(out)
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return releasecontroller.New(srv.Listener.Addr().String(), &releasecontroller.ReleaseControllerConfig{Client: srv.Client()})
+}
+
+func TestStartErrors(t *testing.T) {
+	rc := newFakeReleaseController(t)
+	ctx := context.Background()
+
+	testCases := []struct {
+		name string
+		good string
+		bad  string
+	}{
+		{name: "Good is newer than bad", good: tagName(9), bad: tagName(2)},
+		{name: "Different release streams", good: "other", bad: tagName(2)},
+		{name: "Unknown tag", good: tagName(1), bad: "unknown"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := Start(ctx, rc, testCase.good, testCase.bad)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestBisect(t *testing.T) {
+	rc := newFakeReleaseController(t)
+	ctx := context.Background()
+	now := time.Date(2025, time.January, 11, 0, 0, 0, 0, time.UTC)
+
+	state, err := Start(ctx, rc, tagName(1), tagName(10))
+	require.NoError(t, err)
+
+	// The stored controller must be usable to resume the bisect.
+	resumed, err := releasecontroller.NewFromURL(state.Controller, nil)
+	require.NoError(t, err)
+	assert.Equal(t, rc.Host(), resumed.Host())
+
+	// The rejected tag is not a candidate.
+	assert.Equal(t, []string{tagName(1), tagName(2), tagName(3), tagName(4), tagName(6), tagName(7), tagName(8), tagName(9), tagName(10)}, state.Tags)
+
+	path := filepath.Join(t.TempDir(), "rcctl", "bisect.json")
+	require.NoError(t, state.Save(path))
+
+	// Bisects until done, loading and saving the state each step like the
+	// CLI does. Everything from day 7 onwards is bad.
+	expectedNext := []string{tagName(6), tagName(8), tagName(7)}
+
+	for _, expected := range expectedNext {
+		state, err = Load(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, state.Next())
+
+		verdict := Good
+		if state.Next() >= tagName(7) {
+			verdict = Bad
+		}
+
+		require.NoError(t, state.Mark("", verdict, now))
+		require.NoError(t, state.Save(path))
+	}
+
+	state, err = Load(path)
+	require.NoError(t, err)
+	assert.True(t, state.Done())
+	assert.Len(t, state.Steps, 3)
+
+	assert.Error(t, state.Mark("", Bad, now))
+
+	status, err := state.Status(ctx, rc)
+	require.NoError(t, err)
+
+	assert.Equal(t, &Status{
+		Controller: rc.URL(),
+		Stream:     stream,
+		Good:       tagName(6),
+		Bad:        tagName(7),
+		Done:       true,
+		Changes: []releasecontroller.ComponentDiff{
+			{
+				Name:       "machine-config-operator",
+				Repo:       "openshift/machine-config-operator",
+				FromCommit: "aaaa",
+				ToCommit:   "bbbb",
+				CompareURL: "https://github.com/openshift/machine-config-operator/compare/aaaa...bbbb",
+			},
+		},
+	}, status)
+}
+
+func TestMark(t *testing.T) {
+	state := &State{
+		Tags: []string{"a", "b", "c", "d", "e"},
+		Good: 0,
+		Bad:  4,
+	}
+
+	now := time.Now()
+
+	// Tags outside of the range cannot be marked.
+	assert.Error(t, state.Mark("a", Bad, now))
+	assert.Error(t, state.Mark("unknown", Bad, now))
+	assert.Error(t, state.Mark("b", Verdict("maybe"), now))
+
+	// Tags other than the midpoint may be marked.
+	require.NoError(t, state.Mark("b", Good, now))
+	assert.Equal(t, 1, state.Good)
+	assert.Equal(t, "c", state.Next())
+
+	require.NoError(t, state.Mark("d", Bad, now))
+	assert.Equal(t, 3, state.Bad)
+	assert.Equal(t, "c", state.Next())
+}
+
+func TestLoadErrors(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorContains(t, err, "no bisect in progress")
+
+	path := filepath.Join(t.TempDir(), "bisect.json")
+	require.NoError(t, (&State{Tags: []string{"a"}}).Save(path))
+
+	_, err = Load(path)
+	assert.Error(t, err)
+}
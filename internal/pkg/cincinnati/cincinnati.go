@@ -0,0 +1,288 @@
+package cincinnati
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/coreos/go-semver/semver"
+)
+
+// DefaultURL is the graph endpoint of the production OpenShift Update Service.
+const DefaultURL string = "https://api.openshift.com/api/upgrades_info/v1/graph"
+
+// DefaultArch is the architecture requested when none is given.
+const DefaultArch string = "amd64"
+
+// Client represents an OpenShift Update Service (Cincinnati) graph API client.
+type Client struct {
+	url    string
+	client *http.Client
+}
+
+// Config holds configuration options for the Client.
+type Config struct {
+	// URL is the graph endpoint. Defaults to DefaultURL.
+	URL            string
+	DefaultTimeout time.Duration
+	Client         *http.Client // optional user-provided client
+}
+
+// Graph is the upgrade graph for a given channel and architecture. It is a
+// superset of the release controller graph with the addition of conditional
+// edges.
+type Graph struct {
+	releasecontroller.ReleaseGraph
+	ConditionalEdges []ConditionalEdges `json:"conditionalEdges,omitempty"`
+}
+
+// ConditionalEdges are a set of upgrade edges which are only recommended when
+// none of the given risks apply to the cluster.
+type ConditionalEdges struct {
+	Edges []ConditionalEdge       `json:"edges"`
+	Risks []ConditionalUpdateRisk `json:"risks"`
+}
+
+// ConditionalEdge is an upgrade edge identified by version rather than by node
+// index.
+type ConditionalEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ConditionalUpdateRisk describes a known issue which may affect clusters
+// taking a conditional upgrade edge.
+type ConditionalUpdateRisk struct {
+	URL           string         `json:"url"`
+	Name          string         `json:"name"`
+	Message       string         `json:"message"`
+	MatchingRules []MatchingRule `json:"matchingRules"`
+}
+
+// MatchingRule determines whether a cluster is exposed to a risk.
+type MatchingRule struct {
+	Type   string       `json:"type"`
+	PromQL *PromQLQuery `json:"promql,omitempty"`
+}
+
+type PromQLQuery struct {
+	PromQL string `json:"promql"`
+}
+
+// Update is an upgrade target.
+type Update struct {
+	Version string `json:"version"`
+	Payload string `json:"payload"`
+}
+
+// ConditionalUpdate is an upgrade target which is only recommended when none
+// of its risks apply.
+type ConditionalUpdate struct {
+	Update
+	Risks []ConditionalUpdateRisk `json:"risks"`
+}
+
+// Updates are the upgrade targets available from a given version.
+type Updates struct {
+	From        string              `json:"from"`
+	Channel     string              `json:"channel,omitempty"`
+	Arch        string              `json:"arch,omitempty"`
+	Recommended []Update            `json:"recommended"`
+	Conditional []ConditionalUpdate `json:"conditional"`
+}
+
+// New creates a new Client with the given configuration.
+func New(cfg *Config) *Client {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+
+	if cfg.DefaultTimeout == 0 {
+		cfg.DefaultTimeout = 30 * time.Second
+	}
+
+	u := cfg.URL
+	if u == "" {
+		u = DefaultURL
+	}
+
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{Timeout: cfg.DefaultTimeout}
+	}
+
+	return &Client{url: u, client: client}
+}
+
+// URL returns the graph endpoint of the update service.
+func (c *Client) URL() string {
+	return c.url
+}
+
+// Graph gets the upgrade graph for the given channel and architecture.
+func (c *Client) Graph(ctx context.Context, channel, arch string) (*Graph, error) {
+	if arch == "" {
+		arch = DefaultArch
+	}
+
+	u, err := url.Parse(c.url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid update service URL %q: %w", c.url, err)
+	}
+
+	vals := u.Query()
+	vals.Set("channel", channel)
+	vals.Set("arch", arch)
+	u.RawQuery = vals.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The update service returns an error unless JSON is explicitly requested.
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("got HTTP %d from %s: %s", resp.StatusCode, u.String(), string(body))
+	}
+
+	out := &Graph{}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, fmt.Errorf("could not decode graph from %s: %w", u.String(), err)
+	}
+
+	return out, nil
+}
+
+// Updates gets the recommended and conditional upgrade targets from the given
+// version in the given channel.
+func (c *Client) Updates(ctx context.Context, channel, arch, from string) (*Updates, error) {
+	graph, err := c.Graph(ctx, channel, arch)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := graph.Updates(from)
+	if err != nil {
+		return nil, fmt.Errorf("channel %q: %w", channel, err)
+	}
+
+	if arch == "" {
+		arch = DefaultArch
+	}
+
+	out.Channel = channel
+	out.Arch = arch
+
+	return out, nil
+}
+
+// Updates gets the recommended and conditional upgrade targets from the given
+// version. Targets are sorted newest first.
+func (g *Graph) Updates(from string) (*Updates, error) {
+	fromIdx := -1
+	nodesByVersion := map[string]releasecontroller.ReleaseNode{}
+
+	for i, node := range g.Nodes {
+		nodesByVersion[node.Version] = node
+
+		if node.Version == from {
+			fromIdx = i
+		}
+	}
+
+	if fromIdx == -1 {
+		return nil, fmt.Errorf("version %q not found in graph", from)
+	}
+
+	out := &Updates{
+		From:        from,
+		Recommended: []Update{},
+		Conditional: []ConditionalUpdate{},
+	}
+
+	recommended := map[string]struct{}{}
+
+	for _, edge := range g.Edges {
+		if len(edge) != 2 || edge[0] != fromIdx {
+			continue
+		}
+
+		if edge[1] < 0 || edge[1] >= len(g.Nodes) {
+			return nil, fmt.Errorf("edge %v refers to a nonexistent node", edge)
+		}
+
+		node := g.Nodes[edge[1]]
+		recommended[node.Version] = struct{}{}
+		out.Recommended = append(out.Recommended, Update{Version: node.Version, Payload: node.Payload})
+	}
+
+	// A given target may appear in more than one set of conditional edges, in
+	// which case all of their risks apply.
+	conditional := map[string]*ConditionalUpdate{}
+
+	for _, ce := range g.ConditionalEdges {
+		for _, edge := range ce.Edges {
+			if edge.From != from {
+				continue
+			}
+
+			// Unconditional edges take precedence over conditional ones.
+			if _, ok := recommended[edge.To]; ok {
+				continue
+			}
+
+			if cu, ok := conditional[edge.To]; ok {
+				cu.Risks = append(cu.Risks, ce.Risks...)
+				continue
+			}
+
+			conditional[edge.To] = &ConditionalUpdate{
+				Update: Update{Version: edge.To, Payload: nodesByVersion[edge.To].Payload},
+				Risks:  append([]ConditionalUpdateRisk{}, ce.Risks...),
+			}
+		}
+	}
+
+	for _, cu := range conditional {
+		out.Conditional = append(out.Conditional, *cu)
+	}
+
+	sort.Slice(out.Recommended, func(i, j int) bool {
+		return isNewer(out.Recommended[i].Version, out.Recommended[j].Version)
+	})
+
+	sort.Slice(out.Conditional, func(i, j int) bool {
+		return isNewer(out.Conditional[i].Version, out.Conditional[j].Version)
+	})
+
+	return out, nil
+}
+
+// Compares versions semantically, falling back to a lexical comparison for
+// versions which are not valid semvers.
+func isNewer(a, b string) bool {
+	aVer, aErr := semver.NewVersion(a)
+	bVer, bErr := semver.NewVersion(b)
+
+	if aErr != nil || bErr != nil {
+		return a > b
+	}
+
+	return bVer.LessThan(*aVer)
+}
@@ -0,0 +1,149 @@
+package cincinnati
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Abbreviated from the production update service.
+const testGraph string = `{
+  "version": 1,
+  "nodes": [
+    {"version": "4.17.10", "payload": "quay.io/openshift-release-dev/ocp-release@sha256:1710", "metadata": {"io.openshift.upgrades.graph.release.channels": "stable-4.17,stable-4.18"}},
+    {"version": "4.18.1", "payload": "quay.io/openshift-release-dev/ocp-release@sha256:1801", "metadata": {}},
+    {"version": "4.18.2", "payload": "quay.io/openshift-release-dev/ocp-release@sha256:1802", "metadata": {}},
+    {"version": "4.18.3", "payload": "quay.io/openshift-release-dev/ocp-release@sha256:1803", "metadata": {}},
+    {"version": "4.18.4", "payload": "quay.io/openshift-release-dev/ocp-release@sha256:1804", "metadata": {}}
+  ],
+  "edges": [[0, 1], [0, 3], [1, 3], [2, 3]],
+  "conditionalEdges": [
+    {
+      "edges": [{"from": "4.17.10", "to": "4.18.2"}, {"from": "4.18.1", "to": "4.18.2"}],
+      "risks": [{"url": "https://issues.redhat.com/browse/OCPBUGS-1", "name": "FirstRisk", "message": "First risk.", "matchingRules": [{"type": "PromQL", "promql": {"promql": "group(cluster_version)"}}]}]
+    },
+    {
+      "edges": [{"from": "4.17.10", "to": "4.18.2"}, {"from": "4.17.10", "to": "4.18.1"}],
+      "risks": [{"url": "https://issues.redhat.com/browse/OCPBUGS-2", "name": "SecondRisk", "message": "Second risk.", "matchingRules": [{"type": "Always"}]}]
+    }
+  ]
+}`
+
+func newFakeUpdateService(t *testing.T) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "application/json" {
+			http.Error(w, "unsupported media type", http.StatusNotAcceptable)
+			return
+		}
+
+		if r.URL.Query().Get("channel") != "stable-4.18" || r.URL.Query().Get("arch") != "amd64" {
+			http.Error(w, "unknown channel", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		//nolint:errcheck // This is test code.
+		w.Write([]byte(testGraph))
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return New(&Config{URL: srv.URL, Client: srv.Client()})
+}
+
+func TestGraph(t *testing.T) {
+	c := newFakeUpdateService(t)
+
+	graph, err := c.Graph(context.Background(), "stable-4.18", "")
+	require.NoError(t, err)
+
+	assert.Len(t, graph.Nodes, 5)
+	assert.Len(t, graph.Edges, 4)
+	assert.Len(t, graph.ConditionalEdges, 2)
+	assert.Equal(t, "stable-4.17,stable-4.18", graph.Nodes[0].Metadata["io.openshift.upgrades.graph.release.channels"])
+	assert.Equal(t, "group(cluster_version)", graph.ConditionalEdges[0].Risks[0].MatchingRules[0].PromQL.PromQL)
+	assert.Nil(t, graph.ConditionalEdges[1].Risks[0].MatchingRules[0].PromQL)
+
+	_, err = c.Graph(context.Background(), "stable-4.99", "")
+	assert.ErrorContains(t, err, "HTTP 400")
+}
+
+func TestUpdates(t *testing.T) {
+	c := newFakeUpdateService(t)
+
+	testCases := []struct {
+		name                string
+		from                string
+		expectedRecommended []string
+		expectedConditional map[string][]string
+		expectErr           bool
+	}{
+		{
+			name:                "Recommended and conditional targets",
+			from:                "4.17.10",
+			expectedRecommended: []string{"4.18.3", "4.18.1"},
+			expectedConditional: map[string][]string{
+				"4.18.2": {"FirstRisk", "SecondRisk"},
+			},
+		},
+		{
+			name:                "Single conditional target",
+			from:                "4.18.1",
+			expectedRecommended: []string{"4.18.3"},
+			expectedConditional: map[string][]string{
+				"4.18.2": {"FirstRisk"},
+			},
+		},
+		{
+			name:                "No targets",
+			from:                "4.18.4",
+			expectedRecommended: []string{},
+			expectedConditional: map[string][]string{},
+		},
+		{
+			name:      "Unknown version",
+			from:      "4.16.0",
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			updates, err := c.Updates(context.Background(), "stable-4.18", "amd64", testCase.from)
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.from, updates.From)
+			assert.Equal(t, "stable-4.18", updates.Channel)
+			assert.Equal(t, "amd64", updates.Arch)
+
+			recommended := []string{}
+			for _, update := range updates.Recommended {
+				assert.NotEmpty(t, update.Payload)
+				recommended = append(recommended, update.Version)
+			}
+
+			assert.Equal(t, testCase.expectedRecommended, recommended)
+
+			conditional := map[string][]string{}
+			for _, update := range updates.Conditional {
+				assert.NotEmpty(t, update.Payload)
+				for _, risk := range update.Risks {
+					conditional[update.Version] = append(conditional[update.Version], risk.Name)
+				}
+			}
+
+			assert.Equal(t, testCase.expectedConditional, conditional)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package containers
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/containers/image/v5/docker"
@@ -9,7 +10,7 @@ import (
 	"github.com/containers/image/v5/types"
 )
 
-func ResolveToDigestedPullspec(pullspec, pullSecretPath string) (string, error) {
+func newSystemContext(pullspec, pullSecretPath string) *types.SystemContext {
 	sysCtx := &types.SystemContext{
 		AuthFilePath: pullSecretPath,
 	}
@@ -19,6 +20,29 @@ func ResolveToDigestedPullspec(pullspec, pullSecretPath string) (string, error)
 		sysCtx.DockerInsecureSkipTLSVerify = types.NewOptionalBool(true)
 	}
 
+	return sysCtx
+}
+
+// NewImageSource opens an image source for the given image reference, which
+// may be a bare pullspec, a docker:// reference, or an oci: layout reference.
+// The caller is responsible for closing the returned image source.
+func NewImageSource(ctx context.Context, ref, pullSecretPath string) (types.ImageSource, error) {
+	imgRef, err := ParseImageReference(ref)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %q: %w", ref, err)
+	}
+
+	imgSrc, err := imgRef.NewImageSource(ctx, newSystemContext(ref, pullSecretPath))
+	if err != nil {
+		return nil, fmt.Errorf("could not open %q: %w", ref, err)
+	}
+
+	return imgSrc, nil
+}
+
+func ResolveToDigestedPullspec(pullspec, pullSecretPath string) (string, error) {
+	sysCtx := newSystemContext(pullspec, pullSecretPath)
+
 	tagged, err := docker.ParseReference("//" + pullspec)
 	if err != nil {
 		return "", err
@@ -0,0 +1,248 @@
+package containers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/oci/layout"
+	"github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	dockerTransportPrefix string = "docker://"
+	ociTransportPrefix    string = "oci:"
+)
+
+// Records the digest of the source manifest on the index.json entry of an
+// image copied to an OCI layout since the manifest may have been converted.
+const sourceDigestAnnotation string = "io.github.cheesesashimi.rcctl.source-digest"
+
+// CopyOpts holds the options for copying an image.
+type CopyOpts struct {
+	// SourceAuthfilePath is the path to a registry auth file for the source.
+	SourceAuthfilePath string
+	// DestAuthfilePath is the path to a registry auth file for the destination.
+	DestAuthfilePath string
+	// DestInsecure allows talking to a destination registry over plain HTTP or
+	// with an untrusted TLS certificate.
+	DestInsecure bool
+}
+
+// CopyResult describes the outcome of copying an image.
+type CopyResult struct {
+	// Digest is the digest of the top-level manifest that was copied.
+	Digest digest.Digest
+	// Skipped is true when the destination already had the image.
+	Skipped bool
+}
+
+// IsOCILayoutReference returns true if the given image reference refers to an
+// OCI layout directory (oci:path[:tag]).
+func IsOCILayoutReference(ref string) bool {
+	return strings.HasPrefix(ref, ociTransportPrefix)
+}
+
+// ParseImageReference parses an image reference which is either a bare image
+// pullspec, a docker:// reference, or an oci: layout reference.
+func ParseImageReference(ref string) (types.ImageReference, error) {
+	if IsOCILayoutReference(ref) {
+		return layout.ParseReference(strings.TrimPrefix(ref, ociTransportPrefix))
+	}
+
+	return docker.ParseReference("//" + strings.TrimPrefix(ref, dockerTransportPrefix))
+}
+
+// CopyImage copies an image (or manifest list along with all of its
+// instances) from the source reference to the destination reference. The
+// digest is preserved when copying to a registry; copying to an OCI layout may
+// require converting the manifests, in which case the digest of the copied
+// manifest is returned. Blobs which are already present in the destination are
+// not copied again, so an interrupted copy can be resumed by calling CopyImage
+// again. If the destination already has the image, nothing is copied. For OCI
+// layouts, this is determined by the source digest recorded when the image was
+// copied there.
+func CopyImage(ctx context.Context, src, dest string, opts CopyOpts) (*CopyResult, error) {
+	srcRef, err := ParseImageReference(src)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse source %q: %w", src, err)
+	}
+
+	destRef, err := ParseImageReference(dest)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse destination %q: %w", dest, err)
+	}
+
+	srcSysCtx := newSystemContext(src, opts.SourceAuthfilePath)
+	destSysCtx := newSystemContext(dest, opts.DestAuthfilePath)
+	if opts.DestInsecure {
+		destSysCtx.DockerInsecureSkipTLSVerify = types.NewOptionalBool(true)
+		destSysCtx.OCIInsecureSkipTLSVerify = true
+	}
+
+	toOCILayout := IsOCILayoutReference(dest)
+	if toOCILayout {
+		// Keep layers as they are rather than compressing them, which would
+		// change the digest of an image that is otherwise copied unmodified.
+		destSysCtx.OCIAcceptUncompressedLayers = true
+	}
+
+	manifestDigest, err := getManifestDigest(ctx, srcRef, srcSysCtx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get manifest for %q: %w", src, err)
+	}
+
+	if toOCILayout {
+		if copiedDigest, ok := getOCILayoutCopy(destRef, manifestDigest); ok {
+			return &CopyResult{Digest: copiedDigest, Skipped: true}, nil
+		}
+	} else if hasManifestDigest(ctx, destRef, destSysCtx, manifestDigest) {
+		return &CopyResult{Digest: manifestDigest, Skipped: true}, nil
+	}
+
+	// The images being copied are not run, so there is no need to enforce a
+	// signature policy on them.
+	policyCtx, err := signature.NewPolicyContext(&signature.Policy{
+		Default: signature.PolicyRequirements{signature.NewPRInsecureAcceptAnything()},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint:errcheck // There is nothing to do if the policy context cannot be destroyed.
+	defer policyCtx.Destroy()
+
+	copiedManifest, err := copy.Image(ctx, policyCtx, destRef, srcRef, &copy.Options{
+		SourceCtx:          srcSysCtx,
+		DestinationCtx:     destSysCtx,
+		ImageListSelection: copy.CopyAllImages,
+		PreserveDigests:    !toOCILayout,
+		// OCI layouts cannot store signatures.
+		RemoveSignatures: toOCILayout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not copy %q to %q: %w", src, dest, err)
+	}
+
+	copiedDigest, err := manifest.Digest(copiedManifest)
+	if err != nil {
+		return nil, err
+	}
+
+	if toOCILayout {
+		if err := recordSourceDigest(dest, copiedDigest, manifestDigest); err != nil {
+			return nil, fmt.Errorf("could not record the source digest of %q in %q: %w", src, dest, err)
+		}
+	}
+
+	return &CopyResult{Digest: copiedDigest}, nil
+}
+
+func getManifestDigest(ctx context.Context, ref types.ImageReference, sysCtx *types.SystemContext) (digest.Digest, error) {
+	imgSrc, err := ref.NewImageSource(ctx, sysCtx)
+	if err != nil {
+		return "", err
+	}
+
+	defer imgSrc.Close()
+
+	rawManifest, _, err := imgSrc.GetManifest(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return manifest.Digest(rawManifest)
+}
+
+// Determines whether the given reference already has a manifest with the
+// given digest. Any error is treated as the manifest not being present.
+func hasManifestDigest(ctx context.Context, ref types.ImageReference, sysCtx *types.SystemContext, d digest.Digest) bool {
+	imgSrc, err := ref.NewImageSource(ctx, sysCtx)
+	if err != nil {
+		return false
+	}
+
+	defer imgSrc.Close()
+
+	rawManifest, _, err := imgSrc.GetManifest(ctx, nil)
+	if err != nil {
+		return false
+	}
+
+	matches, err := manifest.MatchesDigest(rawManifest, d)
+	return err == nil && matches
+}
+
+// Determines whether the given OCI layout reference was copied from the source
+// manifest with the given digest, returning the digest of the copied manifest.
+// Any error is treated as the image not being present.
+func getOCILayoutCopy(ref types.ImageReference, srcDigest digest.Digest) (digest.Digest, bool) {
+	desc, err := layout.LoadManifestDescriptor(ref)
+	if err != nil {
+		return "", false
+	}
+
+	if desc.Digest == srcDigest || desc.Annotations[sourceDigestAnnotation] == srcDigest.String() {
+		return desc.Digest, true
+	}
+
+	return "", false
+}
+
+// Adds the source digest annotation to the index.json entry that was written
+// for the given OCI layout reference (oci:path[:tag]).
+func recordSourceDigest(ref string, copiedDigest, srcDigest digest.Digest) error {
+	dir, tag, _ := strings.Cut(strings.TrimPrefix(ref, ociTransportPrefix), ":")
+	indexPath := filepath.Join(dir, imgspecv1.ImageIndexFile)
+
+	b, err := os.ReadFile(indexPath)
+	if err != nil {
+		return err
+	}
+
+	index := imgspecv1.Index{}
+	if err := json.Unmarshal(b, &index); err != nil {
+		return err
+	}
+
+	found := false
+	for i, desc := range index.Manifests {
+		if desc.Digest != copiedDigest || desc.Annotations[imgspecv1.AnnotationRefName] != tag {
+			continue
+		}
+
+		if desc.Annotations == nil {
+			index.Manifests[i].Annotations = map[string]string{}
+		}
+
+		index.Manifests[i].Annotations[sourceDigestAnnotation] = srcDigest.String()
+		found = true
+	}
+
+	if !found {
+		return fmt.Errorf("no entry for %s in %s", copiedDigest, indexPath)
+	}
+
+	out, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that an interruption cannot leave a
+	// truncated index.json behind.
+	tmpPath := indexPath + ".tmp"
+	if err := os.WriteFile(tmpPath, out, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, indexPath)
+}
@@ -0,0 +1,259 @@
+package containers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/oci/layout"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	imgspecs "github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Writes a single-layer image into an OCI layout and returns its manifest
+// blob and descriptor.
+func putTestImage(ctx context.Context, t *testing.T, dest types.ImageDestination, arch string, isInstance bool) ([]byte, imgspecv1.Descriptor) {
+	t.Helper()
+
+	putBlob := func(b []byte, mediaType string, isConfig bool) imgspecv1.Descriptor {
+		info, err := dest.PutBlob(ctx, bytes.NewReader(b), types.BlobInfo{Digest: digest.FromBytes(b), Size: int64(len(b))}, none.NoCache, isConfig)
+		require.NoError(t, err)
+		return imgspecv1.Descriptor{MediaType: mediaType, Digest: info.Digest, Size: info.Size}
+	}
+
+	config, err := json.Marshal(imgspecv1.Image{Platform: imgspecv1.Platform{OS: "linux", Architecture: arch}})
+	require.NoError(t, err)
+
+	m := imgspecv1.Manifest{
+		Versioned: imgspecs.Versioned{SchemaVersion: 2},
+		MediaType: imgspecv1.MediaTypeImageManifest,
+		Config:    putBlob(config, imgspecv1.MediaTypeImageConfig, true),
+		Layers:    []imgspecv1.Descriptor{putBlob([]byte("layer-"+arch), imgspecv1.MediaTypeImageLayer, false)},
+	}
+
+	rawManifest, err := json.Marshal(m)
+	require.NoError(t, err)
+
+	desc := imgspecv1.Descriptor{
+		MediaType: imgspecv1.MediaTypeImageManifest,
+		Digest:    digest.FromBytes(rawManifest),
+		Size:      int64(len(rawManifest)),
+		Platform:  &imgspecv1.Platform{OS: "linux", Architecture: arch},
+	}
+
+	var instanceDigest *digest.Digest
+	if isInstance {
+		instanceDigest = &desc.Digest
+	}
+
+	require.NoError(t, dest.PutManifest(ctx, rawManifest, instanceDigest))
+
+	return rawManifest, desc
+}
+
+func newTestOCILayout(ctx context.Context, t *testing.T, dir string, arches ...string) digest.Digest {
+	t.Helper()
+
+	ref, err := layout.NewReference(dir, "source")
+	require.NoError(t, err)
+
+	dest, err := ref.NewImageDestination(ctx, nil)
+	require.NoError(t, err)
+
+	defer dest.Close()
+
+	var topLevel []byte
+
+	if len(arches) == 1 {
+		topLevel, _ = putTestImage(ctx, t, dest, arches[0], false)
+	} else {
+		index := imgspecv1.Index{
+			Versioned: imgspecs.Versioned{SchemaVersion: 2},
+			MediaType: imgspecv1.MediaTypeImageIndex,
+		}
+
+		for _, arch := range arches {
+			_, desc := putTestImage(ctx, t, dest, arch, true)
+			index.Manifests = append(index.Manifests, desc)
+		}
+
+		topLevel, err = json.Marshal(index)
+		require.NoError(t, err)
+		require.NoError(t, dest.PutManifest(ctx, topLevel, nil))
+	}
+
+	require.NoError(t, dest.Commit(ctx, nil))
+
+	d, err := manifest.Digest(topLevel)
+	require.NoError(t, err)
+
+	return d
+}
+
+func TestCopyImage(t *testing.T) {
+	testCases := []struct {
+		name   string
+		arches []string
+	}{
+		{
+			name:   "Single image",
+			arches: []string{"amd64"},
+		},
+		{
+			name:   "Image index",
+			arches: []string{"amd64", "arm64", "s390x"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			srcDir := filepath.Join(t.TempDir(), "src")
+			destDir := filepath.Join(t.TempDir(), "dest")
+
+			expectedDigest := newTestOCILayout(ctx, t, srcDir, testCase.arches...)
+
+			src := fmt.Sprintf("oci:%s:source", srcDir)
+			dest := fmt.Sprintf("oci:%s:mirrored", destDir)
+
+			result, err := CopyImage(ctx, src, dest, CopyOpts{})
+			require.NoError(t, err)
+			assert.Equal(t, expectedDigest, result.Digest)
+			assert.False(t, result.Skipped)
+
+			// The copied image should have the same digest as the source.
+			destRef, err := ParseImageReference(dest)
+			require.NoError(t, err)
+			assert.True(t, hasManifestDigest(ctx, destRef, nil, expectedDigest))
+
+			// Copying again should be a no-op.
+			result, err = CopyImage(ctx, src, dest, CopyOpts{})
+			require.NoError(t, err)
+			assert.Equal(t, expectedDigest, result.Digest)
+			assert.True(t, result.Skipped)
+		})
+	}
+}
+
+// Writes a single-layer image with a Docker schema 2 manifest into an OCI
+// layout, which is converted to an OCI manifest when copied to another OCI
+// layout.
+func newTestDockerImage(ctx context.Context, t *testing.T, dir string) digest.Digest {
+	t.Helper()
+
+	ref, err := layout.NewReference(dir, "source")
+	require.NoError(t, err)
+
+	dest, err := ref.NewImageDestination(ctx, nil)
+	require.NoError(t, err)
+
+	defer dest.Close()
+
+	putBlob := func(b []byte, mediaType string, isConfig bool) manifest.Schema2Descriptor {
+		info, err := dest.PutBlob(ctx, bytes.NewReader(b), types.BlobInfo{Digest: digest.FromBytes(b), Size: int64(len(b))}, none.NoCache, isConfig)
+		require.NoError(t, err)
+		return manifest.Schema2Descriptor{MediaType: mediaType, Digest: info.Digest, Size: info.Size}
+	}
+
+	config, err := json.Marshal(imgspecv1.Image{Platform: imgspecv1.Platform{OS: "linux", Architecture: "amd64"}})
+	require.NoError(t, err)
+
+	m := manifest.Schema2FromComponents(
+		putBlob(config, manifest.DockerV2Schema2ConfigMediaType, true),
+		[]manifest.Schema2Descriptor{putBlob([]byte("layer-amd64"), manifest.DockerV2SchemaLayerMediaTypeUncompressed, false)},
+	)
+
+	rawManifest, err := m.Serialize()
+	require.NoError(t, err)
+	require.NoError(t, dest.PutManifest(ctx, rawManifest, nil))
+	require.NoError(t, dest.Commit(ctx, nil))
+
+	d, err := manifest.Digest(rawManifest)
+	require.NoError(t, err)
+
+	return d
+}
+
+func TestCopyImageConverted(t *testing.T) {
+	ctx := context.Background()
+
+	srcDir := filepath.Join(t.TempDir(), "src")
+	destDir := filepath.Join(t.TempDir(), "dest")
+
+	srcDigest := newTestDockerImage(ctx, t, srcDir)
+
+	src := fmt.Sprintf("oci:%s:source", srcDir)
+	dest := fmt.Sprintf("oci:%s:mirrored", destDir)
+
+	result, err := CopyImage(ctx, src, dest, CopyOpts{})
+	require.NoError(t, err)
+	assert.NotEqual(t, srcDigest, result.Digest)
+	assert.False(t, result.Skipped)
+
+	copiedDigest := result.Digest
+
+	// Copying again should be a no-op even though the digests differ.
+	result, err = CopyImage(ctx, src, dest, CopyOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, copiedDigest, result.Digest)
+	assert.True(t, result.Skipped)
+
+	// Another tag in the same layout has not been copied yet.
+	result, err = CopyImage(ctx, src, fmt.Sprintf("oci:%s:other", destDir), CopyOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, copiedDigest, result.Digest)
+	assert.False(t, result.Skipped)
+}
+
+func TestParseImageReference(t *testing.T) {
+	testCases := []struct {
+		name      string
+		ref       string
+		transport string
+		expectErr bool
+	}{
+		{
+			name:      "Bare pullspec",
+			ref:       "quay.io/example/image:tag",
+			transport: "docker",
+		},
+		{
+			name:      "Docker transport",
+			ref:       "docker://quay.io/example/image:tag",
+			transport: "docker",
+		},
+		{
+			name:      "OCI layout",
+			ref:       "oci:/tmp/layout:tag",
+			transport: "oci",
+		},
+		{
+			name:      "Invalid pullspec",
+			ref:       "quay.io/Example/image:tag",
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ref, err := ParseImageReference(testCase.ref)
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.transport, ref.Transport().Name())
+		})
+	}
+}
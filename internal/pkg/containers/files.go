@@ -0,0 +1,131 @@
+package containers
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/containers/image/v5/image"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/pkg/compression"
+	"github.com/containers/image/v5/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	whiteoutPrefix string = ".wh."
+	whiteoutOpaque string = whiteoutPrefix + whiteoutPrefix + ".opq"
+)
+
+// ReadImageFiles reads the regular files for which the include function
+// returns true from the layers of the given image without extracting the
+// whole image to disk. Layers are applied in order, so files from later
+// layers replace those from earlier layers and whiteouts remove them. For
+// manifest lists, the instance for the current platform is read. The returned
+// map is keyed by the file path relative to the image root (e.g.,
+// release-manifests/image-references).
+func ReadImageFiles(ctx context.Context, ref, pullSecretPath string, include func(name string) bool) (map[string][]byte, error) {
+	imgSrc, err := NewImageSource(ctx, ref, pullSecretPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer imgSrc.Close()
+
+	img, err := image.FromUnparsedImage(ctx, newSystemContext(ref, pullSecretPath), image.UnparsedInstance(imgSrc, nil))
+	if err != nil {
+		return nil, fmt.Errorf("could not read image %q: %w", ref, err)
+	}
+
+	out := map[string][]byte{}
+
+	for _, layer := range img.LayerInfos() {
+		if err := readLayerFiles(ctx, imgSrc, layer, include, out); err != nil {
+			return nil, fmt.Errorf("could not read layer %s of %q: %w", layer.Digest, ref, err)
+		}
+	}
+
+	return out, nil
+}
+
+// Reads the included files from a single layer into the given map, applying
+// any whiteouts to the files read from earlier layers.
+func readLayerFiles(ctx context.Context, imgSrc types.ImageSource, layer types.BlobInfo, include func(string) bool, out map[string][]byte) error {
+	blob, _, err := imgSrc.GetBlob(ctx, layer, none.NoCache)
+	if err != nil {
+		return err
+	}
+
+	defer blob.Close()
+
+	uncompressed, _, err := compression.AutoDecompress(blob)
+	if err != nil {
+		return err
+	}
+
+	defer uncompressed.Close()
+
+	// Opaque whiteouts only apply to files from earlier layers, so the files
+	// from this layer are tracked separately.
+	fromThisLayer := sets.New[string]()
+
+	tr := tar.NewReader(uncompressed)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		name := cleanLayerPath(hdr.Name)
+		dir, base := path.Split(name)
+
+		if base == whiteoutOpaque {
+			for existing := range out {
+				if strings.HasPrefix(existing, dir) && !fromThisLayer.Has(existing) {
+					delete(out, existing)
+				}
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(base, whiteoutPrefix) {
+			removed := path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix))
+			delete(out, removed)
+
+			for existing := range out {
+				if strings.HasPrefix(existing, removed+"/") {
+					delete(out, existing)
+				}
+			}
+
+			continue
+		}
+
+		if hdr.Typeflag != tar.TypeReg || !include(name) {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return fmt.Errorf("could not read %s: %w", name, err)
+		}
+
+		out[name] = data
+		fromThisLayer.Insert(name)
+	}
+}
+
+// Layer tarballs may refer to files as ./path, /path, or path.
+func cleanLayerPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
@@ -0,0 +1,119 @@
+package containers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/containers/image/v5/oci/layout"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	imgspecs "github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLayerFile struct {
+	name string
+	// A nil content denotes a directory.
+	content *string
+}
+
+// Builds a gzipped layer tarball from the given files.
+func newTestLayer(t *testing.T, files []testLayerFile) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+
+	for _, file := range files {
+		if file.content == nil {
+			require.NoError(t, tw.WriteHeader(&tar.Header{Name: file.name, Typeflag: tar.TypeDir, Mode: 0o755}))
+			continue
+		}
+
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: file.name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(*file.content))}))
+		_, err := tw.Write([]byte(*file.content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	return buf.Bytes()
+}
+
+func TestReadImageFiles(t *testing.T) {
+	ctx := context.Background()
+
+	str := func(s string) *string { return &s }
+
+	layers := [][]testLayerFile{
+		{
+			{name: "release-manifests/", content: nil},
+			{name: "release-manifests/a.yaml", content: str("a: 1")},
+			{name: "release-manifests/b.yaml", content: str("b: 1")},
+			{name: "release-manifests/sub/c.yaml", content: str("c: 1")},
+			{name: "release-manifests/old/d.yaml", content: str("d: 1")},
+			{name: "usr/bin/cluster-version-operator", content: str("binary")},
+		},
+		{
+			{name: "./release-manifests/a.yaml", content: str("a: 2")},
+			{name: "release-manifests/.wh.b.yaml", content: str("")},
+			{name: "release-manifests/sub/e.yaml", content: str("e: 1")},
+			{name: "release-manifests/sub/.wh..wh..opq", content: str("")},
+			{name: "release-manifests/.wh.old", content: str("")},
+		},
+	}
+
+	dir := filepath.Join(t.TempDir(), "layout")
+	ref, err := layout.NewReference(dir, "source")
+	require.NoError(t, err)
+
+	dest, err := ref.NewImageDestination(ctx, nil)
+	require.NoError(t, err)
+
+	putBlob := func(b []byte, mediaType string, isConfig bool) imgspecv1.Descriptor {
+		info, err := dest.PutBlob(ctx, bytes.NewReader(b), types.BlobInfo{Digest: digest.FromBytes(b), Size: int64(len(b))}, none.NoCache, isConfig)
+		require.NoError(t, err)
+		return imgspecv1.Descriptor{MediaType: mediaType, Digest: info.Digest, Size: info.Size}
+	}
+
+	config, err := json.Marshal(imgspecv1.Image{Platform: imgspecv1.Platform{OS: "linux", Architecture: "amd64"}})
+	require.NoError(t, err)
+
+	m := imgspecv1.Manifest{
+		Versioned: imgspecs.Versioned{SchemaVersion: 2},
+		MediaType: imgspecv1.MediaTypeImageManifest,
+		Config:    putBlob(config, imgspecv1.MediaTypeImageConfig, true),
+	}
+
+	for _, layer := range layers {
+		m.Layers = append(m.Layers, putBlob(newTestLayer(t, layer), imgspecv1.MediaTypeImageLayerGzip, false))
+	}
+
+	rawManifest, err := json.Marshal(m)
+	require.NoError(t, err)
+
+	require.NoError(t, dest.PutManifest(ctx, rawManifest, nil))
+	require.NoError(t, dest.Commit(ctx, nil))
+	require.NoError(t, dest.Close())
+
+	files, err := ReadImageFiles(ctx, "oci:"+dir+":source", "", func(name string) bool {
+		return strings.HasPrefix(name, "release-manifests/")
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]byte{
+		"release-manifests/a.yaml":     []byte("a: 2"),
+		"release-manifests/sub/e.yaml": []byte("e: 1"),
+	}, files)
+}
@@ -0,0 +1,142 @@
+package containers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containers/image/v5/image"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+)
+
+// Platform describes a single platform-specific image.
+type Platform struct {
+	OS           string        `json:"os"`
+	Architecture string        `json:"architecture"`
+	Variant      string        `json:"variant,omitempty"`
+	Digest       digest.Digest `json:"digest"`
+}
+
+// Architectures whose variant is implied when an image does not set one, so
+// that, e.g., arm64 and arm64/v8 are treated as the same platform.
+var defaultVariants = map[string]string{
+	"amd64": "v1",
+	"arm64": "v8",
+}
+
+// Name returns the architecture of the platform along with its variant, if
+// any (e.g., amd64 or arm/v7). The default variant of an architecture is
+// omitted.
+func (p Platform) Name() string {
+	if p.Variant == "" || p.Variant == defaultVariants[p.Architecture] {
+		return p.Architecture
+	}
+
+	return fmt.Sprintf("%s/%s", p.Architecture, p.Variant)
+}
+
+// ImagePlatforms describes the platforms an image is available for.
+type ImagePlatforms struct {
+	// Digest is the digest of the top-level manifest.
+	Digest digest.Digest `json:"digest"`
+	// IsManifestList is true when the image is a manifest list or image index.
+	IsManifestList bool `json:"isManifestList"`
+	// Platforms contains each platform-specific image. For a single image,
+	// this contains just the image itself.
+	Platforms []Platform `json:"platforms"`
+}
+
+// Names returns the name of each platform.
+func (i *ImagePlatforms) Names() []string {
+	out := []string{}
+	for _, p := range i.Platforms {
+		out = append(out, p.Name())
+	}
+
+	return out
+}
+
+// GetImagePlatforms resolves the given image reference and returns the
+// platforms it is available for. Manifest lists are not followed beyond their
+// platform descriptors, whereas single images have their config inspected to
+// determine their platform.
+func GetImagePlatforms(ctx context.Context, ref, pullSecretPath string) (*ImagePlatforms, error) {
+	imgSrc, err := NewImageSource(ctx, ref, pullSecretPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer imgSrc.Close()
+
+	rawManifest, mimeType, err := imgSrc.GetManifest(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not get manifest for %q: %w", ref, err)
+	}
+
+	manifestDigest, err := manifest.Digest(rawManifest)
+	if err != nil {
+		return nil, err
+	}
+
+	if !manifest.MIMETypeIsMultiImage(mimeType) {
+		platform, err := getSingleImagePlatform(ctx, imgSrc, ref, pullSecretPath)
+		if err != nil {
+			return nil, err
+		}
+
+		platform.Digest = manifestDigest
+
+		return &ImagePlatforms{
+			Digest:    manifestDigest,
+			Platforms: []Platform{*platform},
+		}, nil
+	}
+
+	list, err := manifest.ListFromBlob(rawManifest, mimeType)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse manifest list for %q: %w", ref, err)
+	}
+
+	out := &ImagePlatforms{
+		Digest:         manifestDigest,
+		IsManifestList: true,
+		Platforms:      []Platform{},
+	}
+
+	for _, instanceDigest := range list.Instances() {
+		instance, err := list.Instance(instanceDigest)
+		if err != nil {
+			return nil, fmt.Errorf("could not get instance %s of %q: %w", instanceDigest, ref, err)
+		}
+
+		platform := Platform{Digest: instanceDigest}
+		if instance.ReadOnly.Platform != nil {
+			platform.OS = instance.ReadOnly.Platform.OS
+			platform.Architecture = instance.ReadOnly.Platform.Architecture
+			platform.Variant = instance.ReadOnly.Platform.Variant
+		}
+
+		out.Platforms = append(out.Platforms, platform)
+	}
+
+	return out, nil
+}
+
+func getSingleImagePlatform(ctx context.Context, imgSrc types.ImageSource, ref, pullSecretPath string) (*Platform, error) {
+	img, err := image.FromUnparsedImage(ctx, newSystemContext(ref, pullSecretPath), image.UnparsedInstance(imgSrc, nil))
+	if err != nil {
+		return nil, fmt.Errorf("could not read image %q: %w", ref, err)
+	}
+
+	info, err := img.Inspect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not inspect image %q: %w", ref, err)
+	}
+
+	return &Platform{
+		OS:           info.Os,
+		Architecture: info.Architecture,
+		Variant:      info.Variant,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package containers
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetImagePlatforms(t *testing.T) {
+	testCases := []struct {
+		name           string
+		arches         []string
+		isManifestList bool
+	}{
+		{
+			name:   "Single image",
+			arches: []string{"amd64"},
+		},
+		{
+			name:           "Image index",
+			arches:         []string{"amd64", "arm64", "ppc64le", "s390x"},
+			isManifestList: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			dir := filepath.Join(t.TempDir(), "image")
+			expectedDigest := newTestOCILayout(ctx, t, dir, testCase.arches...)
+
+			result, err := GetImagePlatforms(ctx, fmt.Sprintf("oci:%s:source", dir), "")
+			require.NoError(t, err)
+
+			assert.Equal(t, expectedDigest, result.Digest)
+			assert.Equal(t, testCase.isManifestList, result.IsManifestList)
+			assert.Equal(t, testCase.arches, result.Names())
+
+			for _, platform := range result.Platforms {
+				assert.Equal(t, "linux", platform.OS)
+				assert.NotEmpty(t, platform.Digest)
+			}
+
+			if !testCase.isManifestList {
+				assert.Equal(t, expectedDigest, result.Platforms[0].Digest)
+			}
+		})
+	}
+}
+
+func TestPlatformName(t *testing.T) {
+	assert.Equal(t, "amd64", Platform{Architecture: "amd64"}.Name())
+	assert.Equal(t, "arm64", Platform{Architecture: "arm64", Variant: "v8"}.Name())
+	assert.Equal(t, "arm/v7", Platform{Architecture: "arm", Variant: "v7"}.Name())
+}
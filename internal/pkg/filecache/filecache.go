@@ -0,0 +1,136 @@
+package filecache
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache is a simple cache which stores JSON-encoded values as files within a
+// directory. Entries expire once they are older than the cache's TTL.
+type Cache struct {
+	dir string
+	ttl time.Duration
+	// Allows the current time to be overridden for testing.
+	now func() time.Time
+}
+
+// New returns a cache which stores its entries in the given directory.
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{
+		dir: dir,
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+// NewUserCache returns a cache which stores its entries in a directory with
+// the given name within the user's cache directory (e.g., ~/.cache/<name>).
+func NewUserCache(name string, ttl time.Duration) (*Cache, error) {
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("could not determine user cache dir: %w", err)
+	}
+
+	return New(filepath.Join(userCacheDir, name), ttl), nil
+}
+
+// Get reads the unexpired entry for the given key into the given value and
+// returns whether it was found.
+func (c *Cache) Get(key string, value interface{}) (bool, error) {
+	path := c.path(key)
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if c.now().Sub(info.ModTime()) > c.ttl {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		// A corrupt entry is treated the same as a missing one so that it
+		// will be overwritten.
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Set stores the given value for the given key.
+func (c *Cache) Set(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temp file first and then rename it so that concurrent
+	// readers never see a partially-written entry.
+	tmp, err := os.CreateTemp(c.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chtimes(tmp.Name(), c.now(), c.now()); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// GetOrFetch returns the unexpired entry for the given key. If there is no
+// such entry, the fetch function is called and its result is stored. Failing
+// to read or write the cache is not fatal since the value can always be
+// fetched.
+func GetOrFetch[T any](c *Cache, key string, fetch func() (T, error)) (T, error) {
+	var cached T
+	if found, err := c.Get(key, &cached); err == nil && found {
+		return cached, nil
+	}
+
+	out, err := fetch()
+	if err != nil {
+		return out, err
+	}
+
+	// The cache is best-effort, so failing to write to it is ignored.
+	_ = c.Set(key, out)
+
+	return out, nil
+}
+
+// Keys are hashed so that they may contain characters which are not valid in
+// filenames.
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%x.json", sha256.Sum256([]byte(key))))
+}
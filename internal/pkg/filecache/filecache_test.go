@@ -0,0 +1,82 @@
+package filecache
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Date(2026, time.March, 5, 15, 37, 52, 0, time.UTC)
+
+	c := New(t.TempDir(), 5*time.Minute)
+	c.now = func() time.Time { return now }
+
+	out := []string{}
+	found, err := c.Get("amd64/releasestreams", &out)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	streams := []string{"4-stable", "4.23.0-0.ci", "4.23.0-0.nightly"}
+	require.NoError(t, c.Set("amd64/releasestreams", streams))
+
+	found, err = c.Get("amd64/releasestreams", &out)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, streams, out)
+
+	// Keys are distinct from one another.
+	found, err = c.Get("arm64/releasestreams", &out)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	// Entries expire after the TTL.
+	now = now.Add(6 * time.Minute)
+	found, err = c.Get("amd64/releasestreams", &out)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestCacheCorruptEntry(t *testing.T) {
+	c := New(t.TempDir(), time.Minute)
+
+	require.NoError(t, c.Set("key", "value"))
+	require.NoError(t, os.WriteFile(c.path("key"), []byte("not json"), 0o644))
+
+	out := ""
+	found, err := c.Get("key", &out)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestGetOrFetch(t *testing.T) {
+	c := New(t.TempDir(), time.Minute)
+
+	calls := 0
+	fetch := func() ([]string, error) {
+		calls++
+		return []string{"machine-config-operator", "rhel-coreos"}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		out, err := GetOrFetch(c, "components", fetch)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"machine-config-operator", "rhel-coreos"}, out)
+	}
+
+	assert.Equal(t, 1, calls)
+
+	// Errors are returned and not cached.
+	_, err := GetOrFetch(c, "failing", func() ([]string, error) {
+		return nil, fmt.Errorf("fetch failed")
+	})
+	assert.Error(t, err)
+
+	found, err := c.Get("failing", &[]string{})
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
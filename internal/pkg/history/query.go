@@ -0,0 +1,200 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+)
+
+// Period is the length of time results are grouped by.
+type Period string
+
+const (
+	Day  Period = "day"
+	Week Period = "week"
+)
+
+// Start returns the start of the period containing the given time. Weeks
+// start on Monday.
+func (p Period) Start(t time.Time) (time.Time, error) {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch p {
+	case Day:
+		return day, nil
+	case Week:
+		// Go's weeks start on Sunday.
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset), nil
+	}
+
+	return time.Time{}, fmt.Errorf("unknown period %q, must be one of: %s, %s", p, Day, Week)
+}
+
+// QueryOpts holds the options for querying the history.
+type QueryOpts struct {
+	// Streams limits the results to the given release streams. All release
+	// streams are included if empty.
+	Streams []string
+	// Since excludes release tags created before this time.
+	Since time.Time
+	// Period is the length of time results are grouped by. Defaults to Week.
+	Period Period
+}
+
+// AcceptanceTrend is the number of release tags in a release stream which
+// were accepted or rejected during a period.
+type AcceptanceTrend struct {
+	Stream          string    `json:"stream"`
+	Period          time.Time `json:"period"`
+	Accepted        int       `json:"accepted"`
+	Rejected        int       `json:"rejected"`
+	AcceptanceRatio float64   `json:"acceptanceRatio"`
+}
+
+// JobPassRate is the number of times a verification job passed or failed for
+// the release tags in a release stream during a period.
+type JobPassRate struct {
+	Stream   string    `json:"stream"`
+	Job      string    `json:"job"`
+	Kind     string    `json:"kind"`
+	Period   time.Time `json:"period"`
+	Passed   int       `json:"passed"`
+	Failed   int       `json:"failed"`
+	PassRate float64   `json:"passRate"`
+}
+
+// AcceptanceTrends returns the acceptance ratio for each release stream
+// during each period, ordered by release stream and period.
+func (s *Store) AcceptanceTrends(controller string, opts QueryOpts) ([]AcceptanceTrend, error) {
+	byKey := map[string]*AcceptanceTrend{}
+
+	err := s.forEachTag(controller, opts, func(tag Tag, period time.Time) {
+		key := tag.Stream + "/" + period.String()
+
+		trend, ok := byKey[key]
+		if !ok {
+			trend = &AcceptanceTrend{Stream: tag.Stream, Period: period}
+			byKey[key] = trend
+		}
+
+		switch releasecontroller.Phase(tag.Phase) {
+		case releasecontroller.PhaseAccepted:
+			trend.Accepted++
+		case releasecontroller.PhaseRejected:
+			trend.Rejected++
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	out := []AcceptanceTrend{}
+	for _, trend := range byKey {
+		if total := trend.Accepted + trend.Rejected; total != 0 {
+			trend.AcceptanceRatio = float64(trend.Accepted) / float64(total)
+			out = append(out, *trend)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Stream != out[j].Stream {
+			return out[i].Stream < out[j].Stream
+		}
+
+		return out[i].Period.Before(out[j].Period)
+	})
+
+	return out, nil
+}
+
+// JobPassRates returns the pass rate of each verification job in each release
+// stream during each period, ordered by release stream, job, and period. Jobs
+// which have not finished are not counted.
+func (s *Store) JobPassRates(controller string, opts QueryOpts) ([]JobPassRate, error) {
+	byKey := map[string]*JobPassRate{}
+
+	err := s.forEachTag(controller, opts, func(tag Tag, period time.Time) {
+		for name, job := range tag.Jobs {
+			key := tag.Stream + "/" + name + "/" + period.String()
+
+			rate, ok := byKey[key]
+			if !ok {
+				rate = &JobPassRate{Stream: tag.Stream, Job: name, Kind: job.Kind, Period: period}
+				byKey[key] = rate
+			}
+
+			switch job.State {
+			case "Succeeded":
+				rate.Passed++
+			case "Failed":
+				rate.Failed++
+			}
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	out := []JobPassRate{}
+	for _, rate := range byKey {
+		if total := rate.Passed + rate.Failed; total != 0 {
+			rate.PassRate = float64(rate.Passed) / float64(total)
+			out = append(out, *rate)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Stream != out[j].Stream {
+			return out[i].Stream < out[j].Stream
+		}
+
+		if out[i].Job != out[j].Job {
+			return out[i].Job < out[j].Job
+		}
+
+		return out[i].Period.Before(out[j].Period)
+	})
+
+	return out, nil
+}
+
+// Calls the given func with each matching release tag and the start of the
+// period it was created in.
+func (s *Store) forEachTag(controller string, opts QueryOpts, fn func(Tag, time.Time)) error {
+	period := opts.Period
+	if period == "" {
+		period = Week
+	}
+
+	// Validate the period up front so that an empty store does not hide an
+	// invalid period.
+	if _, err := period.Start(time.Time{}); err != nil {
+		return err
+	}
+
+	tags, err := s.List(controller, opts.Streams...)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		if tag.Created.Before(opts.Since) {
+			continue
+		}
+
+		start, err := period.Start(tag.Created)
+		if err != nil {
+			return err
+		}
+
+		fn(tag, start)
+	}
+
+	return nil
+}
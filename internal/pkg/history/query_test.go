@@ -0,0 +1,130 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodStart(t *testing.T) {
+	// A Wednesday.
+	ts := time.Date(2025, time.January, 8, 12, 34, 56, 0, time.UTC)
+
+	day, err := Day.Start(ts)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, time.January, 8, 0, 0, 0, 0, time.UTC), day)
+
+	week, err := Week.Start(ts)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC), week)
+
+	// Sundays belong to the week which started on the preceding Monday.
+	week, err = Week.Start(time.Date(2025, time.January, 12, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC), week)
+
+	_, err = Period("month").Start(ts)
+	assert.Error(t, err)
+}
+
+func newTestStoreWithTags(t *testing.T) *Store {
+	t.Helper()
+
+	s := newTestStore(t)
+
+	week1 := time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC)
+	week2 := week1.AddDate(0, 0, 7)
+
+	newTag := func(stream, name, phase string, created time.Time, jobs map[string]string) Tag {
+		tag := Tag{Controller: "rc", Stream: stream, Name: name, Phase: phase, Created: created, Jobs: map[string]Job{}}
+		for job, state := range jobs {
+			tag.Jobs[job] = Job{Kind: BlockingJob, State: state}
+		}
+
+		return tag
+	}
+
+	require.NoError(t, s.Put([]Tag{
+		newTag("nightly", "1", "Accepted", week1, map[string]string{"install": "Succeeded", "upgrade": "Succeeded"}),
+		newTag("nightly", "2", "Rejected", week1.Add(time.Hour), map[string]string{"install": "Succeeded", "upgrade": "Failed"}),
+		newTag("nightly", "3", "Rejected", week2, map[string]string{"install": "Failed", "upgrade": "Failed"}),
+		newTag("nightly", "4", "Ready", week2.Add(time.Hour), map[string]string{"install": "Pending", "upgrade": "Pending"}),
+		newTag("ci", "5", "Accepted", week2, map[string]string{"e2e": "Succeeded"}),
+	}))
+
+	return s
+}
+
+func TestAcceptanceTrends(t *testing.T) {
+	s := newTestStoreWithTags(t)
+
+	week1 := time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC)
+	week2 := week1.AddDate(0, 0, 7)
+
+	testCases := []struct {
+		name      string
+		opts      QueryOpts
+		expected  []AcceptanceTrend
+		expectErr bool
+	}{
+		{
+			name: "All streams",
+			expected: []AcceptanceTrend{
+				{Stream: "ci", Period: week2, Accepted: 1, AcceptanceRatio: 1},
+				{Stream: "nightly", Period: week1, Accepted: 1, Rejected: 1, AcceptanceRatio: 0.5},
+				{Stream: "nightly", Period: week2, Rejected: 1},
+			},
+		},
+		{
+			name: "Single stream since the second week",
+			opts: QueryOpts{Streams: []string{"nightly"}, Since: week2},
+			expected: []AcceptanceTrend{
+				{Stream: "nightly", Period: week2, Rejected: 1},
+			},
+		},
+		{
+			name: "By day",
+			opts: QueryOpts{Streams: []string{"ci"}, Period: Day},
+			expected: []AcceptanceTrend{
+				{Stream: "ci", Period: week2, Accepted: 1, AcceptanceRatio: 1},
+			},
+		},
+		{
+			name:      "Invalid period",
+			opts:      QueryOpts{Period: "month"},
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			trends, err := s.AcceptanceTrends("rc", testCase.opts)
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, trends)
+		})
+	}
+}
+
+func TestJobPassRates(t *testing.T) {
+	s := newTestStoreWithTags(t)
+
+	week1 := time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC)
+	week2 := week1.AddDate(0, 0, 7)
+
+	rates, err := s.JobPassRates("rc", QueryOpts{Streams: []string{"nightly"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []JobPassRate{
+		{Stream: "nightly", Job: "install", Kind: BlockingJob, Period: week1, Passed: 2, PassRate: 1},
+		{Stream: "nightly", Job: "install", Kind: BlockingJob, Period: week2, Failed: 1},
+		{Stream: "nightly", Job: "upgrade", Kind: BlockingJob, Period: week1, Passed: 1, Failed: 1, PassRate: 0.5},
+		{Stream: "nightly", Job: "upgrade", Kind: BlockingJob, Period: week2, Failed: 1},
+	}, rates)
+}
@@ -0,0 +1,167 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"golang.org/x/sync/errgroup"
+)
+
+// RecordOpts holds the options for recording a release stream.
+type RecordOpts struct {
+	// Concurrency is the maximum number of release tags to get the
+	// verification results for at once.
+	Concurrency int
+	// Now is the time the release tags are observed at. Defaults to the
+	// current time.
+	Now time.Time
+}
+
+// RecordResult summarizes what was recorded for a release stream.
+type RecordResult struct {
+	Controller string `json:"controller"`
+	Stream     string `json:"stream"`
+	// Observed is the number of release tags on the release controller.
+	Observed int `json:"observed"`
+	// New is the number of release tags which had not been recorded before.
+	New int `json:"new"`
+	// Updated is the number of release tags whose verification results were
+	// retrieved. Release tags which were previously recorded in a terminal
+	// phase are not retrieved again.
+	Updated int `json:"updated"`
+}
+
+// Record snapshots the release tags in the given release stream, along with
+// their phases and verification job results, into the store.
+func Record(ctx context.Context, s *Store, rc *releasecontroller.ReleaseController, stream string, opts RecordOpts) (*RecordResult, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	rs := rc.ReleaseStream(stream)
+
+	tags, err := rs.Tags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get tags for release stream %q: %w", stream, err)
+	}
+
+	// Release tags are recorded under the name of the web controller so that
+	// those recorded through a cache served by rcctl serve are recorded
+	// alongside those recorded from the release controller it caches. The name
+	// is a URL rather than a hostname for release controllers which are not
+	// served over HTTPS.
+	controller := rc.WebController().String()
+
+	out := &RecordResult{
+		Controller: controller,
+		Stream:     stream,
+		Observed:   len(tags.Tags),
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	if opts.Concurrency > 0 {
+		g.SetLimit(opts.Concurrency)
+	}
+
+	// Each release tag has its own slot so that the goroutines do not need to
+	// coordinate with each other or with this loop.
+	toPut := make([]Tag, len(tags.Tags))
+	updated := make([]bool, len(tags.Tags))
+
+	for i, release := range tags.Tags {
+		existing, err := s.Get(controller, stream, release.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if existing == nil {
+			out.New++
+		}
+
+		tag := Tag{
+			Controller:    controller,
+			Stream:        stream,
+			Name:          release.Name,
+			Phase:         release.Phase,
+			Pullspec:      release.Pullspec,
+			Created:       now,
+			FirstObserved: now,
+			LastObserved:  now,
+		}
+
+		if ts, err := releasecontroller.ParseTagTimestamp(release.Name); err == nil {
+			tag.Created = ts
+		}
+
+		// The verification results of a release tag do not change once it
+		// has reached a terminal phase and they have been recorded.
+		if existing != nil && existing.Phase == release.Phase && isTerminal(release.Phase) && len(existing.Jobs) != 0 {
+			toPut[i] = tag
+			continue
+		}
+
+		g.Go(func() error {
+			info, err := rs.Tag(gctx, release.Name)
+			if err != nil {
+				return fmt.Errorf("could not get verification results for release tag %q: %w", release.Name, err)
+			}
+
+			tag.Jobs = getJobs(info)
+			toPut[i] = tag
+			updated[i] = true
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	for _, u := range updated {
+		if u {
+			out.Updated++
+		}
+	}
+
+	if err := s.Put(toPut); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func getJobs(info *releasecontroller.APIReleaseInfo) map[string]Job {
+	out := map[string]Job{}
+
+	if info.Results == nil {
+		return out
+	}
+
+	for kind, jobs := range map[string]releasecontroller.VerificationStatusMap{
+		BlockingJob:  info.Results.BlockingJobs,
+		InformingJob: info.Results.InformingJobs,
+	} {
+		for name, status := range jobs {
+			if status == nil {
+				continue
+			}
+
+			out[name] = Job{
+				Kind:    kind,
+				State:   status.State,
+				URL:     status.URL,
+				Retries: status.Retries,
+			}
+		}
+	}
+
+	return out
+}
+
+func isTerminal(phase string) bool {
+	return phase == string(releasecontroller.PhaseAccepted) || phase == string(releasecontroller.PhaseRejected)
+}
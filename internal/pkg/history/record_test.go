@@ -0,0 +1,109 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord(t *testing.T) {
+	tags := &releasecontroller.ReleaseTags{
+		Name: "4.18.0-0.nightly",
+		Tags: []releasecontroller.Release{
+			{Name: "4.18.0-0.nightly-2025-01-02-000000", Phase: string(releasecontroller.PhaseReady)},
+			{Name: "4.18.0-0.nightly-2025-01-01-000000", Phase: string(releasecontroller.PhaseAccepted)},
+		},
+	}
+
+	tagRequests := &atomic.Int32{}
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var out interface{}
+
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/tags"):
+			out = tags
+		case strings.Contains(req.URL.Path, "/release/"):
+			tagRequests.Add(1)
+			out = &releasecontroller.APIReleaseInfo{
+				Results: &releasecontroller.VerificationJobsSummary{
+					BlockingJobs:  releasecontroller.VerificationStatusMap{"upgrade": {State: "Succeeded", URL: "https://prow/upgrade"}},
+					InformingJobs: releasecontroller.VerificationStatusMap{"e2e": {State: "Failed"}},
+				},
+			}
+		default:
+			http.NotFound(w, req)
+			return
+		}
+
+		//nolint:errcheck // This is test code.
+		json.NewEncoder(w).Encode(out)
+	}))
+
+	t.Cleanup(srv.Close)
+
+	rc := releasecontroller.New(srv.Listener.Addr().String(), &releasecontroller.ReleaseControllerConfig{Client: srv.Client()})
+
+	s := newTestStore(t)
+
+	now := time.Date(2025, time.January, 3, 0, 0, 0, 0, time.UTC)
+
+	result, err := Record(context.Background(), s, rc, "4.18.0-0.nightly", RecordOpts{Now: now})
+	require.NoError(t, err)
+	assert.Equal(t, &RecordResult{Controller: rc.String(), Stream: "4.18.0-0.nightly", Observed: 2, New: 2, Updated: 2}, result)
+	assert.Equal(t, int32(2), tagRequests.Load())
+
+	tag, err := s.Get(rc.String(), "4.18.0-0.nightly", "4.18.0-0.nightly-2025-01-01-000000")
+	require.NoError(t, err)
+
+	assert.Equal(t, &Tag{
+		Controller:    rc.String(),
+		Stream:        "4.18.0-0.nightly",
+		Name:          "4.18.0-0.nightly-2025-01-01-000000",
+		Phase:         string(releasecontroller.PhaseAccepted),
+		Created:       time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		FirstObserved: now,
+		LastObserved:  now,
+		Jobs: map[string]Job{
+			"upgrade": {Kind: BlockingJob, State: "Succeeded", URL: "https://prow/upgrade"},
+			"e2e":     {Kind: InformingJob, State: "Failed"},
+		},
+	}, tag)
+
+	// The accepted tag should not be retrieved again, but the one which is
+	// still being verified should be.
+	later := now.Add(time.Hour)
+
+	result, err = Record(context.Background(), s, rc, "4.18.0-0.nightly", RecordOpts{Now: later})
+	require.NoError(t, err)
+	assert.Equal(t, &RecordResult{Controller: rc.String(), Stream: "4.18.0-0.nightly", Observed: 2, Updated: 1}, result)
+	assert.Equal(t, int32(3), tagRequests.Load())
+
+	tag, err = s.Get(rc.String(), "4.18.0-0.nightly", "4.18.0-0.nightly-2025-01-01-000000")
+	require.NoError(t, err)
+	assert.Equal(t, now, tag.FirstObserved)
+	assert.Equal(t, later, tag.LastObserved)
+	assert.Len(t, tag.Jobs, 2)
+
+	// Release tags recorded through a cache are recorded under the release
+	// controller it caches.
+	cached := rc.WithWebController(releasecontroller.New(releasecontroller.Amd64OcpReleaseController, nil))
+
+	result, err = Record(context.Background(), s, cached, "4.18.0-0.nightly", RecordOpts{Now: later})
+	require.NoError(t, err)
+	assert.Equal(t, releasecontroller.Amd64OcpReleaseController, result.Controller)
+
+	tag, err = s.Get(releasecontroller.Amd64OcpReleaseController, "4.18.0-0.nightly", "4.18.0-0.nightly-2025-01-01-000000")
+	require.NoError(t, err)
+	require.NotNil(t, tag)
+	assert.Equal(t, releasecontroller.Amd64OcpReleaseController, tag.Controller)
+}
@@ -0,0 +1,276 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// SchemaVersion is the current version of the database schema. Whenever the
+// schema changes, this should be incremented and a migration added.
+const SchemaVersion int = 1
+
+var (
+	metaBucket       = []byte("meta")
+	schemaVersionKey = []byte("schemaVersion")
+	tagsBucket       = []byte("tags")
+)
+
+// Each migration brings the database from the schema version matching its
+// index to the next one.
+var migrations = []func(*bolt.Tx) error{
+	// 0 -> 1: Initial schema.
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(tagsBucket)
+		return err
+	},
+}
+
+const (
+	BlockingJob  string = "blocking"
+	InformingJob string = "informing"
+)
+
+// Job is the result of a single verification job for a release tag.
+type Job struct {
+	// Kind is either BlockingJob or InformingJob.
+	Kind    string `json:"kind"`
+	State   string `json:"state"`
+	URL     string `json:"url,omitempty"`
+	Retries int    `json:"retries,omitempty"`
+}
+
+// Tag is a release tag as it was last observed on the release controller.
+type Tag struct {
+	Controller string `json:"controller"`
+	Stream     string `json:"stream"`
+	Name       string `json:"name"`
+	Phase      string `json:"phase"`
+	Pullspec   string `json:"pullspec,omitempty"`
+	// Created is parsed from the release tag name if possible. Otherwise, it
+	// is when the release tag was first observed.
+	Created       time.Time      `json:"created"`
+	FirstObserved time.Time      `json:"firstObserved"`
+	LastObserved  time.Time      `json:"lastObserved"`
+	Jobs          map[string]Job `json:"jobs,omitempty"`
+}
+
+// Store is a local database of observed release tags.
+type Store struct {
+	db *bolt.DB
+}
+
+// DefaultPath returns the default location of the database, which is within
+// $XDG_DATA_HOME (e.g., ~/.local/share/rcctl/history.db).
+func DefaultPath() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("could not determine home dir: %w", err)
+		}
+
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+
+	return filepath.Join(dataHome, "rcctl", "history.db"), nil
+}
+
+// Open opens the database at the given path, creating it if it does not
+// exist, and migrates it to the current schema version. Databases created by
+// a newer version of the schema are rejected.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("could not open history database %s: %w", path, err)
+	}
+
+	if err := db.Update(migrate); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not migrate history database %s: %w", path, err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SchemaVersion returns the schema version of the database.
+func (s *Store) SchemaVersion() (int, error) {
+	out := 0
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		out, err = getSchemaVersion(tx)
+		return err
+	})
+
+	return out, err
+}
+
+func migrate(tx *bolt.Tx) error {
+	current, err := getSchemaVersion(tx)
+	if err != nil {
+		return err
+	}
+
+	if current > SchemaVersion {
+		return fmt.Errorf("schema version %d is newer than the supported version %d, upgrade rcctl", current, SchemaVersion)
+	}
+
+	for version := current; version < SchemaVersion; version++ {
+		if err := migrations[version](tx); err != nil {
+			return fmt.Errorf("could not migrate from schema version %d: %w", version, err)
+		}
+	}
+
+	meta, err := tx.CreateBucketIfNotExists(metaBucket)
+	if err != nil {
+		return err
+	}
+
+	return meta.Put(schemaVersionKey, []byte(strconv.Itoa(SchemaVersion)))
+}
+
+// A database without a schema version has not been initialized yet.
+func getSchemaVersion(tx *bolt.Tx) (int, error) {
+	meta := tx.Bucket(metaBucket)
+	if meta == nil {
+		return 0, nil
+	}
+
+	raw := meta.Get(schemaVersionKey)
+	if raw == nil {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(string(raw))
+	if err != nil {
+		return 0, fmt.Errorf("invalid schema version %q: %w", string(raw), err)
+	}
+
+	return version, nil
+}
+
+// Put stores the given release tags. Tags which were previously stored keep
+// their original first observed time, and job results which are no longer
+// reported by the release controller are retained.
+func (s *Store) Put(tags []Tag) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(tagsBucket)
+
+		for _, tag := range tags {
+			key := tagKey(tag.Controller, tag.Stream, tag.Name)
+
+			if raw := b.Get(key); raw != nil {
+				existing := Tag{}
+				if err := json.Unmarshal(raw, &existing); err != nil {
+					return fmt.Errorf("could not decode stored tag %s: %w", key, err)
+				}
+
+				tag = merge(existing, tag)
+			}
+
+			raw, err := json.Marshal(tag)
+			if err != nil {
+				return err
+			}
+
+			if err := b.Put(key, raw); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Get returns the stored release tag, if there is one.
+func (s *Store) Get(controller, stream, name string) (*Tag, error) {
+	var out *Tag
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(tagsBucket).Get(tagKey(controller, stream, name))
+		if raw == nil {
+			return nil
+		}
+
+		out = &Tag{}
+		return json.Unmarshal(raw, out)
+	})
+
+	return out, err
+}
+
+// List returns the stored release tags for the given release controller. If
+// any streams are given, only release tags from those streams are returned.
+func (s *Store) List(controller string, streams ...string) ([]Tag, error) {
+	prefixes := [][]byte{}
+	for _, stream := range streams {
+		prefixes = append(prefixes, tagKey(controller, stream, ""))
+	}
+
+	if len(prefixes) == 0 {
+		prefixes = append(prefixes, []byte(controller+"/"))
+	}
+
+	out := []Tag{}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(tagsBucket).Cursor()
+
+		for _, prefix := range prefixes {
+			for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+				tag := Tag{}
+				if err := json.Unmarshal(v, &tag); err != nil {
+					return fmt.Errorf("could not decode stored tag %s: %w", k, err)
+				}
+
+				out = append(out, tag)
+			}
+		}
+
+		return nil
+	})
+
+	return out, err
+}
+
+func merge(existing, tag Tag) Tag {
+	tag.FirstObserved = existing.FirstObserved
+	if existing.Created.Before(tag.Created) {
+		tag.Created = existing.Created
+	}
+
+	jobs := map[string]Job{}
+	for name, job := range existing.Jobs {
+		jobs[name] = job
+	}
+
+	for name, job := range tag.Jobs {
+		jobs[name] = job
+	}
+
+	tag.Jobs = jobs
+
+	return tag
+}
+
+// Keys are ordered by release controller and stream so that they may be
+// scanned by prefix.
+func tagKey(controller, stream, name string) []byte {
+	return []byte(controller + "/" + stream + "/" + name)
+}
@@ -0,0 +1,189 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	s, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { s.Close() })
+
+	return s
+}
+
+func TestOpen(t *testing.T) {
+	t.Run("New database is created at the current schema version", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nested", "history.db")
+
+		s, err := Open(path)
+		require.NoError(t, err)
+
+		version, err := s.SchemaVersion()
+		require.NoError(t, err)
+		assert.Equal(t, SchemaVersion, version)
+
+		require.NoError(t, s.Close())
+	})
+
+	t.Run("Data survives reopening", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "history.db")
+
+		s, err := Open(path)
+		require.NoError(t, err)
+		require.NoError(t, s.Put([]Tag{{Controller: "rc", Stream: "stream", Name: "tag", Phase: "Accepted"}}))
+		require.NoError(t, s.Close())
+
+		s, err = Open(path)
+		require.NoError(t, err)
+
+		defer s.Close()
+
+		tag, err := s.Get("rc", "stream", "tag")
+		require.NoError(t, err)
+		require.NotNil(t, tag)
+		assert.Equal(t, "Accepted", tag.Phase)
+	})
+
+	t.Run("Newer schema version is rejected", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "history.db")
+
+		db, err := bolt.Open(path, 0o644, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, db.Update(func(tx *bolt.Tx) error {
+			meta, err := tx.CreateBucket(metaBucket)
+			if err != nil {
+				return err
+			}
+
+			return meta.Put(schemaVersionKey, []byte("999"))
+		}))
+
+		require.NoError(t, db.Close())
+
+		_, err = Open(path)
+		assert.ErrorContains(t, err, "newer than the supported version")
+	})
+}
+
+func TestPut(t *testing.T) {
+	s := newTestStore(t)
+
+	first := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+
+	require.NoError(t, s.Put([]Tag{{
+		Controller:    "rc",
+		Stream:        "stream",
+		Name:          "tag",
+		Phase:         "Ready",
+		Created:       first,
+		FirstObserved: first,
+		LastObserved:  first,
+		Jobs: map[string]Job{
+			"install": {Kind: BlockingJob, State: "Succeeded"},
+			"upgrade": {Kind: BlockingJob, State: "Pending"},
+		},
+	}}))
+
+	require.NoError(t, s.Put([]Tag{{
+		Controller:    "rc",
+		Stream:        "stream",
+		Name:          "tag",
+		Phase:         "Rejected",
+		Created:       second,
+		FirstObserved: second,
+		LastObserved:  second,
+		Jobs: map[string]Job{
+			"upgrade": {Kind: BlockingJob, State: "Failed"},
+		},
+	}}))
+
+	tag, err := s.Get("rc", "stream", "tag")
+	require.NoError(t, err)
+
+	assert.Equal(t, &Tag{
+		Controller:    "rc",
+		Stream:        "stream",
+		Name:          "tag",
+		Phase:         "Rejected",
+		Created:       first,
+		FirstObserved: first,
+		LastObserved:  second,
+		Jobs: map[string]Job{
+			"install": {Kind: BlockingJob, State: "Succeeded"},
+			"upgrade": {Kind: BlockingJob, State: "Failed"},
+		},
+	}, tag)
+
+	missing, err := s.Get("rc", "stream", "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestList(t *testing.T) {
+	s := newTestStore(t)
+
+	require.NoError(t, s.Put([]Tag{
+		{Controller: "rc", Stream: "4.18.0-0.nightly", Name: "a"},
+		{Controller: "rc", Stream: "4.18.0-0.nightly", Name: "b"},
+		// Shares a prefix with the above stream.
+		{Controller: "rc", Stream: "4.18.0-0.nightly-arm64", Name: "c"},
+		{Controller: "rc", Stream: "4-stable", Name: "d"},
+		{Controller: "other", Stream: "4-stable", Name: "e"},
+	}))
+
+	testCases := []struct {
+		name       string
+		controller string
+		streams    []string
+		expected   []string
+	}{
+		{
+			name:       "All streams",
+			controller: "rc",
+			expected:   []string{"a", "b", "c", "d"},
+		},
+		{
+			name:       "Single stream",
+			controller: "rc",
+			streams:    []string{"4.18.0-0.nightly"},
+			expected:   []string{"a", "b"},
+		},
+		{
+			name:       "Multiple streams",
+			controller: "rc",
+			streams:    []string{"4-stable", "4.18.0-0.nightly-arm64"},
+			expected:   []string{"c", "d"},
+		},
+		{
+			name:       "Other controller",
+			controller: "other",
+			expected:   []string{"e"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			tags, err := s.List(testCase.controller, testCase.streams...)
+			require.NoError(t, err)
+
+			names := []string{}
+			for _, tag := range tags {
+				names = append(names, tag.Name)
+			}
+
+			assert.ElementsMatch(t, testCase.expected, names)
+		})
+	}
+}
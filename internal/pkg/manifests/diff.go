@@ -0,0 +1,377 @@
+package manifests
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+)
+
+// FeatureSetAnnotation is set on manifests which are only applied for
+// particular cluster feature sets. The payload may ship several variants of
+// the same object which differ only by this annotation.
+const FeatureSetAnnotation string = "release.openshift.io/feature-set"
+
+// ObjectKey identifies an object across release payloads.
+type ObjectKey struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// FeatureSet distinguishes the per-feature set variants of an object.
+	FeatureSet string `json:"featureSet,omitempty"`
+	// ClusterProfiles distinguishes the per-cluster profile variants of an
+	// object which a release payload ships more than once. It is the
+	// comma-separated, sorted list of cluster profiles the manifest is
+	// annotated for, so that the key remains comparable. It is empty for
+	// objects which only have a single variant so that an object gaining or
+	// losing a cluster profile is reported as a change.
+	ClusterProfiles string `json:"clusterProfiles,omitempty"`
+}
+
+// String returns the key in the form apiVersion Kind namespace/name, e.g.,
+// apps/v1 DaemonSet openshift-machine-config-operator/machine-config-daemon.
+func (k ObjectKey) String() string {
+	gv := schema.GroupVersion{Group: k.Group, Version: k.Version}.String()
+
+	name := k.Name
+	if k.Namespace != "" {
+		name = k.Namespace + "/" + name
+	}
+
+	out := fmt.Sprintf("%s %s %s", gv, k.Kind, name)
+	if k.FeatureSet != "" {
+		out += fmt.Sprintf(" (%s)", k.FeatureSet)
+	}
+
+	if k.ClusterProfiles != "" {
+		out += fmt.Sprintf(" [%s]", k.ClusterProfiles)
+	}
+
+	return out
+}
+
+// Key returns the key which identifies the manifest's object, without telling
+// apart its per-cluster profile variants (see Diff).
+func (m Manifest) Key() ObjectKey {
+	gv, _ := schema.ParseGroupVersion(m.APIVersion)
+
+	key := ObjectKey{
+		Group:     gv.Group,
+		Version:   gv.Version,
+		Kind:      m.Kind,
+		Namespace: m.Namespace,
+		Name:      m.Name,
+	}
+
+	if metadata, ok := m.Object["metadata"].(map[string]interface{}); ok {
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			key.FeatureSet, _ = annotations[FeatureSetAnnotation].(string)
+		}
+	}
+
+	return key
+}
+
+// Returns the comma-separated, sorted list of cluster profiles the manifest is
+// annotated for.
+func (m Manifest) clusterProfiles() string {
+	profiles := []string{}
+
+	if metadata, ok := m.Object["metadata"].(map[string]interface{}); ok {
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			for annotation := range annotations {
+				if profile, ok := strings.CutPrefix(annotation, clusterProfileAnnotationPrefix); ok {
+					profiles = append(profiles, profile)
+				}
+			}
+		}
+	}
+
+	sort.Strings(profiles)
+
+	return strings.Join(profiles, ",")
+}
+
+// ObjectRef refers to an object within a release payload.
+type ObjectRef struct {
+	ObjectKey
+	Component string `json:"component,omitempty"`
+	File      string `json:"file"`
+}
+
+// FieldChange is a single changed field within an object. From is absent when
+// the field was added and To is absent when the field was removed.
+type FieldChange struct {
+	// Path is the path to the field, e.g.,
+	// spec.template.spec.containers[name=machine-config-daemon].image.
+	Path string      `json:"path"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// ObjectDiff describes how an object which is present in both release
+// payloads changed.
+type ObjectDiff struct {
+	ObjectRef
+	// FromFile is the file the object was in within the first payload, when
+	// it differs from the file it is in within the second.
+	FromFile string        `json:"fromFile,omitempty"`
+	Changes  []FieldChange `json:"changes"`
+}
+
+// DiffResult describes how the manifests changed between two release payloads.
+type DiffResult struct {
+	From    string       `json:"from"`
+	To      string       `json:"to"`
+	Added   []ObjectRef  `json:"added"`
+	Removed []ObjectRef  `json:"removed"`
+	Changed []ObjectDiff `json:"changed"`
+}
+
+// Diff matches the objects from two sets of manifests by their group,
+// version, kind, namespace, name, and feature set and describes which were
+// added, removed, and changed. Objects which either payload ships once per
+// cluster profile are additionally matched by the cluster profiles they are
+// annotated for. Objects which moved between files but are otherwise
+// identical are not considered changed.
+func Diff(from, to []Manifest) *DiffResult {
+	variants := keysWithVariants(from).Union(keysWithVariants(to))
+
+	fromByKey := indexByKey(from, variants)
+	toByKey := indexByKey(to, variants)
+
+	out := &DiffResult{
+		Added:   []ObjectRef{},
+		Removed: []ObjectRef{},
+		Changed: []ObjectDiff{},
+	}
+
+	for _, key := range sortedKeys(fromByKey) {
+		if _, ok := toByKey[key]; !ok {
+			out.Removed = append(out.Removed, newObjectRef(key, fromByKey[key]))
+		}
+	}
+
+	for _, key := range sortedKeys(toByKey) {
+		newer := toByKey[key]
+
+		older, ok := fromByKey[key]
+		if !ok {
+			out.Added = append(out.Added, newObjectRef(key, newer))
+			continue
+		}
+
+		changes := diffValues("", older.Object, newer.Object)
+		if len(changes) == 0 {
+			continue
+		}
+
+		diff := ObjectDiff{
+			ObjectRef: newObjectRef(key, newer),
+			Changes:   changes,
+		}
+
+		if older.File != newer.File {
+			diff.FromFile = older.File
+		}
+
+		out.Changed = append(out.Changed, diff)
+	}
+
+	return out
+}
+
+// Recursively compares two values and returns the changed fields. Lists whose
+// elements all have a name (e.g., containers) are compared by name so that
+// reordering or inserting an element does not mark every following element
+// as changed; other lists are compared by index.
+func diffValues(path string, from, to interface{}) []FieldChange {
+	fromMap, fromIsMap := from.(map[string]interface{})
+	toMap, toIsMap := to.(map[string]interface{})
+
+	if fromIsMap && toIsMap {
+		return diffMaps(path, fromMap, toMap)
+	}
+
+	fromList, fromIsList := from.([]interface{})
+	toList, toIsList := to.([]interface{})
+
+	if fromIsList && toIsList {
+		return diffLists(path, fromList, toList)
+	}
+
+	if reflect.DeepEqual(from, to) {
+		return nil
+	}
+
+	return []FieldChange{{Path: path, From: from, To: to}}
+}
+
+func diffMaps(path string, from, to map[string]interface{}) []FieldChange {
+	out := []FieldChange{}
+
+	for _, key := range sortedUnion(from, to) {
+		out = append(out, diffPresent(joinPath(path, key), from, to, key)...)
+	}
+
+	return out
+}
+
+// Compares the values for the given key, which may be missing from either map.
+func diffPresent(path string, from, to map[string]interface{}, key string) []FieldChange {
+	fromValue, inFrom := from[key]
+	toValue, inTo := to[key]
+
+	switch {
+	case !inFrom:
+		return []FieldChange{{Path: path, To: toValue}}
+	case !inTo:
+		return []FieldChange{{Path: path, From: fromValue}}
+	default:
+		return diffValues(path, fromValue, toValue)
+	}
+}
+
+func sortedUnion(a, b map[string]interface{}) []string {
+	keys := sets.KeySet(a).Union(sets.KeySet(b))
+	return sets.List(keys)
+}
+
+func diffLists(path string, from, to []interface{}) []FieldChange {
+	fromByName, fromNamed := indexByName(from)
+	toByName, toNamed := indexByName(to)
+
+	if !fromNamed || !toNamed {
+		out := []FieldChange{}
+
+		for i := 0; i < len(from) || i < len(to); i++ {
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+
+			switch {
+			case i >= len(from):
+				out = append(out, FieldChange{Path: elemPath, To: to[i]})
+			case i >= len(to):
+				out = append(out, FieldChange{Path: elemPath, From: from[i]})
+			default:
+				out = append(out, diffValues(elemPath, from[i], to[i])...)
+			}
+		}
+
+		return out
+	}
+
+	out := []FieldChange{}
+
+	for _, name := range sortedUnion(fromByName, toByName) {
+		elemPath := fmt.Sprintf("%s[name=%s]", path, name)
+		out = append(out, diffPresent(elemPath, fromByName, toByName, name)...)
+	}
+
+	return out
+}
+
+// Indexes the list elements by their name field, returning false if any
+// element is not a map with a unique name.
+func indexByName(in []interface{}) (map[string]interface{}, bool) {
+	out := map[string]interface{}{}
+
+	for _, item := range in {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		name, ok := m["name"].(string)
+		if !ok || name == "" {
+			return nil, false
+		}
+
+		if _, ok := out[name]; ok {
+			return nil, false
+		}
+
+		out[name] = m
+	}
+
+	return out, true
+}
+
+func joinPath(path, key string) string {
+	// Keys such as annotation names may contain dots.
+	if strings.ContainsAny(key, ".[]") {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// Returns the keys of the objects which are shipped more than once, e.g., once
+// per cluster profile.
+func keysWithVariants(in []Manifest) sets.Set[ObjectKey] {
+	seen := sets.New[ObjectKey]()
+	out := sets.New[ObjectKey]()
+
+	for _, m := range in {
+		key := m.Key()
+		if seen.Has(key) {
+			out.Insert(key)
+		}
+
+		seen.Insert(key)
+	}
+
+	return out
+}
+
+// The objects whose keys are in variants are told apart by their cluster
+// profiles. Objects which still share a key cannot be told apart, so only the
+// first is kept and the others are reported rather than silently replacing it.
+func indexByKey(in []Manifest, variants sets.Set[ObjectKey]) map[ObjectKey]Manifest {
+	out := map[ObjectKey]Manifest{}
+	for _, m := range in {
+		key := m.Key()
+		if variants.Has(key) {
+			key.ClusterProfiles = m.clusterProfiles()
+		}
+
+		if existing, ok := out[key]; ok {
+			klog.Warningf("Ignoring %s in %s, which is also in %s", key, m.File, existing.File)
+			continue
+		}
+
+		out[key] = m
+	}
+
+	return out
+}
+
+func sortedKeys(in map[ObjectKey]Manifest) []ObjectKey {
+	out := []ObjectKey{}
+	for key := range in {
+		out = append(out, key)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].String() < out[j].String()
+	})
+
+	return out
+}
+
+func newObjectRef(key ObjectKey, m Manifest) ObjectRef {
+	return ObjectRef{
+		ObjectKey: key,
+		Component: m.Component,
+		File:      m.File,
+	}
+}
@@ -0,0 +1,260 @@
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseManifests(t *testing.T, files map[string]string) []Manifest {
+	t.Helper()
+
+	out := []Manifest{}
+
+	for file, data := range files {
+		parsed, err := Parse(file, []byte(data))
+		require.NoError(t, err)
+		out = append(out, parsed...)
+	}
+
+	return out
+}
+
+func TestDiff(t *testing.T) {
+	from := parseManifests(t, map[string]string{
+		"0000_80_machine-config_00_daemon.yaml": `apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: machine-config-daemon
+  namespace: openshift-machine-config-operator
+  annotations:
+    include.release.openshift.io/self-managed-high-availability: "true"
+spec:
+  template:
+    spec:
+      containers:
+      - name: machine-config-daemon
+        image: quay.io/mcd:1
+      - name: kube-rbac-proxy
+        image: quay.io/proxy:1
+      tolerations:
+      - operator: Exists
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: removed
+  namespace: openshift-machine-config-operator
+`,
+		"0000_10_config-operator_01_featuregate-Default.crd.yaml": `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: featuregates.config.openshift.io
+  annotations:
+    release.openshift.io/feature-set: Default
+spec:
+  group: config.openshift.io
+`,
+		"0000_10_config-operator_01_featuregate-TechPreviewNoUpgrade.crd.yaml": `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: featuregates.config.openshift.io
+  annotations:
+    release.openshift.io/feature-set: TechPreviewNoUpgrade
+spec:
+  group: config.openshift.io
+`,
+	})
+
+	to := parseManifests(t, map[string]string{
+		"0000_80_machine-config_00_daemonset.yaml": `apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: machine-config-daemon
+  namespace: openshift-machine-config-operator
+  annotations:
+    include.release.openshift.io/self-managed-high-availability: "false"
+spec:
+  template:
+    spec:
+      containers:
+      - name: kube-rbac-proxy
+        image: quay.io/proxy:1
+      - name: machine-config-daemon
+        image: quay.io/mcd:2
+        args: ["start"]
+      tolerations:
+      - operator: Exists
+      - key: node-role.kubernetes.io/master
+`,
+		"0000_80_machine-config_01_rbac.yaml": `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: added
+`,
+		"0000_10_config-operator_01_featuregate-Default.crd.yaml": `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: featuregates.config.openshift.io
+  annotations:
+    release.openshift.io/feature-set: Default
+spec:
+  group: config.openshift.io
+`,
+		"0000_10_config-operator_01_featuregate-TechPreviewNoUpgrade.crd.yaml": `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: featuregates.config.openshift.io
+  annotations:
+    release.openshift.io/feature-set: TechPreviewNoUpgrade
+spec:
+  group: config.openshift.io
+  scope: Cluster
+`,
+	})
+
+	result := Diff(from, to)
+
+	require.Len(t, result.Added, 1)
+	assert.Equal(t, "rbac.authorization.k8s.io/v1 ClusterRole added", result.Added[0].String())
+
+	require.Len(t, result.Removed, 1)
+	assert.Equal(t, "v1 ConfigMap openshift-machine-config-operator/removed", result.Removed[0].String())
+
+	require.Len(t, result.Changed, 2)
+
+	assert.Equal(t, ObjectDiff{
+		ObjectRef: ObjectRef{
+			ObjectKey: ObjectKey{
+				Group:      "apiextensions.k8s.io",
+				Version:    "v1",
+				Kind:       "CustomResourceDefinition",
+				Name:       "featuregates.config.openshift.io",
+				FeatureSet: "TechPreviewNoUpgrade",
+			},
+			Component: "config-operator",
+			File:      "0000_10_config-operator_01_featuregate-TechPreviewNoUpgrade.crd.yaml",
+		},
+		Changes: []FieldChange{{Path: "spec.scope", To: "Cluster"}},
+	}, result.Changed[0])
+
+	daemon := result.Changed[1]
+	assert.Equal(t, "0000_80_machine-config_00_daemon.yaml", daemon.FromFile)
+	assert.Equal(t, "0000_80_machine-config_00_daemonset.yaml", daemon.File)
+	assert.Equal(t, []FieldChange{
+		{Path: `metadata.annotations["include.release.openshift.io/self-managed-high-availability"]`, From: "true", To: "false"},
+		{Path: "spec.template.spec.containers[name=machine-config-daemon].args", To: []interface{}{"start"}},
+		{Path: "spec.template.spec.containers[name=machine-config-daemon].image", From: "quay.io/mcd:1", To: "quay.io/mcd:2"},
+		{Path: "spec.template.spec.tolerations[1]", To: map[string]interface{}{"key": "node-role.kubernetes.io/master"}},
+	}, daemon.Changes)
+}
+
+func TestDiffIdentical(t *testing.T) {
+	manifests := parseManifests(t, map[string]string{
+		"0000_80_machine-config_00_namespace.yaml": mcoManifests,
+	})
+
+	result := Diff(manifests, manifests)
+	assert.Empty(t, result.Added)
+	assert.Empty(t, result.Removed)
+	assert.Empty(t, result.Changed)
+}
+
+func TestDiffClusterProfiles(t *testing.T) {
+	variants := func(image string) []Manifest {
+		return parseManifests(t, map[string]string{
+			"0000_50_operator_00_deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: operator
+  namespace: openshift-operator
+  annotations:
+    include.release.openshift.io/self-managed-high-availability: "true"
+    include.release.openshift.io/single-node-developer: "true"
+spec:
+  replicas: 1
+`,
+			"0000_50_operator_00_deployment-hypershift.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: operator
+  namespace: openshift-operator
+  annotations:
+    include.release.openshift.io/ibm-cloud-managed: "true"
+spec:
+  template:
+    spec:
+      containers:
+      - name: operator
+        image: ` + image + `
+`,
+		})
+	}
+
+	result := Diff(variants("quay.io/operator:1"), variants("quay.io/operator:2"))
+	assert.Empty(t, result.Added)
+	assert.Empty(t, result.Removed)
+
+	// Only the variant which changed is reported, rather than the variants
+	// being compared with each other.
+	require.Len(t, result.Changed, 1)
+	assert.Equal(t, "apps/v1 Deployment openshift-operator/operator [ibm-cloud-managed]", result.Changed[0].String())
+	assert.Equal(t, "0000_50_operator_00_deployment-hypershift.yaml", result.Changed[0].File)
+	assert.Equal(t, []FieldChange{
+		{Path: "spec.template.spec.containers[name=operator].image", From: "quay.io/operator:1", To: "quay.io/operator:2"},
+	}, result.Changed[0].Changes)
+}
+
+func TestDiffClusterProfileAdded(t *testing.T) {
+	deployment := func(annotations string) []Manifest {
+		return parseManifests(t, map[string]string{
+			"0000_50_operator_00_deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: operator
+  namespace: openshift-operator
+  annotations:
+` + annotations + `spec:
+  replicas: 1
+`,
+		})
+	}
+
+	from := deployment(`    include.release.openshift.io/self-managed-high-availability: "true"
+`)
+	to := deployment(`    include.release.openshift.io/self-managed-high-availability: "true"
+    include.release.openshift.io/single-node-developer: "true"
+`)
+
+	// The object only has a single variant, so gaining a cluster profile is
+	// a change rather than a removal and an addition.
+	result := Diff(from, to)
+	assert.Empty(t, result.Added)
+	assert.Empty(t, result.Removed)
+	require.Len(t, result.Changed, 1)
+	assert.Equal(t, "apps/v1 Deployment openshift-operator/operator", result.Changed[0].String())
+	assert.Equal(t, []FieldChange{
+		{Path: `metadata.annotations["include.release.openshift.io/single-node-developer"]`, To: "true"},
+	}, result.Changed[0].Changes)
+
+	// Once the object is split into per-cluster profile variants, the variant
+	// with the same cluster profiles is matched with the original object.
+	to = append(deployment(`    include.release.openshift.io/self-managed-high-availability: "true"
+`), parseManifests(t, map[string]string{
+		"0000_50_operator_00_deployment-hypershift.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: operator
+  namespace: openshift-operator
+  annotations:
+    include.release.openshift.io/ibm-cloud-managed: "true"
+`,
+	})...)
+
+	result = Diff(from, to)
+	assert.Empty(t, result.Removed)
+	assert.Empty(t, result.Changed)
+	require.Len(t, result.Added, 1)
+	assert.Equal(t, "apps/v1 Deployment openshift-operator/operator [ibm-cloud-managed]", result.Added[0].String())
+}
@@ -0,0 +1,265 @@
+package manifests
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// DefaultClusterProfile is the cluster profile for regular self-managed
+	// clusters.
+	DefaultClusterProfile string = "self-managed-high-availability"
+
+	clusterProfileAnnotationPrefix string = "include.release.openshift.io/"
+	defaultFeatureSet              string = "Default"
+)
+
+// Feature sets in the order they are usually presented. Any others found in
+// the payload are listed after these.
+var knownFeatureSets = []string{defaultFeatureSet, "TechPreviewNoUpgrade", "DevPreviewNoUpgrade", "OKD"}
+
+// GateState is whether a feature gate is enabled within a feature set.
+type GateState string
+
+const (
+	GateEnabled  GateState = "enabled"
+	GateDisabled GateState = "disabled"
+	// GateAbsent means the feature gate is not known to the feature set.
+	GateAbsent GateState = ""
+)
+
+// FeatureGate is the state of a single feature gate within each feature set.
+type FeatureGate struct {
+	Name string `json:"name"`
+	// States maps each feature set to whether the gate is enabled in it.
+	States map[string]GateState `json:"states"`
+}
+
+// FeatureGateMatrix describes which feature gates are enabled in each feature
+// set of a release payload.
+type FeatureGateMatrix struct {
+	Release        string        `json:"release,omitempty"`
+	ClusterProfile string        `json:"clusterProfile"`
+	FeatureSets    []string      `json:"featureSets"`
+	Gates          []FeatureGate `json:"gates"`
+}
+
+// FeatureGateChange is a feature gate whose state changed within a feature
+// set between two releases.
+type FeatureGateChange struct {
+	Name       string    `json:"name"`
+	FeatureSet string    `json:"featureSet"`
+	From       GateState `json:"from"`
+	To         GateState `json:"to"`
+}
+
+// FeatureGateDiff describes which feature gates changed between two releases.
+type FeatureGateDiff struct {
+	From           string              `json:"from"`
+	To             string              `json:"to"`
+	ClusterProfile string              `json:"clusterProfile"`
+	Changes        []FeatureGateChange `json:"changes"`
+}
+
+// FeatureGates builds the feature gate matrix from the FeatureGate manifests
+// shipped in a release payload for the given cluster profile. The payload
+// ships one FeatureGate per feature set and cluster profile whose status
+// lists the enabled and disabled gates.
+func FeatureGates(in []Manifest, clusterProfile string) (*FeatureGateMatrix, error) {
+	byFeatureSet := map[string]map[string]GateState{}
+
+	for _, m := range in {
+		if m.Kind != "FeatureGate" || !includedInProfile(m, clusterProfile) {
+			continue
+		}
+
+		u := &unstructured.Unstructured{Object: m.Object}
+
+		states, err := gateStatesFromStatus(u)
+		if err != nil {
+			return nil, fmt.Errorf("could not read feature gates from %s: %w", m.File, err)
+		}
+
+		for _, featureSet := range featureSetsFor(u) {
+			byFeatureSet[featureSet] = states
+		}
+	}
+
+	if len(byFeatureSet) == 0 {
+		return nil, fmt.Errorf("no FeatureGate manifests found for cluster profile %q", clusterProfile)
+	}
+
+	out := &FeatureGateMatrix{
+		ClusterProfile: clusterProfile,
+		FeatureSets:    orderFeatureSets(byFeatureSet),
+		Gates:          []FeatureGate{},
+	}
+
+	names := sets.New[string]()
+	for _, states := range byFeatureSet {
+		names.Insert(sets.KeySet(states).UnsortedList()...)
+	}
+
+	for _, name := range sets.List(names) {
+		gate := FeatureGate{Name: name, States: map[string]GateState{}}
+		for featureSet, states := range byFeatureSet {
+			gate.States[featureSet] = states[name]
+		}
+
+		out.Gates = append(out.Gates, gate)
+	}
+
+	return out, nil
+}
+
+// DiffFeatureGates returns the feature gates whose state changed within each
+// feature set between the two matrices.
+func DiffFeatureGates(from, to *FeatureGateMatrix) *FeatureGateDiff {
+	out := &FeatureGateDiff{
+		From:           from.Release,
+		To:             to.Release,
+		ClusterProfile: to.ClusterProfile,
+		Changes:        []FeatureGateChange{},
+	}
+
+	fromGates := indexGates(from)
+	toGates := indexGates(to)
+
+	names := sets.KeySet(fromGates).Union(sets.KeySet(toGates))
+
+	featureSets := append([]string{}, to.FeatureSets...)
+	for _, featureSet := range from.FeatureSets {
+		if !sets.New(featureSets...).Has(featureSet) {
+			featureSets = append(featureSets, featureSet)
+		}
+	}
+
+	for _, name := range sets.List(names) {
+		for _, featureSet := range featureSets {
+			fromState := fromGates[name].States[featureSet]
+			toState := toGates[name].States[featureSet]
+
+			if fromState != toState {
+				out.Changes = append(out.Changes, FeatureGateChange{
+					Name:       name,
+					FeatureSet: featureSet,
+					From:       fromState,
+					To:         toState,
+				})
+			}
+		}
+	}
+
+	return out
+}
+
+func indexGates(in *FeatureGateMatrix) map[string]FeatureGate {
+	out := map[string]FeatureGate{}
+	for _, gate := range in.Gates {
+		out[gate.Name] = gate
+	}
+
+	return out
+}
+
+// Reads the enabled and disabled gates from the first entry of
+// status.featureGates. The payload only has an entry for its own version.
+func gateStatesFromStatus(u *unstructured.Unstructured) (map[string]GateState, error) {
+	featureGates, _, err := unstructured.NestedSlice(u.Object, "status", "featureGates")
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]GateState{}
+
+	if len(featureGates) == 0 {
+		return out, nil
+	}
+
+	details, ok := featureGates[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected status.featureGates entry %v", featureGates[0])
+	}
+
+	for field, state := range map[string]GateState{"enabled": GateEnabled, "disabled": GateDisabled} {
+		// An empty list may be serialized as null.
+		gates, _ := details[field].([]interface{})
+
+		for _, gate := range gates {
+			gateMap, ok := gate.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if name, ok := gateMap["name"].(string); ok {
+				out[name] = state
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// Determines which feature sets a FeatureGate manifest applies to from its
+// feature-set annotation, which may list several, falling back to its
+// spec.featureSet.
+func featureSetsFor(u *unstructured.Unstructured) []string {
+	if annotation := u.GetAnnotations()[FeatureSetAnnotation]; annotation != "" {
+		out := []string{}
+		for _, featureSet := range strings.Split(annotation, ",") {
+			if featureSet = strings.TrimSpace(featureSet); featureSet != "" {
+				out = append(out, featureSet)
+			}
+		}
+
+		return out
+	}
+
+	featureSet, _, _ := unstructured.NestedString(u.Object, "spec", "featureSet")
+	if featureSet == "" {
+		featureSet = defaultFeatureSet
+	}
+
+	return []string{featureSet}
+}
+
+// Manifests which are not annotated for any cluster profile apply to all of
+// them.
+func includedInProfile(m Manifest, clusterProfile string) bool {
+	annotations := (&unstructured.Unstructured{Object: m.Object}).GetAnnotations()
+
+	hasProfile := false
+	for key := range annotations {
+		if strings.HasPrefix(key, clusterProfileAnnotationPrefix) {
+			hasProfile = true
+			break
+		}
+	}
+
+	return !hasProfile || annotations[clusterProfileAnnotationPrefix+clusterProfile] == "true"
+}
+
+func orderFeatureSets(byFeatureSet map[string]map[string]GateState) []string {
+	out := []string{}
+
+	for _, featureSet := range knownFeatureSets {
+		if _, ok := byFeatureSet[featureSet]; ok {
+			out = append(out, featureSet)
+		}
+	}
+
+	others := []string{}
+	for featureSet := range byFeatureSet {
+		if !sets.New(knownFeatureSets...).Has(featureSet) {
+			others = append(others, featureSet)
+		}
+	}
+
+	sort.Strings(others)
+
+	return append(out, others...)
+}
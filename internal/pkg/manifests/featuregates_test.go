@@ -0,0 +1,98 @@
+package manifests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFeatureGateManifest(profile, featureSet string, enabled, disabled []string) string {
+	out := fmt.Sprintf(`apiVersion: config.openshift.io/v1
+kind: FeatureGate
+metadata:
+  name: cluster
+  annotations:
+    include.release.openshift.io/%s: "true"
+    release.openshift.io/feature-set: %s
+spec: {}
+status:
+  featureGates:
+  - version: ""
+    enabled:
+`, profile, featureSet)
+
+	for _, gate := range enabled {
+		out += fmt.Sprintf("    - name: %s\n", gate)
+	}
+
+	out += "    disabled:\n"
+	for _, gate := range disabled {
+		out += fmt.Sprintf("    - name: %s\n", gate)
+	}
+
+	return out
+}
+
+func newFeatureGateManifests(t *testing.T, defaultEnabled, defaultDisabled []string) []Manifest {
+	t.Helper()
+
+	return parseManifests(t, map[string]string{
+		"0000_50_cluster-config-api_featureGate-SelfManagedHA-Default.yaml":              newFeatureGateManifest(DefaultClusterProfile, "Default", defaultEnabled, defaultDisabled),
+		"0000_50_cluster-config-api_featureGate-SelfManagedHA-TechPreviewNoUpgrade.yaml": newFeatureGateManifest(DefaultClusterProfile, "TechPreviewNoUpgrade,DevPreviewNoUpgrade", []string{"OnClusterBuild", "PinnedImages"}, []string{}),
+		"0000_50_cluster-config-api_featureGate-Hypershift-Default.yaml":                 newFeatureGateManifest("ibm-cloud-managed", "Default", []string{"OnClusterBuild", "PinnedImages"}, []string{}),
+		"0000_50_cluster-config-api_featureGate-SelfManagedHA-CustomNoUpgrade.yaml":      newFeatureGateManifest(DefaultClusterProfile, "CustomNoUpgrade", []string{}, []string{}),
+		"0000_80_machine-config_00_namespace.yaml":                                       mcoManifests,
+	})
+}
+
+func TestFeatureGates(t *testing.T) {
+	matrix, err := FeatureGates(newFeatureGateManifests(t, []string{}, []string{"OnClusterBuild", "PinnedImages"}), DefaultClusterProfile)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Default", "TechPreviewNoUpgrade", "DevPreviewNoUpgrade", "CustomNoUpgrade"}, matrix.FeatureSets)
+	assert.Equal(t, []FeatureGate{
+		{
+			Name: "OnClusterBuild",
+			States: map[string]GateState{
+				"Default":              GateDisabled,
+				"TechPreviewNoUpgrade": GateEnabled,
+				"DevPreviewNoUpgrade":  GateEnabled,
+				"CustomNoUpgrade":      GateAbsent,
+			},
+		},
+		{
+			Name: "PinnedImages",
+			States: map[string]GateState{
+				"Default":              GateDisabled,
+				"TechPreviewNoUpgrade": GateEnabled,
+				"DevPreviewNoUpgrade":  GateEnabled,
+				"CustomNoUpgrade":      GateAbsent,
+			},
+		},
+	}, matrix.Gates)
+
+	_, err = FeatureGates(newFeatureGateManifests(t, nil, nil), "single-node-developer")
+	assert.Error(t, err)
+}
+
+func TestDiffFeatureGates(t *testing.T) {
+	from, err := FeatureGates(newFeatureGateManifests(t, []string{}, []string{"OnClusterBuild", "PinnedImages"}), DefaultClusterProfile)
+	require.NoError(t, err)
+	from.Release = "4.22.0"
+
+	to, err := FeatureGates(newFeatureGateManifests(t, []string{"OnClusterBuild", "NewGate"}, []string{"PinnedImages"}), DefaultClusterProfile)
+	require.NoError(t, err)
+	to.Release = "4.23.0"
+
+	assert.Equal(t, &FeatureGateDiff{
+		From:           "4.22.0",
+		To:             "4.23.0",
+		ClusterProfile: DefaultClusterProfile,
+		Changes: []FeatureGateChange{
+			{Name: "NewGate", FeatureSet: "Default", From: GateAbsent, To: GateEnabled},
+			{Name: "OnClusterBuild", FeatureSet: "Default", From: GateDisabled, To: GateEnabled},
+		},
+	}, DiffFeatureGates(from, to))
+}
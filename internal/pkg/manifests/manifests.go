@@ -0,0 +1,316 @@
+package manifests
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/containers"
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Dir is the directory within a release payload image which contains the
+// manifests applied by the cluster-version-operator.
+const Dir string = "release-manifests"
+
+// Matches the run-level prefix of a manifest filename (e.g., 0000_80_).
+var runLevelPrefix = regexp.MustCompile(`^\d{4}_\d{2}_`)
+
+// Manifest is a single YAML document from a file in the release manifests.
+type Manifest struct {
+	// File is the name of the file within the release manifests directory.
+	File string `json:"file"`
+	// Line is the line within the file the document starts on.
+	Line int `json:"line"`
+	// Component is the component which ships the manifest, as determined by its
+	// filename.
+	Component  string `json:"component,omitempty"`
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name,omitempty"`
+	// Raw is the YAML document itself.
+	Raw []byte `json:"-"`
+	// Object is the parsed YAML document.
+	Object map[string]interface{} `json:"-"`
+}
+
+// String returns a short description of the manifest, e.g.,
+// ConfigMap/openshift-machine-config-operator/machine-config-osimageurl.
+func (m Manifest) String() string {
+	parts := []string{m.Kind}
+	if m.Namespace != "" {
+		parts = append(parts, m.Namespace)
+	}
+
+	return strings.Join(append(parts, m.Name), "/")
+}
+
+// Filter selects which manifests to include. Empty fields match everything.
+type Filter struct {
+	// Components are matched against the component named in the manifest
+	// filename. An -operator suffix is ignored on both sides so that the image
+	// name (e.g., machine-config-operator) may be used for components whose
+	// manifests are named differently (e.g., 0000_80_machine-config_...).
+	Components []string
+	// Kinds are matched case-insensitively against the manifest kind.
+	Kinds []string
+	// Names are glob patterns (e.g., machine-config-*) matched against the
+	// manifest name.
+	Names []string
+}
+
+// Matches returns true if the given manifest is selected by the filter.
+func (f Filter) Matches(m Manifest) bool {
+	return matchesAny(f.Components, func(component string) bool {
+		return normalizeComponent(component) == normalizeComponent(m.Component)
+	}) && matchesAny(f.Kinds, func(kind string) bool {
+		return strings.EqualFold(kind, m.Kind)
+	}) && matchesAny(f.Names, func(pattern string) bool {
+		matched, err := path.Match(pattern, m.Name)
+		return err == nil && matched
+	})
+}
+
+// Match is a single line of a manifest which matches a search pattern.
+type Match struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Component string `json:"component,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Text      string `json:"text"`
+}
+
+// Read reads and parses the release manifests from the given release payload
+// pullspec by pulling its image layers.
+func Read(ctx context.Context, pullspec, authfilePath string) ([]Manifest, error) {
+	files, err := containers.ReadImageFiles(ctx, pullspec, authfilePath, func(name string) bool {
+		return strings.HasPrefix(name, Dir+"/")
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%q does not contain any release manifests", pullspec)
+	}
+
+	out := []Manifest{}
+
+	for _, name := range sortedFileNames(files) {
+		parsed, err := Parse(strings.TrimPrefix(name, Dir+"/"), files[name])
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, parsed...)
+	}
+
+	return out, nil
+}
+
+// Parse splits the given file into its YAML documents. Only files with a YAML
+// or JSON extension are parsed, so files such as image-references and
+// release-metadata are skipped. Empty documents are omitted.
+func Parse(file string, data []byte) ([]Manifest, error) {
+	switch filepath.Ext(file) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return nil, nil
+	}
+
+	out := []Manifest{}
+
+	for _, doc := range splitDocuments(data) {
+		if len(bytes.TrimSpace(doc.raw)) == 0 {
+			continue
+		}
+
+		u := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(doc.raw, &u.Object); err != nil {
+			return nil, fmt.Errorf("could not parse %s at line %d: %w", file, doc.line, err)
+		}
+
+		// Documents consisting only of comments unmarshal to nothing.
+		if len(u.Object) == 0 {
+			continue
+		}
+
+		out = append(out, Manifest{
+			File:       file,
+			Line:       doc.line,
+			Component:  componentFromFilename(file),
+			APIVersion: u.GetAPIVersion(),
+			Kind:       u.GetKind(),
+			Namespace:  u.GetNamespace(),
+			Name:       u.GetName(),
+			Raw:        doc.raw,
+			Object:     u.Object,
+		})
+	}
+
+	return out, nil
+}
+
+// Select returns the manifests which are matched by the given filter.
+func Select(in []Manifest, f Filter) []Manifest {
+	out := []Manifest{}
+
+	for _, m := range in {
+		if f.Matches(m) {
+			out = append(out, m)
+		}
+	}
+
+	return out
+}
+
+// Grep returns each line of the given manifests which matches the given
+// pattern.
+func Grep(in []Manifest, pattern *regexp.Regexp) []Match {
+	out := []Match{}
+
+	for _, m := range in {
+		scanner := bufio.NewScanner(bytes.NewReader(m.Raw))
+		scanner.Buffer(nil, len(m.Raw)+1)
+
+		for i := 0; scanner.Scan(); i++ {
+			if !pattern.Match(scanner.Bytes()) {
+				continue
+			}
+
+			out = append(out, Match{
+				File:      m.File,
+				Line:      m.Line + i,
+				Component: m.Component,
+				Kind:      m.Kind,
+				Namespace: m.Namespace,
+				Name:      m.Name,
+				Text:      scanner.Text(),
+			})
+		}
+	}
+
+	return out
+}
+
+// Write writes the given manifests into the given directory using their
+// original filenames. When only some of the documents in a file were
+// selected, only those documents are written to it.
+func Write(dir string, in []Manifest) error {
+	byFile := map[string][][]byte{}
+	files := []string{}
+
+	for _, m := range in {
+		if _, ok := byFile[m.File]; !ok {
+			files = append(files, m.File)
+		}
+
+		byFile[m.File] = append(byFile[m.File], bytes.TrimRight(m.Raw, "\n"))
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		data := append(bytes.Join(byFile[file], []byte("\n---\n")), '\n')
+
+		path := filepath.Join(dir, filepath.FromSlash(file))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return fmt.Errorf("could not write %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+type document struct {
+	line int
+	raw  []byte
+}
+
+// Splits a multi-document YAML file on its document separators while keeping
+// track of which line each document starts on.
+func splitDocuments(data []byte) []document {
+	out := []document{}
+	current := document{line: 1}
+
+	lines := bytes.SplitAfter(data, []byte("\n"))
+
+	for i, line := range lines {
+		if isDocumentSeparator(line) {
+			out = append(out, current)
+			current = document{line: i + 2}
+			continue
+		}
+
+		current.raw = append(current.raw, line...)
+	}
+
+	return append(out, current)
+}
+
+func isDocumentSeparator(line []byte) bool {
+	trimmed := bytes.TrimRight(line, " \t\r\n")
+	return bytes.Equal(trimmed, []byte("---")) || bytes.HasPrefix(line, []byte("--- "))
+}
+
+// Determines the component from a manifest filename such as
+// 0000_80_machine-config_00_clusterreader_clusterrole.yaml (machine-config).
+func componentFromFilename(file string) string {
+	base := path.Base(file)
+
+	if !runLevelPrefix.MatchString(base) {
+		return ""
+	}
+
+	component, _, _ := strings.Cut(runLevelPrefix.ReplaceAllString(base, ""), "_")
+	return strings.TrimSuffix(component, path.Ext(component))
+}
+
+func normalizeComponent(component string) string {
+	return strings.TrimSuffix(component, "-operator")
+}
+
+// Returns true if there are no values or if any of the values match.
+func matchesAny(values []string, matches func(string) bool) bool {
+	if len(values) == 0 {
+		return true
+	}
+
+	for _, value := range values {
+		if matches(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedFileNames(files map[string][]byte) []string {
+	out := []string{}
+	for name := range files {
+		out = append(out, name)
+	}
+
+	sort.Strings(out)
+
+	return out
+}
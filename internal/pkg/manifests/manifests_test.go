@@ -0,0 +1,171 @@
+package manifests
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mcoManifests string = `# A leading comment.
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: openshift-machine-config-operator
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: machine-config-osimageurl
+  namespace: openshift-machine-config-operator
+data:
+  baseOSContainerImage: quay.io/openshift/os@sha256:abc
+---
+`
+
+const ingressManifests string = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: ingress-operator
+  namespace: openshift-ingress-operator
+spec:
+  template:
+    spec:
+      containers:
+      - image: quay.io/openshift/os@sha256:def
+`
+
+func parseTestManifests(t *testing.T) []Manifest {
+	t.Helper()
+
+	out := []Manifest{}
+
+	for file, data := range map[string]string{
+		"0000_80_machine-config_00_namespace.yaml":            mcoManifests,
+		"0000_50_cluster-ingress-operator_02-deployment.yaml": ingressManifests,
+		"image-references": `{"kind": "ImageStream"}`,
+	} {
+		parsed, err := Parse(file, []byte(data))
+		require.NoError(t, err)
+		out = append(out, parsed...)
+	}
+
+	return out
+}
+
+func TestParse(t *testing.T) {
+	parsed, err := Parse("0000_80_machine-config_00_namespace.yaml", []byte(mcoManifests))
+	require.NoError(t, err)
+	require.Len(t, parsed, 2)
+
+	assert.Equal(t, "machine-config", parsed[0].Component)
+	assert.Equal(t, "Namespace/openshift-machine-config-operator", parsed[0].String())
+	assert.Equal(t, 1, parsed[0].Line)
+
+	assert.Equal(t, "v1", parsed[1].APIVersion)
+	assert.Equal(t, "ConfigMap/openshift-machine-config-operator/machine-config-osimageurl", parsed[1].String())
+	assert.Equal(t, 7, parsed[1].Line)
+
+	skipped, err := Parse("image-references", []byte("{}"))
+	assert.NoError(t, err)
+	assert.Empty(t, skipped)
+
+	_, err = Parse("0000_00_invalid.yaml", []byte("kind: [\n"))
+	assert.Error(t, err)
+}
+
+func TestSelect(t *testing.T) {
+	all := parseTestManifests(t)
+
+	testCases := []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{
+			name:     "No filter",
+			expected: []string{"Namespace/openshift-machine-config-operator", "ConfigMap/openshift-machine-config-operator/machine-config-osimageurl", "Deployment/openshift-ingress-operator/ingress-operator"},
+		},
+		{
+			name:     "Component by image name",
+			filter:   Filter{Components: []string{"machine-config-operator"}},
+			expected: []string{"Namespace/openshift-machine-config-operator", "ConfigMap/openshift-machine-config-operator/machine-config-osimageurl"},
+		},
+		{
+			name:     "Component by filename",
+			filter:   Filter{Components: []string{"cluster-ingress-operator"}},
+			expected: []string{"Deployment/openshift-ingress-operator/ingress-operator"},
+		},
+		{
+			name:     "Kind is case-insensitive",
+			filter:   Filter{Kinds: []string{"configmap", "Deployment"}},
+			expected: []string{"ConfigMap/openshift-machine-config-operator/machine-config-osimageurl", "Deployment/openshift-ingress-operator/ingress-operator"},
+		},
+		{
+			name:     "Name glob",
+			filter:   Filter{Names: []string{"machine-config-*"}},
+			expected: []string{"ConfigMap/openshift-machine-config-operator/machine-config-osimageurl"},
+		},
+		{
+			name:     "Combined",
+			filter:   Filter{Components: []string{"machine-config"}, Kinds: []string{"Deployment"}},
+			expected: []string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			selected := []string{}
+			for _, m := range Select(all, testCase.filter) {
+				selected = append(selected, m.String())
+			}
+
+			assert.ElementsMatch(t, testCase.expected, selected)
+		})
+	}
+}
+
+func TestGrep(t *testing.T) {
+	matches := Grep(parseTestManifests(t), regexp.MustCompile(`quay\.io/openshift/os`))
+	require.Len(t, matches, 2)
+
+	byFile := map[string]Match{}
+	for _, match := range matches {
+		byFile[match.File] = match
+	}
+
+	assert.Equal(t, Match{
+		File:      "0000_80_machine-config_00_namespace.yaml",
+		Line:      13,
+		Component: "machine-config",
+		Kind:      "ConfigMap",
+		Namespace: "openshift-machine-config-operator",
+		Name:      "machine-config-osimageurl",
+		Text:      "  baseOSContainerImage: quay.io/openshift/os@sha256:abc",
+	}, byFile["0000_80_machine-config_00_namespace.yaml"])
+
+	assert.Equal(t, 10, byFile["0000_50_cluster-ingress-operator_02-deployment.yaml"].Line)
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	selected := Select(parseTestManifests(t), Filter{Kinds: []string{"ConfigMap", "Deployment"}})
+	require.NoError(t, Write(dir, selected))
+
+	mco, err := os.ReadFile(filepath.Join(dir, "0000_80_machine-config_00_namespace.yaml"))
+	require.NoError(t, err)
+
+	// Only the selected document is written.
+	reparsed, err := Parse("0000_80_machine-config_00_namespace.yaml", mco)
+	require.NoError(t, err)
+	require.Len(t, reparsed, 1)
+	assert.Equal(t, "machine-config-osimageurl", reparsed[0].Name)
+
+	ingress, err := os.ReadFile(filepath.Join(dir, "0000_50_cluster-ingress-operator_02-deployment.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, ingressManifests, string(ingress))
+}
@@ -0,0 +1,309 @@
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/containers"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/containers/image/v5/docker/reference"
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1alpha1 "github.com/openshift/api/operator/v1alpha1"
+	"golang.org/x/sync/errgroup"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+const (
+	// The name used for the release payload image itself.
+	payloadImageName string = "release"
+)
+
+// Opts holds the options for mirroring a release payload.
+type Opts struct {
+	// To is either a registry repository (registry.host/org/repo) or an OCI
+	// layout directory (oci:path/to/dir).
+	To string
+	// Concurrency is the maximum number of images that may be copied at once.
+	// OCI layout destinations are always copied one image at a time.
+	Concurrency int
+	containers.CopyOpts
+}
+
+// Image describes a single image that was mirrored.
+type Image struct {
+	Name        string `json:"name"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Digest      string `json:"digest"`
+	Skipped     bool   `json:"skipped"`
+}
+
+// Result describes the outcome of mirroring a release payload.
+type Result struct {
+	Release string  `json:"release"`
+	To      string  `json:"to"`
+	Images  []Image `json:"images"`
+}
+
+// Release mirrors the release payload image and every component image it
+// references to the destination described by opts. Image digests are
+// preserved when mirroring to a registry so that the payload's digested
+// component references continue to resolve from the mirror. Images mirrored
+// to an OCI layout are converted to OCI manifests if needed, which changes
+// their digests.
+func Release(ctx context.Context, ri *releasecontroller.ReleaseInfo, opts Opts) (*Result, error) {
+	images, err := getImagesToMirror(ri, opts.To)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := opts.Concurrency
+	if containers.IsOCILayoutReference(opts.To) && concurrency != 1 {
+		// Each OCI layout destination rewrites index.json upon commit, so
+		// concurrent copies into the same directory would clobber each other.
+		klog.Infof("Copying images to OCI layout %s one at a time", opts.To)
+		concurrency = 1
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	if concurrency > 0 {
+		g.SetLimit(concurrency)
+	}
+
+	mu := &sync.Mutex{}
+	out := &Result{
+		Release: ri.Metadata.Version,
+		To:      opts.To,
+		Images:  []Image{},
+	}
+
+	for _, img := range images {
+		g.Go(func() error {
+			klog.Infof("Copying %s (%s) to %s", img.Name, img.Source, img.Destination)
+
+			result, err := containers.CopyImage(ctx, img.Source, img.Destination, opts.CopyOpts)
+			if err != nil {
+				return fmt.Errorf("could not mirror %s: %w", img.Name, err)
+			}
+
+			if result.Skipped {
+				klog.Infof("%s already present at %s, skipping", img.Name, img.Destination)
+			}
+
+			img.Digest = result.Digest.String()
+			img.Skipped = result.Skipped
+
+			mu.Lock()
+			defer mu.Unlock()
+			out.Images = append(out.Images, img)
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(out.Images, func(i, j int) bool {
+		return out.Images[i].Name < out.Images[j].Name
+	})
+
+	return out, nil
+}
+
+// Determines the source and destination for the payload image and each of its
+// components. Destinations are tagged following the same convention as $ oc
+// adm release mirror: the payload is tagged with its version and each
+// component is tagged with <version>-<component>.
+func getImagesToMirror(ri *releasecontroller.ReleaseInfo, to string) ([]Image, error) {
+	if ri.References == nil {
+		return nil, fmt.Errorf("release %q does not have any image references", ri.Metadata.Version)
+	}
+
+	version := sanitizeTag(ri.Metadata.Version)
+	if version == "" {
+		return nil, fmt.Errorf("release does not have a version")
+	}
+
+	payloadSource, err := getPayloadSource(ri)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []Image{
+		{
+			Name:        payloadImageName,
+			Source:      payloadSource,
+			Destination: getDestination(to, version),
+		},
+	}
+
+	for _, tag := range ri.References.Spec.Tags {
+		if tag.From == nil || tag.From.Name == "" {
+			continue
+		}
+
+		out = append(out, Image{
+			Name:        tag.Name,
+			Source:      tag.From.Name,
+			Destination: getDestination(to, sanitizeTag(fmt.Sprintf("%s-%s", version, tag.Name))),
+		})
+	}
+
+	return out, nil
+}
+
+// Prefers the digested form of the payload pullspec so that the mirrored
+// payload is exactly the one that was inspected.
+func getPayloadSource(ri *releasecontroller.ReleaseInfo) (string, error) {
+	pullspec := ri.Image
+	if pullspec == "" {
+		pullspec = ri.ReleasePullspec
+	}
+
+	if pullspec == "" {
+		return "", fmt.Errorf("release %q does not have a pullspec", ri.Metadata.Version)
+	}
+
+	if ri.Digest == "" {
+		return pullspec, nil
+	}
+
+	named, err := reference.ParseNormalizedNamed(pullspec)
+	if err != nil {
+		return "", fmt.Errorf("could not parse release pullspec %q: %w", pullspec, err)
+	}
+
+	return fmt.Sprintf("%s@%s", reference.TrimNamed(named).String(), ri.Digest), nil
+}
+
+func getDestination(to, tag string) string {
+	if containers.IsOCILayoutReference(to) {
+		return fmt.Sprintf("%s:%s", to, tag)
+	}
+
+	return fmt.Sprintf("%s:%s", strings.TrimSuffix(to, "/"), tag)
+}
+
+// Image tags may only contain [A-Za-z0-9_.-], so replace anything else (e.g.,
+// the + found in some OKD versions).
+func sanitizeTag(tag string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, tag)
+}
+
+// Returns the source repositories for the mirrored images mapped to the
+// repository they were mirrored to.
+func getSourceRepositories(result *Result) (map[string]string, error) {
+	if containers.IsOCILayoutReference(result.To) {
+		return nil, fmt.Errorf("mirror sets cannot be generated for OCI layout destination %q", result.To)
+	}
+
+	mirrorRepo := strings.TrimSuffix(result.To, "/")
+
+	out := map[string]string{}
+	for _, img := range result.Images {
+		named, err := reference.ParseNormalizedNamed(img.Source)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse source %q: %w", img.Source, err)
+		}
+
+		out[reference.TrimNamed(named).String()] = mirrorRepo
+	}
+
+	return out, nil
+}
+
+// ImageDigestMirrorSet returns an ImageDigestMirrorSet which directs a cluster
+// to pull the mirrored images from the mirror.
+func ImageDigestMirrorSet(name string, result *Result) (*configv1.ImageDigestMirrorSet, error) {
+	repos, err := getSourceRepositories(result)
+	if err != nil {
+		return nil, err
+	}
+
+	idms := &configv1.ImageDigestMirrorSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: configv1.GroupVersion.String(),
+			Kind:       "ImageDigestMirrorSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+
+	for _, source := range sortedKeys(repos) {
+		idms.Spec.ImageDigestMirrors = append(idms.Spec.ImageDigestMirrors, configv1.ImageDigestMirrors{
+			Source:  source,
+			Mirrors: []configv1.ImageMirror{configv1.ImageMirror(repos[source])},
+		})
+	}
+
+	return idms, nil
+}
+
+// ImageContentSourcePolicy returns an ImageContentSourcePolicy which directs a
+// cluster to pull the mirrored images from the mirror. This is for older
+// clusters which do not support ImageDigestMirrorSets.
+func ImageContentSourcePolicy(name string, result *Result) (*operatorv1alpha1.ImageContentSourcePolicy, error) {
+	repos, err := getSourceRepositories(result)
+	if err != nil {
+		return nil, err
+	}
+
+	icsp := &operatorv1alpha1.ImageContentSourcePolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: operatorv1alpha1.GroupVersion.String(),
+			Kind:       "ImageContentSourcePolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+
+	for _, source := range sortedKeys(repos) {
+		icsp.Spec.RepositoryDigestMirrors = append(icsp.Spec.RepositoryDigestMirrors, operatorv1alpha1.RepositoryDigestMirrors{
+			Source:  source,
+			Mirrors: []string{repos[source]},
+		})
+	}
+
+	return icsp, nil
+}
+
+// MirrorSetName returns a name suitable for the ImageDigestMirrorSet and
+// ImageContentSourcePolicy objects for the given mirror destination.
+func MirrorSetName(to string) string {
+	name := strings.ToLower(filepath.Base(strings.TrimSuffix(to, "/")))
+	name = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '.' {
+			return r
+		}
+
+		return '-'
+	}, name)
+
+	return strings.Trim(name, "-.") + "-mirror"
+}
+
+func sortedKeys(in map[string]string) []string {
+	out := make([]string, 0, len(in))
+	for key := range in {
+		out = append(out, key)
+	}
+
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,144 @@
+package mirror
+
+import (
+	"testing"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	imagev1 "github.com/openshift/api/image/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	payloadDigest string = "sha256:aa6cd007e204673ceafa266fe1cf359b386cbb1e34c785ae2dd1856e8f61b71c"
+	mcoDigest     string = "sha256:bec41abb841b042589766901962cf99bb7894bd673d4f71602523aa0c255a4f4"
+	rhcosDigest   string = "sha256:eccbe17a07f73e67689e2617855525c81de69fcb06f188b29b46c69c95c92242"
+)
+
+func newReleaseInfo() *releasecontroller.ReleaseInfo {
+	return &releasecontroller.ReleaseInfo{
+		Image:  "quay.io/openshift-release-dev/ocp-release:4.21.4-x86_64",
+		Digest: payloadDigest,
+		Metadata: releasecontroller.Metadata{
+			Version: "4.21.4",
+		},
+		References: &imagev1.ImageStream{
+			Spec: imagev1.ImageStreamSpec{
+				Tags: []imagev1.TagReference{
+					{
+						Name: "machine-config-operator",
+						From: &corev1.ObjectReference{Kind: "DockerImage", Name: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + mcoDigest},
+					},
+					{
+						Name: "rhel-coreos",
+						From: &corev1.ObjectReference{Kind: "DockerImage", Name: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + rhcosDigest},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGetImagesToMirror(t *testing.T) {
+	testCases := []struct {
+		name     string
+		to       string
+		expected []Image
+	}{
+		{
+			name: "Registry",
+			to:   "localhost:5000/ocp/release/",
+			expected: []Image{
+				{
+					Name:        "release",
+					Source:      "quay.io/openshift-release-dev/ocp-release@" + payloadDigest,
+					Destination: "localhost:5000/ocp/release:4.21.4",
+				},
+				{
+					Name:        "machine-config-operator",
+					Source:      "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + mcoDigest,
+					Destination: "localhost:5000/ocp/release:4.21.4-machine-config-operator",
+				},
+				{
+					Name:        "rhel-coreos",
+					Source:      "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + rhcosDigest,
+					Destination: "localhost:5000/ocp/release:4.21.4-rhel-coreos",
+				},
+			},
+		},
+		{
+			name: "OCI layout",
+			to:   "oci:/tmp/mirror",
+			expected: []Image{
+				{
+					Name:        "release",
+					Source:      "quay.io/openshift-release-dev/ocp-release@" + payloadDigest,
+					Destination: "oci:/tmp/mirror:4.21.4",
+				},
+				{
+					Name:        "machine-config-operator",
+					Source:      "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + mcoDigest,
+					Destination: "oci:/tmp/mirror:4.21.4-machine-config-operator",
+				},
+				{
+					Name:        "rhel-coreos",
+					Source:      "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + rhcosDigest,
+					Destination: "oci:/tmp/mirror:4.21.4-rhel-coreos",
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			images, err := getImagesToMirror(newReleaseInfo(), testCase.to)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, images)
+		})
+	}
+
+	_, err := getImagesToMirror(&releasecontroller.ReleaseInfo{}, "localhost:5000/ocp/release")
+	assert.Error(t, err)
+}
+
+func TestMirrorSets(t *testing.T) {
+	images, err := getImagesToMirror(newReleaseInfo(), "localhost:5000/ocp/release")
+	require.NoError(t, err)
+
+	result := &Result{
+		Release: "4.21.4",
+		To:      "localhost:5000/ocp/release",
+		Images:  images,
+	}
+
+	name := MirrorSetName(result.To)
+	assert.Equal(t, "release-mirror", name)
+
+	idms, err := ImageDigestMirrorSet(name, result)
+	require.NoError(t, err)
+	assert.Equal(t, "ImageDigestMirrorSet", idms.Kind)
+	assert.Equal(t, "config.openshift.io/v1", idms.APIVersion)
+	require.Len(t, idms.Spec.ImageDigestMirrors, 2)
+	assert.Equal(t, "quay.io/openshift-release-dev/ocp-release", idms.Spec.ImageDigestMirrors[0].Source)
+	assert.Equal(t, "quay.io/openshift-release-dev/ocp-v4.0-art-dev", idms.Spec.ImageDigestMirrors[1].Source)
+	for _, idm := range idms.Spec.ImageDigestMirrors {
+		assert.Equal(t, "localhost:5000/ocp/release", string(idm.Mirrors[0]))
+	}
+
+	icsp, err := ImageContentSourcePolicy(name, result)
+	require.NoError(t, err)
+	assert.Equal(t, "ImageContentSourcePolicy", icsp.Kind)
+	assert.Equal(t, "operator.openshift.io/v1alpha1", icsp.APIVersion)
+	require.Len(t, icsp.Spec.RepositoryDigestMirrors, 2)
+	assert.Equal(t, []string{"localhost:5000/ocp/release"}, icsp.Spec.RepositoryDigestMirrors[0].Mirrors)
+
+	result.To = "oci:/tmp/mirror"
+	_, err = ImageDigestMirrorSet(name, result)
+	assert.Error(t, err)
+}
+
+func TestSanitizeTag(t *testing.T) {
+	assert.Equal(t, "4.20.0-okd-scos.17", sanitizeTag("4.20.0-okd-scos.17"))
+	assert.Equal(t, "4.21.0_build.1", sanitizeTag("4.21.0+build.1"))
+}
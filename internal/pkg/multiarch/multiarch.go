@@ -0,0 +1,160 @@
+package multiarch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/containers"
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/containers/image/v5/docker/reference"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+)
+
+// Opts holds the options for inspecting the architectures of a release.
+type Opts struct {
+	// AuthfilePath is the path to a registry auth file.
+	AuthfilePath string
+	// Concurrency is the maximum number of component images to inspect at once.
+	Concurrency int
+}
+
+// Component describes which architectures a single release component is
+// available for.
+type Component struct {
+	Name          string   `json:"name"`
+	Image         string   `json:"image"`
+	Architectures []string `json:"architectures"`
+	// Missing contains the architectures the release payload is available for
+	// which this component is not.
+	Missing []string `json:"missing,omitempty"`
+}
+
+// Result describes the architectures of a release payload and its components.
+type Result struct {
+	Release  string `json:"release"`
+	Pullspec string `json:"pullspec"`
+	containers.ImagePlatforms
+	Components []Component `json:"components"`
+	// ComponentsMissingArchitectures contains the name of each component which
+	// is not available for every architecture the release payload is.
+	ComponentsMissingArchitectures []string `json:"componentsMissingArchitectures"`
+}
+
+// Inspect resolves the manifest list for the given release payload along with
+// each of its component images and determines which architectures each one is
+// available for. Components which are not available for every architecture
+// the payload is are flagged.
+func Inspect(ctx context.Context, ri *releasecontroller.ReleaseInfo, opts Opts) (*Result, error) {
+	if ri.References == nil {
+		return nil, fmt.Errorf("release %q does not have any image references", ri.Metadata.Version)
+	}
+
+	pullspec, err := getPayloadPullspec(ri)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := containers.GetImagePlatforms(ctx, pullspec, opts.AuthfilePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not get platforms for release %q: %w", pullspec, err)
+	}
+
+	expected := sets.New[string](payload.Names()...)
+
+	g, ctx := errgroup.WithContext(ctx)
+	if opts.Concurrency > 0 {
+		g.SetLimit(opts.Concurrency)
+	}
+
+	mu := &sync.Mutex{}
+	components := []Component{}
+
+	for _, tag := range ri.References.Spec.Tags {
+		if tag.From == nil || tag.From.Name == "" {
+			continue
+		}
+
+		g.Go(func() error {
+			platforms, err := containers.GetImagePlatforms(ctx, tag.From.Name, opts.AuthfilePath)
+			if err != nil {
+				return fmt.Errorf("could not get platforms for component %s: %w", tag.Name, err)
+			}
+
+			found := sets.New[string](platforms.Names()...)
+
+			component := Component{
+				Name:          tag.Name,
+				Image:         tag.From.Name,
+				Architectures: sets.List(found),
+				Missing:       sets.List(expected.Difference(found)),
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			components = append(components, component)
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].Name < components[j].Name
+	})
+
+	out := &Result{
+		Release:                        ri.Metadata.Version,
+		Pullspec:                       pullspec,
+		ImagePlatforms:                 *payload,
+		Components:                     components,
+		ComponentsMissingArchitectures: []string{},
+	}
+
+	for _, component := range components {
+		if len(component.Missing) == 0 {
+			continue
+		}
+
+		klog.Warningf("Component %s is missing architecture(s): %v", component.Name, component.Missing)
+		out.ComponentsMissingArchitectures = append(out.ComponentsMissingArchitectures, component.Name)
+	}
+
+	return out, nil
+}
+
+// Determines the pullspec for the release payload. When the release info was
+// obtained from a manifest list, $ oc adm release info reports on a single
+// platform-specific image, so the list digest is used instead.
+func getPayloadPullspec(ri *releasecontroller.ReleaseInfo) (string, error) {
+	pullspec := ri.Image
+	if pullspec == "" {
+		pullspec = ri.ReleasePullspec
+	}
+
+	if pullspec == "" {
+		return "", fmt.Errorf("release %q does not have a pullspec", ri.Metadata.Version)
+	}
+
+	d := ri.ListDigest
+	if d == "" {
+		d = ri.Digest
+	}
+
+	if d == "" || containers.IsOCILayoutReference(pullspec) {
+		return pullspec, nil
+	}
+
+	named, err := reference.ParseNormalizedNamed(pullspec)
+	if err != nil {
+		return "", fmt.Errorf("could not parse release pullspec %q: %w", pullspec, err)
+	}
+
+	return fmt.Sprintf("%s@%s", reference.TrimNamed(named).String(), d), nil
+}
@@ -0,0 +1,216 @@
+package multiarch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/containers/image/v5/oci/layout"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	imgspecs "github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Writes an image index containing an image for each of the given
+// architectures (optionally with a variant, e.g., arm64/v8) into an OCI layout
+// and returns its reference.
+func newTestImageIndex(ctx context.Context, t *testing.T, dir, name string, arches ...string) string {
+	t.Helper()
+
+	ref, err := layout.NewReference(dir, name)
+	require.NoError(t, err)
+
+	dest, err := ref.NewImageDestination(ctx, nil)
+	require.NoError(t, err)
+
+	defer dest.Close()
+
+	putBlob := func(b []byte, mediaType string, isConfig bool) imgspecv1.Descriptor {
+		info, err := dest.PutBlob(ctx, bytes.NewReader(b), types.BlobInfo{Digest: digest.FromBytes(b), Size: int64(len(b))}, none.NoCache, isConfig)
+		require.NoError(t, err)
+		return imgspecv1.Descriptor{MediaType: mediaType, Digest: info.Digest, Size: info.Size}
+	}
+
+	index := imgspecv1.Index{
+		Versioned: imgspecs.Versioned{SchemaVersion: 2},
+		MediaType: imgspecv1.MediaTypeImageIndex,
+	}
+
+	for _, arch := range arches {
+		architecture, variant, _ := strings.Cut(arch, "/")
+		platform := imgspecv1.Platform{OS: "linux", Architecture: architecture, Variant: variant}
+
+		config, err := json.Marshal(imgspecv1.Image{Platform: platform})
+		require.NoError(t, err)
+
+		m := imgspecv1.Manifest{
+			Versioned: imgspecs.Versioned{SchemaVersion: 2},
+			MediaType: imgspecv1.MediaTypeImageManifest,
+			Config:    putBlob(config, imgspecv1.MediaTypeImageConfig, true),
+			Layers:    []imgspecv1.Descriptor{putBlob([]byte(name+"-"+architecture), imgspecv1.MediaTypeImageLayer, false)},
+		}
+
+		rawManifest, err := json.Marshal(m)
+		require.NoError(t, err)
+
+		d := digest.FromBytes(rawManifest)
+		require.NoError(t, dest.PutManifest(ctx, rawManifest, &d))
+
+		index.Manifests = append(index.Manifests, imgspecv1.Descriptor{
+			MediaType: imgspecv1.MediaTypeImageManifest,
+			Digest:    d,
+			Size:      int64(len(rawManifest)),
+			Platform:  &platform,
+		})
+	}
+
+	rawIndex, err := json.Marshal(index)
+	require.NoError(t, err)
+	require.NoError(t, dest.PutManifest(ctx, rawIndex, nil))
+	require.NoError(t, dest.Commit(ctx, nil))
+
+	return fmt.Sprintf("oci:%s:%s", dir, name)
+}
+
+func TestInspect(t *testing.T) {
+	ctx := context.Background()
+
+	allArches := []string{"amd64", "arm64", "ppc64le", "s390x"}
+
+	dir := filepath.Join(t.TempDir(), "layout")
+
+	payload := newTestImageIndex(ctx, t, dir, "release", allArches...)
+
+	components := map[string][]string{
+		"machine-config-operator": allArches,
+		"rhel-coreos":             allArches,
+		"baremetal-installer":     {"amd64", "arm64"},
+		"ovirt-csi-driver":        {"amd64"},
+		// arm64/v8 is the same as arm64.
+		"cli": {"amd64", "arm64/v8", "ppc64le", "s390x"},
+	}
+
+	ri := &releasecontroller.ReleaseInfo{
+		Image: payload,
+		Metadata: releasecontroller.Metadata{
+			Version: "4.21.4",
+		},
+		References: &imagev1.ImageStream{},
+	}
+
+	for name, arches := range components {
+		ri.References.Spec.Tags = append(ri.References.Spec.Tags, imagev1.TagReference{
+			Name: name,
+			From: &corev1.ObjectReference{Kind: "DockerImage", Name: newTestImageIndex(ctx, t, dir, name, arches...)},
+		})
+	}
+
+	result, err := Inspect(ctx, ri, Opts{Concurrency: 4})
+	require.NoError(t, err)
+
+	assert.Equal(t, "4.21.4", result.Release)
+	assert.Equal(t, payload, result.Pullspec)
+	assert.True(t, result.IsManifestList)
+	assert.Equal(t, allArches, result.Names())
+	assert.Equal(t, []string{"baremetal-installer", "ovirt-csi-driver"}, result.ComponentsMissingArchitectures)
+
+	expected := []Component{
+		{
+			Name:          "baremetal-installer",
+			Architectures: []string{"amd64", "arm64"},
+			Missing:       []string{"ppc64le", "s390x"},
+		},
+		{
+			Name:          "cli",
+			Architectures: allArches,
+		},
+		{
+			Name:          "machine-config-operator",
+			Architectures: allArches,
+		},
+		{
+			Name:          "ovirt-csi-driver",
+			Architectures: []string{"amd64"},
+			Missing:       []string{"arm64", "ppc64le", "s390x"},
+		},
+		{
+			Name:          "rhel-coreos",
+			Architectures: allArches,
+		},
+	}
+
+	require.Len(t, result.Components, len(expected))
+
+	for i, component := range expected {
+		assert.Equal(t, component.Name, result.Components[i].Name)
+		assert.Equal(t, component.Architectures, result.Components[i].Architectures)
+		assert.ElementsMatch(t, component.Missing, result.Components[i].Missing)
+		assert.NotEmpty(t, result.Components[i].Image)
+	}
+}
+
+func TestGetPayloadPullspec(t *testing.T) {
+	listDigest := "sha256:aa6cd007e204673ceafa266fe1cf359b386cbb1e34c785ae2dd1856e8f61b71c"
+	instanceDigest := "sha256:bec41abb841b042589766901962cf99bb7894bd673d4f71602523aa0c255a4f4"
+
+	testCases := []struct {
+		name      string
+		ri        *releasecontroller.ReleaseInfo
+		expected  string
+		expectErr bool
+	}{
+		{
+			name: "Prefers list digest",
+			ri: &releasecontroller.ReleaseInfo{
+				Image:      "quay.io/openshift-release-dev/ocp-release:4.21.4-multi",
+				Digest:     instanceDigest,
+				ListDigest: listDigest,
+			},
+			expected: "quay.io/openshift-release-dev/ocp-release@" + listDigest,
+		},
+		{
+			name: "Falls back to digest",
+			ri: &releasecontroller.ReleaseInfo{
+				Image:  "quay.io/openshift-release-dev/ocp-release:4.21.4-x86_64",
+				Digest: instanceDigest,
+			},
+			expected: "quay.io/openshift-release-dev/ocp-release@" + instanceDigest,
+		},
+		{
+			name: "Uses release pullspec",
+			ri: &releasecontroller.ReleaseInfo{
+				ReleasePullspec: "quay.io/openshift-release-dev/ocp-release:4.21.4-multi",
+			},
+			expected: "quay.io/openshift-release-dev/ocp-release:4.21.4-multi",
+		},
+		{
+			name:      "No pullspec",
+			ri:        &releasecontroller.ReleaseInfo{},
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			pullspec, err := getPayloadPullspec(testCase.ri)
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, pullspec)
+		})
+	}
+}
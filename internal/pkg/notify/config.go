@@ -0,0 +1,226 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Config describes which release streams to watch, where to send events, and
+// which events to send where.
+type Config struct {
+	// Streams are the release streams to watch.
+	Streams []string `json:"streams"`
+	// Interval is how often to poll the release controller. Defaults to 5m.
+	Interval metav1.Duration `json:"interval,omitempty"`
+	// StaleAfter is how long a release stream may go without a release tag
+	// being accepted before a stale event is sent. Stale events are not sent
+	// if unset.
+	StaleAfter metav1.Duration `json:"staleAfter,omitempty"`
+	Sinks      []SinkConfig    `json:"sinks"`
+	Rules      []Rule          `json:"rules"`
+}
+
+// SinkConfig configures a single named sink. Exactly one of the sink kinds
+// must be set.
+type SinkConfig struct {
+	Name    string             `json:"name"`
+	Webhook *WebhookSinkConfig `json:"webhook,omitempty"`
+	Slack   *SlackSinkConfig   `json:"slack,omitempty"`
+	Exec    *ExecSinkConfig    `json:"exec,omitempty"`
+}
+
+type WebhookSinkConfig struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is a template for the request body. Defaults to the event as JSON.
+	Body string `json:"body,omitempty"`
+}
+
+type SlackSinkConfig struct {
+	URL string `json:"url"`
+	// Text is a template for the message text.
+	Text string `json:"text,omitempty"`
+}
+
+type ExecSinkConfig struct {
+	Command []string `json:"command"`
+}
+
+// Rule sends matching events to the given sinks.
+type Rule struct {
+	// Streams limits the rule to the given release streams. The rule applies
+	// to all watched release streams if empty.
+	Streams []string `json:"streams,omitempty"`
+	// Events limits the rule to the given event types. The rule applies to all
+	// event types if empty.
+	Events []EventType `json:"events,omitempty"`
+	// Sinks are the names of the sinks to send matching events to.
+	Sinks []string `json:"sinks"`
+}
+
+// Matches returns whether the rule applies to the given event.
+func (r Rule) Matches(e Event) bool {
+	if len(r.Streams) != 0 && !slices.Contains(r.Streams, e.Stream) {
+		return false
+	}
+
+	if len(r.Events) != 0 && !slices.Contains(r.Events, e.Type) {
+		return false
+	}
+
+	return true
+}
+
+// LoadConfig reads and validates the YAML config at the given path.
+func LoadConfig(path string) (*Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &Config{}
+	if err := yaml.Unmarshal(raw, out); err != nil {
+		return nil, fmt.Errorf("could not parse config %s: %w", path, err)
+	}
+
+	if err := out.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	if out.Interval.Duration == 0 {
+		out.Interval.Duration = 5 * time.Minute
+	}
+
+	return out, nil
+}
+
+// Validate ensures that the config is complete and that each rule refers to
+// known sinks and event types.
+func (c *Config) Validate() error {
+	errs := []error{}
+
+	if len(c.Streams) == 0 {
+		errs = append(errs, fmt.Errorf("no streams to watch"))
+	}
+
+	sinkNames := sets.New[string]()
+
+	for i, sink := range c.Sinks {
+		if sink.Name == "" {
+			errs = append(errs, fmt.Errorf("sink %d does not have a name", i))
+		}
+
+		if sinkNames.Has(sink.Name) {
+			errs = append(errs, fmt.Errorf("duplicate sink %q", sink.Name))
+		}
+
+		sinkNames.Insert(sink.Name)
+
+		kinds := 0
+		for _, isSet := range []bool{sink.Webhook != nil, sink.Slack != nil, sink.Exec != nil} {
+			if isSet {
+				kinds++
+			}
+		}
+
+		if kinds != 1 {
+			errs = append(errs, fmt.Errorf("sink %q must have exactly one of webhook, slack, or exec", sink.Name))
+		}
+	}
+
+	knownEvents := sets.New[EventType](EventTypes()...)
+
+	for i, rule := range c.Rules {
+		if len(rule.Sinks) == 0 {
+			errs = append(errs, fmt.Errorf("rule %d does not have any sinks", i))
+		}
+
+		for _, sink := range rule.Sinks {
+			if !sinkNames.Has(sink) {
+				errs = append(errs, fmt.Errorf("rule %d refers to unknown sink %q", i, sink))
+			}
+		}
+
+		for _, event := range rule.Events {
+			if !knownEvents.Has(event) {
+				errs = append(errs, fmt.Errorf("rule %d refers to unknown event %q, must be one of: %v", i, event, EventTypes()))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Notifier sends events to the sinks of each matching rule.
+type Notifier struct {
+	rules []Rule
+	sinks map[string]Sink
+}
+
+// NewNotifier creates a Notifier from the given config. The given HTTP client,
+// if any, is used by the webhook and Slack sinks.
+func NewNotifier(cfg *Config, client *http.Client) (*Notifier, error) {
+	sinks := map[string]Sink{}
+
+	for _, sinkCfg := range cfg.Sinks {
+		sink, err := newSink(sinkCfg, client)
+		if err != nil {
+			return nil, fmt.Errorf("sink %q: %w", sinkCfg.Name, err)
+		}
+
+		sinks[sinkCfg.Name] = sink
+	}
+
+	return &Notifier{rules: cfg.Rules, sinks: sinks}, nil
+}
+
+// Notify sends the event to the sinks of each matching rule. Each sink
+// receives a given event at most once, even if it is referred to by multiple
+// matching rules. A failure to send to one sink does not prevent sending to
+// the others.
+func (n *Notifier) Notify(ctx context.Context, e Event) error {
+	sent := sets.New[string]()
+	errs := []error{}
+
+	for _, rule := range n.rules {
+		if !rule.Matches(e) {
+			continue
+		}
+
+		for _, name := range rule.Sinks {
+			if sent.Has(name) {
+				continue
+			}
+
+			sent.Insert(name)
+
+			if err := n.sinks[name].Send(ctx, e); err != nil {
+				errs = append(errs, fmt.Errorf("could not send %s event for %s to sink %q: %w", e.Type, e.Stream, name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func newSink(cfg SinkConfig, client *http.Client) (Sink, error) {
+	switch {
+	case cfg.Webhook != nil:
+		return NewWebhookSink(cfg.Webhook.URL, cfg.Webhook.Headers, cfg.Webhook.Body, client)
+	case cfg.Slack != nil:
+		return NewSlackSink(cfg.Slack.URL, cfg.Slack.Text, client)
+	case cfg.Exec != nil:
+		return NewExecSink(cfg.Exec.Command)
+	}
+
+	return nil, fmt.Errorf("no sink kind configured")
+}
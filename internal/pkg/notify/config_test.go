@@ -0,0 +1,205 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	testCases := []struct {
+		name      string
+		config    string
+		expectErr bool
+	}{
+		{
+			name: "Valid",
+			config: `
+streams:
+- 4.18.0-0.nightly
+interval: 1m
+staleAfter: 12h
+sinks:
+- name: chat
+  slack:
+    url: https://hooks.slack.com/services/abc
+- name: hook
+  webhook:
+    url: https://example.com/hook
+    headers:
+      Authorization: Bearer token
+    body: '{"tag": {{ json .Tag }}}'
+- name: script
+  exec:
+    command: ["/usr/local/bin/handle-event", "--verbose"]
+rules:
+- events: [rejected, stale]
+  sinks: [chat, hook]
+- streams: [4.18.0-0.nightly]
+  sinks: [script]
+`,
+		},
+		{
+			name:      "No streams",
+			config:    `sinks: []`,
+			expectErr: true,
+		},
+		{
+			name: "Unknown sink",
+			config: `
+streams: [4.18.0-0.nightly]
+rules:
+- sinks: [missing]
+`,
+			expectErr: true,
+		},
+		{
+			name: "Unknown event",
+			config: `
+streams: [4.18.0-0.nightly]
+sinks:
+- name: script
+  exec:
+    command: [true]
+rules:
+- events: [exploded]
+  sinks: [script]
+`,
+			expectErr: true,
+		},
+		{
+			name: "Multiple sink kinds",
+			config: `
+streams: [4.18.0-0.nightly]
+sinks:
+- name: both
+  exec:
+    command: [true]
+  slack:
+    url: https://hooks.slack.com/services/abc
+`,
+			expectErr: true,
+		},
+		{
+			name: "Duplicate sink names",
+			config: `
+streams: [4.18.0-0.nightly]
+sinks:
+- name: script
+  exec:
+    command: [true]
+- name: script
+  exec:
+    command: [false]
+`,
+			expectErr: true,
+		},
+		{
+			name: "Invalid duration",
+			config: `
+streams: [4.18.0-0.nightly]
+interval: soon
+`,
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(testCase.config), 0o644))
+
+			cfg, err := LoadConfig(path)
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{"4.18.0-0.nightly"}, cfg.Streams)
+			assert.Equal(t, time.Minute, cfg.Interval.Duration)
+			assert.Equal(t, 12*time.Hour, cfg.StaleAfter.Duration)
+			assert.Len(t, cfg.Sinks, 3)
+			assert.Equal(t, []EventType{EventRejected, EventStale}, cfg.Rules[0].Events)
+
+			_, err = NewNotifier(cfg, nil)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	event := Event{Type: EventAccepted, Stream: "4.18.0-0.nightly"}
+
+	assert.True(t, Rule{}.Matches(event))
+	assert.True(t, Rule{Streams: []string{"4.18.0-0.nightly"}, Events: []EventType{EventAccepted}}.Matches(event))
+	assert.False(t, Rule{Streams: []string{"4.19.0-0.nightly"}}.Matches(event))
+	assert.False(t, Rule{Events: []EventType{EventRejected, EventStale}}.Matches(event))
+}
+
+type fakeSink struct {
+	events []Event
+	err    error
+}
+
+func (f *fakeSink) Send(_ context.Context, e Event) error {
+	f.events = append(f.events, e)
+	return f.err
+}
+
+func TestNotifier(t *testing.T) {
+	chat := &fakeSink{}
+	broken := &fakeSink{err: fmt.Errorf("broken")}
+	other := &fakeSink{}
+
+	n := &Notifier{
+		rules: []Rule{
+			{Events: []EventType{EventRejected}, Sinks: []string{"broken", "chat"}},
+			// Sinks referred to by multiple matching rules only receive the
+			// event once.
+			{Streams: []string{"4.18.0-0.nightly"}, Sinks: []string{"chat"}},
+			{Streams: []string{"4.19.0-0.nightly"}, Sinks: []string{"other"}},
+		},
+		sinks: map[string]Sink{
+			"chat":   chat,
+			"broken": broken,
+			"other":  other,
+		},
+	}
+
+	rejected := Event{Type: EventRejected, Stream: "4.18.0-0.nightly"}
+	accepted := Event{Type: EventAccepted, Stream: "4.18.0-0.nightly"}
+
+	// A failing sink does not prevent the other sinks from receiving the
+	// event.
+	assert.ErrorContains(t, n.Notify(context.Background(), rejected), "broken")
+	assert.NoError(t, n.Notify(context.Background(), accepted))
+
+	assert.Equal(t, []Event{rejected, accepted}, chat.events)
+	assert.Equal(t, []Event{rejected}, broken.events)
+	assert.Empty(t, other.events)
+}
+
+func TestNewNotifierUsesClient(t *testing.T) {
+	srv, received := newRecordingServer(t, http.StatusOK)
+
+	cfg := &Config{
+		Streams: []string{"4.18.0-0.nightly"},
+		Sinks:   []SinkConfig{{Name: "hook", Webhook: &WebhookSinkConfig{URL: srv.URL}}},
+		Rules:   []Rule{{Sinks: []string{"hook"}}},
+	}
+
+	n, err := NewNotifier(cfg, srv.Client())
+	require.NoError(t, err)
+
+	require.NoError(t, n.Notify(context.Background(), newTestEvent()))
+	assert.Len(t, received, 1)
+}
@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EventType is the kind of release stream event.
+type EventType string
+
+const (
+	// A new release tag was accepted.
+	EventAccepted EventType = "accepted"
+	// A new release tag was rejected.
+	EventRejected EventType = "rejected"
+	// A release stream has not had a release tag accepted within the
+	// configured maximum age.
+	EventStale EventType = "stale"
+)
+
+// EventTypes returns all of the known event types.
+func EventTypes() []EventType {
+	return []EventType{EventAccepted, EventRejected, EventStale}
+}
+
+// Event is something which happened to a release stream.
+type Event struct {
+	Type       EventType `json:"type"`
+	Controller string    `json:"controller"`
+	Stream     string    `json:"stream"`
+	// Tag is the release tag the event is about. For stale events, this is
+	// the last accepted release tag, if there is one.
+	Tag      string `json:"tag,omitempty"`
+	Phase    string `json:"phase,omitempty"`
+	Pullspec string `json:"pullspec,omitempty"`
+	// URL is the release controller page for the release tag.
+	URL string `json:"url,omitempty"`
+	// FailedBlockingJobs are the names of the blocking jobs which failed for
+	// a rejected release tag.
+	FailedBlockingJobs []string `json:"failedBlockingJobs,omitempty"`
+	// SinceLastAccepted is how long ago the last release tag was accepted for
+	// stale events.
+	SinceLastAccepted string    `json:"sinceLastAccepted,omitempty"`
+	Time              time.Time `json:"time"`
+}
+
+// Summary returns a single line, human-readable description of the event.
+func (e Event) Summary() string {
+	switch e.Type {
+	case EventAccepted:
+		return fmt.Sprintf("%s was accepted", e.Tag)
+	case EventRejected:
+		if len(e.FailedBlockingJobs) == 0 {
+			return fmt.Sprintf("%s was rejected", e.Tag)
+		}
+
+		return fmt.Sprintf("%s was rejected, failed blocking jobs: %s", e.Tag, strings.Join(e.FailedBlockingJobs, ", "))
+	case EventStale:
+		if e.Tag == "" {
+			return fmt.Sprintf("%s has never had a release accepted", e.Stream)
+		}
+
+		return fmt.Sprintf("%s has not had a release accepted in %s, last accepted: %s", e.Stream, e.SinceLastAccepted, e.Tag)
+	}
+
+	return fmt.Sprintf("%s: %s %s", e.Stream, e.Type, e.Tag)
+}
@@ -0,0 +1,196 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Sink sends events somewhere.
+type Sink interface {
+	Send(context.Context, Event) error
+}
+
+var templateFuncs = template.FuncMap{
+	// Encodes the given value as JSON so that it can be safely embedded in a
+	// JSON body.
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"join": strings.Join,
+}
+
+// WebhookSink POSTs each event to an HTTP endpoint. The body is the event
+// encoded as JSON unless a template is given.
+type WebhookSink struct {
+	url     string
+	headers map[string]string
+	body    *template.Template
+	client  *http.Client
+}
+
+// NewWebhookSink creates a WebhookSink. The body template, if given, is a
+// text/template executed against the Event. A json function is available to
+// encode values as JSON.
+func NewWebhookSink(url string, headers map[string]string, bodyTemplate string, client *http.Client) (*WebhookSink, error) {
+	out := &WebhookSink{
+		url:     url,
+		headers: headers,
+		client:  getClient(client),
+	}
+
+	if bodyTemplate != "" {
+		tmpl, err := template.New("body").Funcs(templateFuncs).Parse(bodyTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook body template: %w", err)
+		}
+
+		out.body = tmpl
+	}
+
+	return out, nil
+}
+
+func (w *WebhookSink) Send(ctx context.Context, e Event) error {
+	var body []byte
+
+	if w.body == nil {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+
+		body = b
+	} else {
+		buf := bytes.NewBuffer(nil)
+		if err := w.body.Execute(buf, e); err != nil {
+			return fmt.Errorf("could not render webhook body: %w", err)
+		}
+
+		body = buf.Bytes()
+	}
+
+	return postJSON(ctx, w.client, w.url, w.headers, body)
+}
+
+// SlackSink posts each event to a Slack-compatible incoming webhook.
+type SlackSink struct {
+	url    string
+	text   *template.Template
+	client *http.Client
+}
+
+// NewSlackSink creates a SlackSink. The text template, if given, is a
+// text/template executed against the Event. Otherwise, the event summary and
+// a link to the release tag are used.
+func NewSlackSink(url, textTemplate string, client *http.Client) (*SlackSink, error) {
+	if textTemplate == "" {
+		textTemplate = `{{ if .URL }}<{{ .URL }}|{{ .Stream }}>{{ else }}{{ .Stream }}{{ end }}: {{ .Summary }}`
+	}
+
+	tmpl, err := template.New("text").Funcs(templateFuncs).Parse(textTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid slack text template: %w", err)
+	}
+
+	return &SlackSink{
+		url:    url,
+		text:   tmpl,
+		client: getClient(client),
+	}, nil
+}
+
+func (s *SlackSink) Send(ctx context.Context, e Event) error {
+	buf := bytes.NewBuffer(nil)
+	if err := s.text.Execute(buf, e); err != nil {
+		return fmt.Errorf("could not render slack text: %w", err)
+	}
+
+	body, err := json.Marshal(map[string]string{"text": buf.String()})
+	if err != nil {
+		return err
+	}
+
+	return postJSON(ctx, s.client, s.url, nil, body)
+}
+
+// ExecSink runs a command for each event. The event is written to the
+// command's stdin as JSON and its type, stream, and tag are also provided as
+// the RCCTL_EVENT_TYPE, RCCTL_EVENT_STREAM, and RCCTL_EVENT_TAG environment
+// variables.
+type ExecSink struct {
+	command []string
+}
+
+// NewExecSink creates an ExecSink which runs the given command and arguments.
+func NewExecSink(command []string) (*ExecSink, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("exec sink requires a command")
+	}
+
+	return &ExecSink{command: command}, nil
+}
+
+func (e *ExecSink) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, e.command[0], e.command[1:]...)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(),
+		"RCCTL_EVENT_TYPE="+string(event.Type),
+		"RCCTL_EVENT_STREAM="+event.Stream,
+		"RCCTL_EVENT_TAG="+event.Tag,
+	)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("command %v failed: %w: %s", e.command, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("got HTTP %d from %s: %s", resp.StatusCode, url, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
+
+func getClient(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+
+	return &http.Client{Timeout: 30 * time.Second}
+}
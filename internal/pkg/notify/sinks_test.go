@@ -0,0 +1,168 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// Starts a local HTTP server which records each request it receives and
+// responds with the given status code.
+func newRecordingServer(t *testing.T, statusCode int) (*httptest.Server, chan receivedRequest) {
+	t.Helper()
+
+	received := make(chan receivedRequest, 10)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		received <- receivedRequest{header: req.Header.Clone(), body: body}
+
+		w.WriteHeader(statusCode)
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return srv, received
+}
+
+func newTestEvent() Event {
+	return Event{
+		Type:               EventRejected,
+		Controller:         "amd64.ocp.releases.ci.openshift.org",
+		Stream:             "4.18.0-0.nightly",
+		Tag:                "4.18.0-0.nightly-2025-01-01-000000",
+		Phase:              "Rejected",
+		URL:                "https://amd64.ocp.releases.ci.openshift.org/releasestream/4.18.0-0.nightly/release/4.18.0-0.nightly-2025-01-01-000000",
+		FailedBlockingJobs: []string{"install", "upgrade"},
+		Time:               time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	t.Run("Default body", func(t *testing.T) {
+		srv, received := newRecordingServer(t, http.StatusOK)
+
+		sink, err := NewWebhookSink(srv.URL, nil, "", srv.Client())
+		require.NoError(t, err)
+
+		require.NoError(t, sink.Send(context.Background(), newTestEvent()))
+
+		req := <-received
+		assert.Equal(t, "application/json", req.header.Get("Content-Type"))
+
+		event := Event{}
+		require.NoError(t, json.Unmarshal(req.body, &event))
+		assert.Equal(t, newTestEvent(), event)
+	})
+
+	t.Run("Templated body and headers", func(t *testing.T) {
+		srv, received := newRecordingServer(t, http.StatusNoContent)
+
+		body := `{"summary": {{ json .Summary }}, "jobs": {{ json .FailedBlockingJobs }}, "joined": "{{ join .FailedBlockingJobs "," }}"}`
+
+		sink, err := NewWebhookSink(srv.URL, map[string]string{"Authorization": "Bearer token"}, body, srv.Client())
+		require.NoError(t, err)
+
+		require.NoError(t, sink.Send(context.Background(), newTestEvent()))
+
+		req := <-received
+		assert.Equal(t, "Bearer token", req.header.Get("Authorization"))
+		assert.JSONEq(t, `{
+			"summary": "4.18.0-0.nightly-2025-01-01-000000 was rejected, failed blocking jobs: install, upgrade",
+			"jobs": ["install", "upgrade"],
+			"joined": "install,upgrade"
+		}`, string(req.body))
+	})
+
+	t.Run("Invalid template", func(t *testing.T) {
+		_, err := NewWebhookSink("http://localhost", nil, "{{ .Unclosed", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("Error status", func(t *testing.T) {
+		srv, _ := newRecordingServer(t, http.StatusInternalServerError)
+
+		sink, err := NewWebhookSink(srv.URL, nil, "", srv.Client())
+		require.NoError(t, err)
+
+		assert.ErrorContains(t, sink.Send(context.Background(), newTestEvent()), "HTTP 500")
+	})
+}
+
+func TestSlackSink(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "Default text",
+			expected: "<https://amd64.ocp.releases.ci.openshift.org/releasestream/4.18.0-0.nightly/release/4.18.0-0.nightly-2025-01-01-000000|4.18.0-0.nightly>: 4.18.0-0.nightly-2025-01-01-000000 was rejected, failed blocking jobs: install, upgrade",
+		},
+		{
+			name:     "Templated text",
+			text:     ":red_circle: {{ .Tag }} {{ .Type }}",
+			expected: ":red_circle: 4.18.0-0.nightly-2025-01-01-000000 rejected",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			srv, received := newRecordingServer(t, http.StatusOK)
+
+			sink, err := NewSlackSink(srv.URL, testCase.text, srv.Client())
+			require.NoError(t, err)
+
+			require.NoError(t, sink.Send(context.Background(), newTestEvent()))
+
+			req := <-received
+
+			payload := map[string]string{}
+			require.NoError(t, json.Unmarshal(req.body, &payload))
+			assert.Equal(t, map[string]string{"text": testCase.expected}, payload)
+		})
+	}
+}
+
+func TestExecSink(t *testing.T) {
+	dir := t.TempDir()
+
+	sink, err := NewExecSink([]string{"sh", "-c", `cat > "$0/event.json" && echo "$RCCTL_EVENT_TYPE $RCCTL_EVENT_STREAM $RCCTL_EVENT_TAG" > "$0/env"`, dir})
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Send(context.Background(), newTestEvent()))
+
+	raw, err := os.ReadFile(filepath.Join(dir, "event.json"))
+	require.NoError(t, err)
+
+	event := Event{}
+	require.NoError(t, json.Unmarshal(raw, &event))
+	assert.Equal(t, newTestEvent(), event)
+
+	env, err := os.ReadFile(filepath.Join(dir, "env"))
+	require.NoError(t, err)
+	assert.Equal(t, "rejected 4.18.0-0.nightly 4.18.0-0.nightly-2025-01-01-000000\n", string(env))
+
+	failing, err := NewExecSink([]string{"sh", "-c", "echo oops && exit 1"})
+	require.NoError(t, err)
+	assert.ErrorContains(t, failing.Send(context.Background(), newTestEvent()), "oops")
+
+	_, err = NewExecSink(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,224 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"k8s.io/klog"
+)
+
+// Watcher polls release streams and produces events by comparing what it sees
+// with what it saw on the previous poll.
+type Watcher struct {
+	rc         *releasecontroller.ReleaseController
+	streams    []string
+	staleAfter time.Duration
+	// Release stream -> release tag -> phase as of the last poll. A release
+	// stream is absent until it has been polled successfully once.
+	phases map[string]map[string]string
+	// Release streams a stale event has already been produced for. This is
+	// cleared once a release tag is accepted.
+	stale map[string]bool
+	// Allows the current time to be overridden for testing.
+	now func() time.Time
+}
+
+// NewWatcher creates a Watcher for the given release streams. Stale events are
+// not produced if staleAfter is zero.
+func NewWatcher(rc *releasecontroller.ReleaseController, streams []string, staleAfter time.Duration) *Watcher {
+	return &Watcher{
+		rc:         rc,
+		streams:    streams,
+		staleAfter: staleAfter,
+		phases:     map[string]map[string]string{},
+		stale:      map[string]bool{},
+		now:        time.Now,
+	}
+}
+
+// Poll gets the current release tags in each release stream and returns the
+// events which happened since the last poll. The first poll of each release
+// stream only records its current state so that existing release tags do not
+// produce events, though a stale event may still be produced. A release
+// stream which cannot be polled does not stop the others from being polled;
+// the events from the others are returned along with the errors.
+func (w *Watcher) Poll(ctx context.Context) ([]Event, error) {
+	out := []Event{}
+	errs := []error{}
+
+	for _, stream := range w.streams {
+		events, err := w.pollStream(ctx, stream)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		out = append(out, events...)
+	}
+
+	return out, errors.Join(errs...)
+}
+
+// Run polls the release streams at the given interval until the context is
+// done, sending each event to the notifier. Errors are logged rather than
+// returned so that a temporary problem does not stop the watch.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration, n *Notifier) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		events, err := w.Poll(ctx)
+		if err != nil {
+			klog.Warningf("Could not poll release streams: %s", err)
+		}
+
+		for _, event := range events {
+			klog.Infof("%s: %s", event.Stream, event.Summary())
+
+			if err := n.Notify(ctx, event); err != nil {
+				klog.Warning(err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Watcher) pollStream(ctx context.Context, stream string) ([]Event, error) {
+	rs := w.rc.ReleaseStream(stream)
+
+	tags, err := rs.Tags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get tags for release stream %q: %w", stream, err)
+	}
+
+	now := w.now()
+	out := []Event{}
+
+	previous, seen := w.phases[stream]
+	current := map[string]string{}
+
+	// Release tags are ordered newest first, but events should be produced in
+	// the order they happened.
+	for i := len(tags.Tags) - 1; i >= 0; i-- {
+		tag := tags.Tags[i]
+		current[tag.Name] = tag.Phase
+
+		if !seen || previous[tag.Name] == tag.Phase {
+			continue
+		}
+
+		event := Event{
+			Controller: w.rc.WebController().Host(),
+			Stream:     stream,
+			Tag:        tag.Name,
+			Phase:      tag.Phase,
+			Pullspec:   tag.Pullspec,
+			URL:        w.rc.ReleaseURL(stream, tag.Name),
+			Time:       now,
+		}
+
+		switch releasecontroller.Phase(tag.Phase) {
+		case releasecontroller.PhaseAccepted:
+			event.Type = EventAccepted
+			delete(w.stale, stream)
+		case releasecontroller.PhaseRejected:
+			event.Type = EventRejected
+			event.FailedBlockingJobs = w.getFailedBlockingJobs(ctx, rs, tag.Name)
+		default:
+			continue
+		}
+
+		out = append(out, event)
+	}
+
+	w.phases[stream] = current
+
+	event, err := w.checkStale(ctx, stream, tags.Tags, now)
+	if err != nil {
+		return out, err
+	}
+
+	if event != nil {
+		out = append(out, *event)
+	}
+
+	return out, nil
+}
+
+// Produces a stale event the first time a release stream is found to not have
+// had a release tag accepted within the stale threshold. If the age of the last
+// accepted release tag cannot be determined, an error is returned and the
+// release stream is checked again on the next poll.
+func (w *Watcher) checkStale(ctx context.Context, stream string, tags []releasecontroller.Release, now time.Time) (*Event, error) {
+	if w.staleAfter == 0 || w.stale[stream] {
+		return nil, nil
+	}
+
+	event := &Event{
+		Type:       EventStale,
+		Controller: w.rc.WebController().Host(),
+		Stream:     stream,
+		Time:       now,
+	}
+
+	for _, tag := range tags {
+		if tag.Phase != string(releasecontroller.PhaseAccepted) {
+			continue
+		}
+
+		ts, err := w.rc.GetTagTime(ctx, tag.Name)
+		if err != nil {
+			return nil, fmt.Errorf("could not determine whether release stream %q is stale: %w", stream, err)
+		}
+
+		since := now.Sub(ts)
+		if since <= w.staleAfter {
+			return nil, nil
+		}
+
+		event.Tag = tag.Name
+		event.Phase = tag.Phase
+		event.Pullspec = tag.Pullspec
+		event.URL = w.rc.ReleaseURL(stream, tag.Name)
+		event.SinceLastAccepted = since.Round(time.Minute).String()
+
+		break
+	}
+
+	w.stale[stream] = true
+
+	return event, nil
+}
+
+// The failed blocking jobs are best-effort, so failing to get them does not
+// prevent the rejected event from being produced.
+func (w *Watcher) getFailedBlockingJobs(ctx context.Context, rs *releasecontroller.ReleaseStream, tag string) []string {
+	info, err := rs.Tag(ctx, tag)
+	if err != nil {
+		klog.Warningf("Could not get verification results for release tag %q: %s", tag, err)
+		return nil
+	}
+
+	if info.Results == nil {
+		return nil
+	}
+
+	out := []string{}
+	for name, status := range info.Results.BlockingJobs {
+		if status != nil && status.State == "Failed" {
+			out = append(out, name)
+		}
+	}
+
+	sort.Strings(out)
+
+	return out
+}
@@ -0,0 +1,195 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A release controller whose release tags can be changed between polls.
+type fakeReleaseController struct {
+	mu   sync.Mutex
+	tags []releasecontroller.Release
+	// Release tag -> release info, for release tags without a timestamp.
+	releaseInfos map[string]*releasecontroller.ReleaseInfo
+}
+
+func (f *fakeReleaseController) setTags(tags ...releasecontroller.Release) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.tags = tags
+}
+
+func (f *fakeReleaseController) start(t *testing.T) *releasecontroller.ReleaseController {
+	t.Helper()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		var out interface{}
+
+		switch {
+		case strings.Contains(req.URL.Path, "/releasestream/unknown/"):
+			http.NotFound(w, req)
+			return
+		case strings.HasPrefix(req.URL.Path, "/releasetag/"):
+			ri, ok := f.releaseInfos[strings.Split(req.URL.Path, "/")[2]]
+			if !ok {
+				http.NotFound(w, req)
+				return
+			}
+
+			out = ri
+		case strings.HasSuffix(req.URL.Path, "/tags"):
+			out = &releasecontroller.ReleaseTags{Tags: f.tags}
+		case strings.Contains(req.URL.Path, "/release/"):
+			out = &releasecontroller.APIReleaseInfo{
+				Results: &releasecontroller.VerificationJobsSummary{
+					BlockingJobs: releasecontroller.VerificationStatusMap{
+						"upgrade": {State: "Failed"},
+						"install": {State: "Succeeded"},
+						"e2e":     {State: "Failed"},
+					},
+				},
+			}
+		}
+
+		//nolint:errcheck // This is test code.
+		json.NewEncoder(w).Encode(out)
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return releasecontroller.New(srv.Listener.Addr().String(), &releasecontroller.ReleaseControllerConfig{Client: srv.Client()})
+}
+
+func TestWatcher(t *testing.T) {
+	f := &fakeReleaseController{}
+	rc := f.start(t)
+
+	now := time.Date(2025, time.January, 3, 0, 0, 0, 0, time.UTC)
+
+	w := NewWatcher(rc, []string{"4.18.0-0.nightly"}, 50*time.Hour)
+	w.now = func() time.Time { return now }
+
+	summaries := func(events []Event) []string {
+		out := []string{}
+		for _, event := range events {
+			out = append(out, string(event.Type)+": "+event.Summary())
+		}
+
+		return out
+	}
+
+	poll := func() []string {
+		events, err := w.Poll(context.Background())
+		require.NoError(t, err)
+		return summaries(events)
+	}
+
+	// Existing release tags do not produce events on the first poll.
+	f.setTags(
+		releasecontroller.Release{Name: "4.18.0-0.nightly-2025-01-02-000000", Phase: "Ready"},
+		releasecontroller.Release{Name: "4.18.0-0.nightly-2025-01-01-000000", Phase: "Accepted"},
+	)
+
+	assert.Empty(t, poll())
+
+	// Newly finished release tags produce events in the order they happened.
+	f.setTags(
+		releasecontroller.Release{Name: "4.18.0-0.nightly-2025-01-02-120000", Phase: "Accepted"},
+		releasecontroller.Release{Name: "4.18.0-0.nightly-2025-01-02-000000", Phase: "Rejected"},
+		releasecontroller.Release{Name: "4.18.0-0.nightly-2025-01-01-000000", Phase: "Accepted"},
+	)
+
+	assert.Equal(t, []string{
+		"rejected: 4.18.0-0.nightly-2025-01-02-000000 was rejected, failed blocking jobs: e2e, upgrade",
+		"accepted: 4.18.0-0.nightly-2025-01-02-120000 was accepted",
+	}, poll())
+
+	// Nothing has changed.
+	assert.Empty(t, poll())
+
+	// The release stream goes stale, which only produces an event once.
+	now = now.Add(48 * time.Hour)
+
+	assert.Equal(t, []string{
+		"stale: 4.18.0-0.nightly has not had a release accepted in 60h0m0s, last accepted: 4.18.0-0.nightly-2025-01-02-120000",
+	}, poll())
+
+	assert.Empty(t, poll())
+
+	// Once a new release tag is accepted, the release stream may go stale
+	// again.
+	f.setTags(
+		releasecontroller.Release{Name: "4.18.0-0.nightly-2025-01-05-000000", Phase: "Accepted"},
+		releasecontroller.Release{Name: "4.18.0-0.nightly-2025-01-02-120000", Phase: "Accepted"},
+	)
+
+	assert.Equal(t, []string{"accepted: 4.18.0-0.nightly-2025-01-05-000000 was accepted"}, poll())
+
+	now = now.Add(72 * time.Hour)
+
+	assert.Equal(t, []string{
+		"stale: 4.18.0-0.nightly has not had a release accepted in 72h0m0s, last accepted: 4.18.0-0.nightly-2025-01-05-000000",
+	}, poll())
+}
+
+func TestWatcherNeverAccepted(t *testing.T) {
+	f := &fakeReleaseController{}
+	f.setTags(releasecontroller.Release{Name: "4.18.0-0.nightly-2025-01-01-000000", Phase: "Rejected"})
+
+	rc := f.start(t)
+
+	w := NewWatcher(rc, []string{"4.18.0-0.nightly"}, time.Hour)
+
+	events, err := w.Poll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "4.18.0-0.nightly has never had a release accepted", events[0].Summary())
+}
+
+func TestWatcherWithoutTimestamp(t *testing.T) {
+	f := &fakeReleaseController{
+		releaseInfos: map[string]*releasecontroller.ReleaseInfo{
+			"4.18.3": {Config: releasecontroller.Config{Created: "2025-01-01T00:00:00Z"}},
+		},
+	}
+
+	rc := f.start(t)
+
+	now := time.Date(2025, time.January, 3, 0, 0, 0, 0, time.UTC)
+
+	w := NewWatcher(rc, []string{"unknown", "4-stable"}, 24*time.Hour)
+	w.now = func() time.Time { return now }
+
+	// The creation time of the release payload is used when the release tag
+	// does not have a timestamp, and a release stream which cannot be polled
+	// does not stop the others from being polled.
+	f.setTags(releasecontroller.Release{Name: "4.18.3", Phase: "Accepted"})
+
+	events, err := w.Poll(context.Background())
+	assert.Error(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "4-stable has not had a release accepted in 48h0m0s, last accepted: 4.18.3", events[0].Summary())
+
+	// A release tag whose age cannot be determined is not treated as fresh.
+	f.setTags(releasecontroller.Release{Name: "4.18.4", Phase: "Accepted"})
+
+	w = NewWatcher(rc, []string{"4-stable"}, 24*time.Hour)
+	w.now = func() time.Time { return now }
+
+	_, err = w.Poll(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,249 @@
+package printers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Output formats which may be passed to NewPrinter. The JSONPath and
+// GoTemplate formats are followed by an = and the expression to evaluate
+// (e.g., jsonpath={.tags[*].name}), mirroring $ kubectl get -o.
+const (
+	JSON       string = "json"
+	YAML       string = "yaml"
+	Table      string = "table"
+	Name       string = "name"
+	JSONPath   string = "jsonpath"
+	GoTemplate string = "go-template"
+)
+
+// Formats returns the description of each supported output format.
+func Formats() []string {
+	return []string{JSON, YAML, Table, Name, JSONPath + "=...", GoTemplate + "=..."}
+}
+
+// Printer prints an object to the given writer.
+type Printer interface {
+	Print(io.Writer, interface{}) error
+}
+
+// TableData holds an object rendered as rows and columns.
+type TableData struct {
+	Headers []string
+	Rows    [][]string
+	// Names holds the name of the object each row describes. It is used by the
+	// name output format.
+	Names []string
+}
+
+// TableFunc converts an object into TableData. Callers provide this since
+// only they know which columns are meaningful for the objects they print.
+type TableFunc func(interface{}) (*TableData, error)
+
+// PrinterFunc allows a plain function to be used as a Printer.
+type PrinterFunc func(io.Writer, interface{}) error
+
+func (p PrinterFunc) Print(w io.Writer, obj interface{}) error {
+	return p(w, obj)
+}
+
+// NewPrinter returns a Printer for the given output format. The table and
+// name formats use the given TableFunc to convert objects.
+func NewPrinter(output string, tableFunc TableFunc) (Printer, error) {
+	format, arg, hasArg := strings.Cut(output, "=")
+
+	switch format {
+	case JSON, YAML, Table, Name:
+		if hasArg {
+			return nil, fmt.Errorf("output format %q does not take an argument", format)
+		}
+	case JSONPath, GoTemplate:
+		if arg == "" {
+			return nil, fmt.Errorf("output format %q requires an argument (e.g., %s=...)", format, format)
+		}
+	}
+
+	switch format {
+	case JSON:
+		return PrinterFunc(printJSON), nil
+	case YAML:
+		return PrinterFunc(printYAML), nil
+	case Table:
+		return newTablePrinter(tableFunc), nil
+	case Name:
+		return newNamePrinter(tableFunc), nil
+	case JSONPath:
+		return newJSONPathPrinter(arg)
+	case GoTemplate:
+		return newGoTemplatePrinter(arg)
+	}
+
+	return nil, fmt.Errorf("unknown output format %q, expected one of: %v", output, Formats())
+}
+
+// Converts the given object into JSON bytes. Byte slices are assumed to
+// already be JSON, as is the case for some release controller responses.
+func toJSON(obj interface{}) ([]byte, error) {
+	if b, ok := obj.([]byte); ok {
+		return b, nil
+	}
+
+	return json.Marshal(obj)
+}
+
+// Converts the given object into its generic JSON representation so that
+// JSONPath and Go template expressions see the same field names as the JSON
+// output.
+func toGeneric(obj interface{}) (interface{}, error) {
+	jsonBytes, err := toJSON(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var out interface{}
+	if err := json.Unmarshal(jsonBytes, &out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func printJSON(w io.Writer, obj interface{}) error {
+	jsonBytes, err := toJSON(obj)
+	if err != nil {
+		return err
+	}
+
+	outBuf := bytes.NewBuffer([]byte{})
+	if err := json.Indent(outBuf, jsonBytes, "", "    "); err != nil {
+		return err
+	}
+
+	_, err = w.Write(outBuf.Bytes())
+	return err
+}
+
+func printYAML(w io.Writer, obj interface{}) error {
+	jsonBytes, err := toJSON(obj)
+	if err != nil {
+		return err
+	}
+
+	yamlBytes, err := yaml.JSONToYAML(jsonBytes)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(yamlBytes)
+	return err
+}
+
+func newTablePrinter(tableFunc TableFunc) Printer {
+	return PrinterFunc(func(w io.Writer, obj interface{}) error {
+		data, err := getTableData(tableFunc, obj)
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(data.Headers, "\t"))
+
+		for _, row := range data.Rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+
+		return tw.Flush()
+	})
+}
+
+func newNamePrinter(tableFunc TableFunc) Printer {
+	return PrinterFunc(func(w io.Writer, obj interface{}) error {
+		data, err := getTableData(tableFunc, obj)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range data.Names {
+			if _, err := fmt.Fprintln(w, name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func getTableData(tableFunc TableFunc, obj interface{}) (*TableData, error) {
+	if tableFunc == nil {
+		return nil, fmt.Errorf("table output is not supported")
+	}
+
+	return tableFunc(obj)
+}
+
+func newJSONPathPrinter(expr string) (Printer, error) {
+	relaxed, err := relaxedJSONPathExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := jsonpath.New("output")
+	if err := parser.Parse(relaxed); err != nil {
+		return nil, fmt.Errorf("could not parse jsonpath %q: %w", expr, err)
+	}
+
+	return PrinterFunc(func(w io.Writer, obj interface{}) error {
+		generic, err := toGeneric(obj)
+		if err != nil {
+			return err
+		}
+
+		if err := parser.Execute(w, generic); err != nil {
+			return fmt.Errorf("could not execute jsonpath %q: %w", expr, err)
+		}
+
+		return nil
+	}), nil
+}
+
+// Allows JSONPath expressions without the surrounding braces or leading dot
+// (e.g., .tags[*].name or tags[*].name), as $ kubectl does.
+func relaxedJSONPathExpression(expr string) (string, error) {
+	if strings.HasPrefix(expr, "{") {
+		if !strings.HasSuffix(expr, "}") {
+			return "", fmt.Errorf("unterminated jsonpath expression %q", expr)
+		}
+
+		return expr, nil
+	}
+
+	return fmt.Sprintf("{.%s}", strings.TrimPrefix(expr, ".")), nil
+}
+
+func newGoTemplatePrinter(tmpl string) (Printer, error) {
+	t, err := template.New("output").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse go-template %q: %w", tmpl, err)
+	}
+
+	return PrinterFunc(func(w io.Writer, obj interface{}) error {
+		generic, err := toGeneric(obj)
+		if err != nil {
+			return err
+		}
+
+		if err := t.Execute(w, generic); err != nil {
+			return fmt.Errorf("could not execute go-template %q: %w", tmpl, err)
+		}
+
+		return nil
+	}), nil
+}
@@ -0,0 +1,178 @@
+package printers
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRelease struct {
+	Name  string `json:"name"`
+	Phase string `json:"phase"`
+}
+
+type testReleases struct {
+	Stream string        `json:"stream"`
+	Tags   []testRelease `json:"tags"`
+}
+
+func testTableFunc(obj interface{}) (*TableData, error) {
+	releases, ok := obj.(*testReleases)
+	if !ok {
+		return nil, fmt.Errorf("unsupported type %T", obj)
+	}
+
+	out := &TableData{Headers: []string{"NAME", "PHASE"}}
+	for _, tag := range releases.Tags {
+		out.Rows = append(out.Rows, []string{tag.Name, tag.Phase})
+		out.Names = append(out.Names, tag.Name)
+	}
+
+	return out, nil
+}
+
+func TestPrinters(t *testing.T) {
+	obj := &testReleases{
+		Stream: "4.23.0-0.ci",
+		Tags: []testRelease{
+			{Name: "4.23.0-0.ci-2026-03-05-153752", Phase: "Accepted"},
+			{Name: "4.23.0-0.ci-2026-03-05-093752", Phase: "Rejected"},
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		output    string
+		obj       interface{}
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:   "JSON",
+			output: "json",
+			obj:    obj,
+			expected: `{
+    "stream": "4.23.0-0.ci",
+    "tags": [
+        {
+            "name": "4.23.0-0.ci-2026-03-05-153752",
+            "phase": "Accepted"
+        },
+        {
+            "name": "4.23.0-0.ci-2026-03-05-093752",
+            "phase": "Rejected"
+        }
+    ]
+}`,
+		},
+		{
+			name:     "JSON bytes",
+			output:   "json",
+			obj:      []byte(`{"name":"4-stable"}`),
+			expected: "{\n    \"name\": \"4-stable\"\n}",
+		},
+		{
+			name:   "YAML",
+			output: "yaml",
+			obj:    obj,
+			expected: `stream: 4.23.0-0.ci
+tags:
+- name: 4.23.0-0.ci-2026-03-05-153752
+  phase: Accepted
+- name: 4.23.0-0.ci-2026-03-05-093752
+  phase: Rejected
+`,
+		},
+		{
+			name:   "Table",
+			output: "table",
+			obj:    obj,
+			expected: `NAME                            PHASE
+4.23.0-0.ci-2026-03-05-153752   Accepted
+4.23.0-0.ci-2026-03-05-093752   Rejected
+`,
+		},
+		{
+			name:     "Name",
+			output:   "name",
+			obj:      obj,
+			expected: "4.23.0-0.ci-2026-03-05-153752\n4.23.0-0.ci-2026-03-05-093752\n",
+		},
+		{
+			name:      "Table with unsupported type",
+			output:    "table",
+			obj:       []string{"4-stable"},
+			expectErr: true,
+		},
+		{
+			name:     "JSONPath",
+			output:   "jsonpath={.tags[*].name}",
+			obj:      obj,
+			expected: "4.23.0-0.ci-2026-03-05-153752 4.23.0-0.ci-2026-03-05-093752",
+		},
+		{
+			name:     "Relaxed JSONPath",
+			output:   "jsonpath=.tags[0].phase",
+			obj:      obj,
+			expected: "Accepted",
+		},
+		{
+			name:     "JSONPath with range",
+			output:   `jsonpath={range .tags[*]}{.name}{"\t"}{.phase}{"\n"}{end}`,
+			obj:      obj,
+			expected: "4.23.0-0.ci-2026-03-05-153752\tAccepted\n4.23.0-0.ci-2026-03-05-093752\tRejected\n",
+		},
+		{
+			name:     "Go template",
+			output:   `go-template={{range .tags}}{{.name}} {{.phase}}{{"\n"}}{{end}}`,
+			obj:      obj,
+			expected: "4.23.0-0.ci-2026-03-05-153752 Accepted\n4.23.0-0.ci-2026-03-05-093752 Rejected\n",
+		},
+		{
+			name:      "Missing JSONPath field",
+			output:    "jsonpath={.missing}",
+			obj:       obj,
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			p, err := NewPrinter(testCase.output, testTableFunc)
+			require.NoError(t, err)
+
+			buf := bytes.NewBuffer([]byte{})
+			err = p.Print(buf, testCase.obj)
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, buf.String())
+		})
+	}
+}
+
+func TestNewPrinterInvalid(t *testing.T) {
+	testCases := []string{
+		"",
+		"xml",
+		"json=.foo",
+		"jsonpath",
+		"jsonpath=",
+		"jsonpath={.foo",
+		"go-template",
+		"go-template={{.foo",
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase, func(t *testing.T) {
+			_, err := NewPrinter(testCase, testTableFunc)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package proxy
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+type metrics struct {
+	registry *prometheus.Registry
+	// Requests served, by how the cache served them.
+	requests *prometheus.CounterVec
+	// Requests made to the release controller, by HTTP status code.
+	upstreamRequests *prometheus.CounterVec
+	upstreamDuration prometheus.Histogram
+	// Background refreshes of cached responses, by result.
+	refreshes *prometheus.CounterVec
+}
+
+// Each Server has its own registry so that multiple servers (e.g., in tests)
+// do not conflict with each other.
+func newMetrics(cacheEntries func() int) *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rcctl_serve_requests_total",
+			Help: "Number of requests served, by cache result (hit, miss, bypass or error).",
+		}, []string{"cache"}),
+		upstreamRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rcctl_serve_upstream_requests_total",
+			Help: "Number of requests made to the release controller, by HTTP status code (or error).",
+		}, []string{"code"}),
+		upstreamDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "rcctl_serve_upstream_request_duration_seconds",
+			Help:    "Duration of requests made to the release controller.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		refreshes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rcctl_serve_refreshes_total",
+			Help: "Number of background refreshes of cached responses, by result (success or failure).",
+		}, []string{"result"}),
+	}
+
+	m.registry.MustRegister(
+		m.requests,
+		m.upstreamRequests,
+		m.upstreamDuration,
+		m.refreshes,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "rcctl_serve_cache_entries",
+			Help: "Number of cached responses.",
+		}, func() float64 {
+			return float64(cacheEntries())
+		}),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return m
+}
+
+func (m *metrics) handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
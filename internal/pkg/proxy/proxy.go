@@ -0,0 +1,499 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
+	"k8s.io/klog"
+)
+
+const (
+	// LatestPath serves the latest accepted release tag for each release
+	// stream as a map of release stream name to release tag name.
+	LatestPath string = "/rcctl/v1/latest"
+	// UpstreamPath serves the release controller the proxy caches (see
+	// Upstream) so that clients can link to it rather than to the proxy.
+	UpstreamPath string = "/rcctl/v1/upstream"
+	// HealthzPath serves the health of the proxy.
+	HealthzPath string = "/healthz"
+	// MetricsPath serves Prometheus metrics for the proxy.
+	MetricsPath string = "/metrics"
+
+	// CacheHeader tells clients how a response was served; one of hit, miss,
+	// bypass (for responses which are not cached), or error.
+	CacheHeader string = "X-Rcctl-Cache"
+
+	cacheHit    string = "hit"
+	cacheMiss   string = "miss"
+	cacheBypass string = "bypass"
+	cacheError  string = "error"
+
+	acceptedPath string = "/api/v1/releasestreams/accepted"
+
+	// How many cached responses are refreshed concurrently.
+	refreshConcurrency int = 4
+)
+
+// The release info for a release tag never changes once it exists.
+var immutablePathRegex = regexp.MustCompile(`^/releasetag/[^/]+/json$`)
+
+// Upstream describes the release controller a proxy caches.
+type Upstream struct {
+	// Controller is the release controller in the form accepted by
+	// releasecontroller.NewFromURL.
+	Controller string `json:"controller"`
+}
+
+type response struct {
+	status      int
+	contentType string
+	body        []byte
+}
+
+type entry struct {
+	*response
+	fetched    time.Time
+	lastAccess time.Time
+}
+
+// ServerOpts configures a Server.
+type ServerOpts struct {
+	// RefreshInterval is how often Run refreshes the cached responses. Only
+	// responses which were requested since the previous refresh are refreshed;
+	// any other response which is older than twice the interval is fetched
+	// again the next time it is requested.
+	RefreshInterval time.Duration
+	// EvictAfter is how long a cached response may go without being requested
+	// before it is evicted during the next refresh. Responses are never
+	// evicted for being idle if it is zero.
+	EvictAfter time.Duration
+	// MaxEntries is the maximum number of cached responses. Once it is
+	// reached, the least recently requested response is evicted to make room
+	// for a new one. The number of cached responses is not limited if it is
+	// zero.
+	MaxEntries int
+}
+
+// Server is a caching proxy for a release controller. It serves the same
+// endpoints as the release controller, as well as derived views, from an
+// in-memory cache which is refreshed in the background by Run. Only
+// successful responses are cached.
+type Server struct {
+	upstream *releasecontroller.ReleaseController
+	opts     ServerOpts
+	mux      *http.ServeMux
+	metrics  *metrics
+	group    singleflight.Group
+
+	mu sync.Mutex
+	// Request URI -> cached response.
+	entries map[string]*entry
+
+	// Allows the current time to be overridden for testing.
+	now func() time.Time
+}
+
+// NewServer creates a Server for the given release controller.
+func NewServer(upstream *releasecontroller.ReleaseController, opts ServerOpts) *Server {
+	s := &Server{
+		upstream: upstream,
+		opts:     opts,
+		mux:      http.NewServeMux(),
+		entries:  map[string]*entry{},
+		now:      time.Now,
+	}
+
+	s.metrics = newMetrics(s.len)
+
+	s.mux.HandleFunc("GET "+HealthzPath, s.handleHealthz)
+	s.mux.Handle("GET "+MetricsPath, s.metrics.handler())
+	s.mux.HandleFunc("GET "+LatestPath, s.handleLatest)
+	s.mux.HandleFunc("GET "+UpstreamPath, s.handleUpstream)
+	s.mux.HandleFunc("GET /", s.handleProxy)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Run refreshes the cache at the refresh interval until the context is done.
+// Errors are logged rather than returned so that a temporary problem with the
+// release controller does not stop the refreshes; the previously cached
+// responses continue to be served in the meantime. Nothing is refreshed if the
+// refresh interval is not positive.
+func (s *Server) Run(ctx context.Context) error {
+	if s.opts.RefreshInterval <= 0 {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	ticker := time.NewTicker(s.opts.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		if err := s.Refresh(ctx); err != nil {
+			klog.Warningf("Could not refresh all cached responses: %s", err)
+		}
+	}
+}
+
+// Refresh evicts the cached responses which have not been requested recently
+// and re-fetches those which were requested within the refresh interval from
+// the release controller, except for those which never change. A response
+// which can no longer be found is evicted, whereas any other failure keeps the
+// previously cached response.
+func (s *Server) Refresh(ctx context.Context) error {
+	now := s.now()
+	keys := []string{}
+
+	s.mu.Lock()
+	for key, e := range s.entries {
+		idle := now.Sub(e.lastAccess)
+
+		if s.opts.EvictAfter > 0 && idle > s.opts.EvictAfter {
+			delete(s.entries, key)
+			continue
+		}
+
+		if !isImmutable(key) && idle <= s.opts.RefreshInterval {
+			keys = append(keys, key)
+		}
+	}
+	s.mu.Unlock()
+
+	errs := []error{}
+	errMu := &sync.Mutex{}
+
+	g := &errgroup.Group{}
+	g.SetLimit(refreshConcurrency)
+
+	for _, key := range keys {
+		g.Go(func() error {
+			err := s.refresh(ctx, key)
+
+			result := "success"
+			if err != nil {
+				result = "failure"
+
+				errMu.Lock()
+				errs = append(errs, err)
+				errMu.Unlock()
+			}
+
+			s.metrics.refreshes.WithLabelValues(result).Inc()
+
+			return nil
+		})
+	}
+
+	//nolint:errcheck // Errors are collected above so that one failure does not stop the others.
+	g.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (s *Server) refresh(ctx context.Context, key string) error {
+	resp, err := s.fetch(ctx, key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	if resp.status == http.StatusNotFound {
+		s.mu.Lock()
+		delete(s.entries, key)
+		s.mu.Unlock()
+		return nil
+	}
+
+	if resp.status != http.StatusOK {
+		return fmt.Errorf("%s: got HTTP %d", key, resp.status)
+	}
+
+	return nil
+}
+
+// Gets the response for the given request URI from the cache, fetching it from
+// the release controller if it is not cached or has gone stale. Concurrent
+// requests for the same request URI share a single request to the release
+// controller.
+func (s *Server) get(ctx context.Context, key string) (*response, time.Time, string, error) {
+	s.mu.Lock()
+	e, cached := s.entries[key]
+	if cached {
+		e.lastAccess = s.now()
+	}
+	s.mu.Unlock()
+
+	if cached && !s.isStale(key, e) {
+		return e.response, e.fetched, cacheHit, nil
+	}
+
+	resp, err := s.fetch(ctx, key)
+
+	// As with a failed refresh, the stale response is served if the release
+	// controller could not provide a new one.
+	if cached && (err != nil || (resp.status != http.StatusOK && resp.status != http.StatusNotFound)) {
+		return e.response, e.fetched, cacheHit, nil
+	}
+
+	if err != nil {
+		return nil, time.Time{}, cacheError, err
+	}
+
+	if resp.status == http.StatusNotFound {
+		s.mu.Lock()
+		delete(s.entries, key)
+		s.mu.Unlock()
+	}
+
+	if resp.status != http.StatusOK {
+		return resp, time.Time{}, cacheBypass, nil
+	}
+
+	return resp, s.now(), cacheMiss, nil
+}
+
+// Responses which were requested since the previous refresh are kept fresh by
+// Refresh, so a cached response is only stale once it has missed a refresh.
+// Twice the refresh interval allows for the time a refresh takes.
+func (s *Server) isStale(key string, e *entry) bool {
+	if s.opts.RefreshInterval <= 0 || isImmutable(key) {
+		return false
+	}
+
+	return s.now().Sub(e.fetched) > 2*s.opts.RefreshInterval
+}
+
+// Fetches the given request URI from the release controller, caching the
+// response if it is successful.
+func (s *Server) fetch(ctx context.Context, key string) (*response, error) {
+	v, err, _ := s.group.Do(key, func() (interface{}, error) {
+		// The fetch is shared between requests, so it must not be canceled
+		// when the request that started it goes away.
+		return s.fetchUncoalesced(context.WithoutCancel(ctx), key)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*response), nil
+}
+
+func (s *Server) fetchUncoalesced(ctx context.Context, key string) (*response, error) {
+	start := s.now()
+
+	resp, err := s.upstream.Do(ctx, key)
+	if err != nil {
+		s.metrics.upstreamRequests.WithLabelValues(cacheError).Inc()
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		s.metrics.upstreamRequests.WithLabelValues(cacheError).Inc()
+		return nil, err
+	}
+
+	s.metrics.upstreamRequests.WithLabelValues(strconv.Itoa(resp.StatusCode)).Inc()
+	s.metrics.upstreamDuration.Observe(s.now().Sub(start).Seconds())
+
+	out := &response{
+		status:      resp.StatusCode,
+		contentType: resp.Header.Get("Content-Type"),
+		body:        body,
+	}
+
+	if out.status == http.StatusOK {
+		s.store(key, out)
+	}
+
+	return out, nil
+}
+
+// Stores the given response, keeping the last access time of any response it
+// replaces so that refreshing a response does not keep it from being evicted.
+// If the cache is full, the least recently requested response is evicted to
+// make room.
+func (s *Server) store(key string, resp *response) {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lastAccess := now
+	if existing, ok := s.entries[key]; ok {
+		lastAccess = existing.lastAccess
+	} else if s.opts.MaxEntries > 0 && len(s.entries) >= s.opts.MaxEntries {
+		s.evictLeastRecentlyAccessed()
+	}
+
+	s.entries[key] = &entry{
+		response:   resp,
+		fetched:    now,
+		lastAccess: lastAccess,
+	}
+}
+
+// The caller must hold the lock.
+func (s *Server) evictLeastRecentlyAccessed() {
+	oldestKey := ""
+	var oldest time.Time
+
+	for key, e := range s.entries {
+		if oldestKey == "" || e.lastAccess.Before(oldest) {
+			oldestKey = key
+			oldest = e.lastAccess
+		}
+	}
+
+	delete(s.entries, oldestKey)
+}
+
+func (s *Server) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.entries)
+}
+
+func (s *Server) handleProxy(w http.ResponseWriter, r *http.Request) {
+	resp, fetched, result, err := s.get(r.Context(), r.URL.RequestURI())
+	s.metrics.requests.WithLabelValues(result).Inc()
+
+	if err != nil {
+		w.Header().Set(CacheHeader, result)
+		http.Error(w, fmt.Sprintf("could not reach release controller %s: %s", s.upstream, err), http.StatusBadGateway)
+		return
+	}
+
+	if resp.contentType != "" {
+		w.Header().Set("Content-Type", resp.contentType)
+	}
+
+	s.writeResponse(w, resp.status, resp.body, fetched, result)
+}
+
+// Serves the latest accepted release tag for each release stream, which is
+// the first of the accepted release tags the release controller lists.
+func (s *Server) handleLatest(w http.ResponseWriter, r *http.Request) {
+	resp, fetched, result, err := s.get(r.Context(), acceptedPath)
+	s.metrics.requests.WithLabelValues(result).Inc()
+
+	if err != nil {
+		w.Header().Set(CacheHeader, result)
+		http.Error(w, fmt.Sprintf("could not reach release controller %s: %s", s.upstream, err), http.StatusBadGateway)
+		return
+	}
+
+	if resp.status != http.StatusOK {
+		http.Error(w, fmt.Sprintf("got HTTP %d from release controller %s", resp.status, s.upstream), http.StatusBadGateway)
+		return
+	}
+
+	accepted := map[string][]string{}
+	if err := json.Unmarshal(resp.body, &accepted); err != nil {
+		http.Error(w, fmt.Sprintf("could not parse accepted release tags: %s", err), http.StatusBadGateway)
+		return
+	}
+
+	latest := map[string]string{}
+	for stream, tags := range accepted {
+		if len(tags) > 0 {
+			latest[stream] = tags[0]
+		}
+	}
+
+	body, err := json.Marshal(latest)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	s.writeResponse(w, http.StatusOK, body, fetched, result)
+}
+
+func (s *Server) handleUpstream(w http.ResponseWriter, _ *http.Request) {
+	body, err := json.Marshal(Upstream{Controller: s.upstream.String()})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	//nolint:errcheck // There is nothing to do if the client went away.
+	w.Write(body)
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	//nolint:errcheck // There is nothing to do if the client went away.
+	w.Write([]byte("ok\n"))
+}
+
+func (s *Server) writeResponse(w http.ResponseWriter, status int, body []byte, fetched time.Time, result string) {
+	w.Header().Set(CacheHeader, result)
+
+	if result == cacheHit {
+		w.Header().Set("Age", strconv.Itoa(int(s.now().Sub(fetched).Seconds())))
+	}
+
+	w.WriteHeader(status)
+
+	//nolint:errcheck // There is nothing to do if the client went away.
+	w.Write(body)
+}
+
+func isImmutable(key string) bool {
+	path, _, _ := strings.Cut(key, "?")
+	return immutablePathRegex.MatchString(path)
+}
+
+// GetUpstream gets the release controller which the given release controller
+// caches if it is a proxy served by rcctl serve, or nil if it is not.
+func GetUpstream(ctx context.Context, rc *releasecontroller.ReleaseController) (*releasecontroller.ReleaseController, error) {
+	resp, err := rc.Do(ctx, UpstreamPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got HTTP %d from %s%s", resp.StatusCode, rc, UpstreamPath)
+	}
+
+	out := Upstream{}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("could not parse upstream of %s: %w", rc, err)
+	}
+
+	return releasecontroller.NewFromURL(out.Controller, nil)
+}
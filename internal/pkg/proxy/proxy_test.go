@@ -0,0 +1,408 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUpstream serves fixed responses and counts how many times each path was
+// requested.
+type fakeUpstream struct {
+	mu        sync.Mutex
+	responses map[string]fakeResponse
+	requests  map[string]int
+}
+
+type fakeResponse struct {
+	status int
+	body   string
+}
+
+func newFakeUpstream(responses map[string]fakeResponse) *fakeUpstream {
+	return &fakeUpstream{
+		responses: responses,
+		requests:  map[string]int{},
+	}
+}
+
+func (f *fakeUpstream) set(uri string, resp fakeResponse) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.responses[uri] = resp
+}
+
+func (f *fakeUpstream) count(uri string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.requests[uri]
+}
+
+func (f *fakeUpstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests[r.URL.RequestURI()]++
+
+	resp, ok := f.responses[r.URL.RequestURI()]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.status)
+	//nolint:errcheck // This is test code.
+	w.Write([]byte(resp.body))
+}
+
+func newTestServer(t *testing.T, upstream http.Handler, opts ServerOpts) *Server {
+	t.Helper()
+
+	srv := httptest.NewServer(upstream)
+	t.Cleanup(srv.Close)
+
+	rc, err := releasecontroller.NewFromURL(srv.URL, nil)
+	require.NoError(t, err)
+
+	return NewServer(rc, opts)
+}
+
+func doRequest(s *Server, method, uri string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(method, uri, nil))
+	return w
+}
+
+func TestServerCaching(t *testing.T) {
+	const (
+		latestPath  = "/api/v1/releasestream/4-stable/latest"
+		tagsPath    = "/api/v1/releasestream/4-stable/tags?phase=Accepted"
+		missingPath = "/api/v1/releasestream/missing/latest"
+	)
+
+	upstream := newFakeUpstream(map[string]fakeResponse{
+		latestPath: {status: http.StatusOK, body: `{"name":"4.19.3"}`},
+		tagsPath:   {status: http.StatusOK, body: `{"name":"4-stable","tags":[]}`},
+	})
+
+	s := newTestServer(t, upstream, ServerOpts{})
+
+	testCases := []struct {
+		name             string
+		uri              string
+		expectedStatus   int
+		expectedCache    string
+		expectedBody     string
+		expectedUpstream int
+	}{
+		{
+			name:             "First request is fetched",
+			uri:              latestPath,
+			expectedStatus:   http.StatusOK,
+			expectedCache:    cacheMiss,
+			expectedBody:     `{"name":"4.19.3"}`,
+			expectedUpstream: 1,
+		},
+		{
+			name:             "Second request is cached",
+			uri:              latestPath,
+			expectedStatus:   http.StatusOK,
+			expectedCache:    cacheHit,
+			expectedBody:     `{"name":"4.19.3"}`,
+			expectedUpstream: 1,
+		},
+		{
+			name:             "Query is part of the cache key",
+			uri:              tagsPath,
+			expectedStatus:   http.StatusOK,
+			expectedCache:    cacheMiss,
+			expectedBody:     `{"name":"4-stable","tags":[]}`,
+			expectedUpstream: 1,
+		},
+		{
+			name:             "Not found is passed through",
+			uri:              missingPath,
+			expectedStatus:   http.StatusNotFound,
+			expectedCache:    cacheBypass,
+			expectedUpstream: 1,
+		},
+		{
+			name:             "Not found is not cached",
+			uri:              missingPath,
+			expectedStatus:   http.StatusNotFound,
+			expectedCache:    cacheBypass,
+			expectedUpstream: 2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := doRequest(s, http.MethodGet, testCase.uri)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.Equal(t, testCase.expectedCache, w.Header().Get(CacheHeader))
+			assert.Equal(t, testCase.expectedUpstream, upstream.count(testCase.uri))
+
+			if testCase.expectedBody != "" {
+				assert.Equal(t, testCase.expectedBody, w.Body.String())
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+
+	assert.Equal(t, http.StatusMethodNotAllowed, doRequest(s, http.MethodPost, latestPath).Code)
+}
+
+func TestServerRefresh(t *testing.T) {
+	const (
+		latestPath      = "/api/v1/releasestream/4-stable/latest"
+		releaseInfoPath = "/releasetag/4.19.3/json"
+		flakyPath       = "/api/v1/releasestream/4-stable/release/4.19.3"
+		deletedPath     = "/api/v1/releasestream/4-stable/release/4.19.2"
+	)
+
+	upstream := newFakeUpstream(map[string]fakeResponse{
+		latestPath:      {status: http.StatusOK, body: `{"name":"4.19.3"}`},
+		releaseInfoPath: {status: http.StatusOK, body: `{"metadata":{"version":"4.19.3"}}`},
+		flakyPath:       {status: http.StatusOK, body: `{"phase":"Ready"}`},
+		deletedPath:     {status: http.StatusOK, body: `{"phase":"Accepted"}`},
+	})
+
+	now := time.Date(2026, time.March, 5, 12, 0, 0, 0, time.UTC)
+
+	s := newTestServer(t, upstream, ServerOpts{RefreshInterval: 5 * time.Minute, EvictAfter: time.Hour})
+	s.now = func() time.Time { return now }
+
+	for _, uri := range []string{latestPath, releaseInfoPath, flakyPath, deletedPath} {
+		require.Equal(t, http.StatusOK, doRequest(s, http.MethodGet, uri).Code)
+	}
+
+	upstream.set(latestPath, fakeResponse{status: http.StatusOK, body: `{"name":"4.19.4"}`})
+	upstream.set(flakyPath, fakeResponse{status: http.StatusServiceUnavailable})
+	upstream.set(deletedPath, fakeResponse{status: http.StatusNotFound})
+
+	now = now.Add(5 * time.Minute)
+
+	// The flaky response fails to refresh, so an error is returned.
+	assert.Error(t, s.Refresh(context.Background()))
+
+	// Refreshed responses are served from the cache.
+	w := doRequest(s, http.MethodGet, latestPath)
+	assert.Equal(t, cacheHit, w.Header().Get(CacheHeader))
+	assert.Equal(t, `{"name":"4.19.4"}`, w.Body.String())
+	assert.Equal(t, "0", w.Header().Get("Age"))
+	assert.Equal(t, 2, upstream.count(latestPath))
+
+	// Release info never changes, so it is not refreshed.
+	assert.Equal(t, 1, upstream.count(releaseInfoPath))
+
+	// The previous response is kept when a refresh fails.
+	w = doRequest(s, http.MethodGet, flakyPath)
+	assert.Equal(t, cacheHit, w.Header().Get(CacheHeader))
+	assert.Equal(t, `{"phase":"Ready"}`, w.Body.String())
+	assert.Equal(t, "300", w.Header().Get("Age"))
+
+	// Responses which can no longer be found are evicted.
+	w = doRequest(s, http.MethodGet, deletedPath)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, cacheBypass, w.Header().Get(CacheHeader))
+
+	// Only the latest response is requested within the eviction period, so
+	// everything else is evicted. It has missed several refreshes, so it is
+	// fetched again rather than served from the cache.
+	now = now.Add(59 * time.Minute)
+	assert.Equal(t, cacheMiss, doRequest(s, http.MethodGet, latestPath).Header().Get(CacheHeader))
+	now = now.Add(2 * time.Minute)
+
+	upstream.set(flakyPath, fakeResponse{status: http.StatusOK, body: `{"phase":"Accepted"}`})
+	assert.NoError(t, s.Refresh(context.Background()))
+	assert.Equal(t, 1, s.len())
+	assert.Equal(t, 4, upstream.count(latestPath))
+}
+
+func TestServerRefreshIdle(t *testing.T) {
+	const latestPath = "/api/v1/releasestream/4-stable/latest"
+
+	upstream := newFakeUpstream(map[string]fakeResponse{
+		latestPath: {status: http.StatusOK, body: `{"name":"4.19.3"}`},
+	})
+
+	now := time.Date(2026, time.March, 5, 12, 0, 0, 0, time.UTC)
+
+	s := newTestServer(t, upstream, ServerOpts{RefreshInterval: time.Minute})
+	s.now = func() time.Time { return now }
+
+	require.Equal(t, http.StatusOK, doRequest(s, http.MethodGet, latestPath).Code)
+
+	// The response was requested since the previous refresh.
+	now = now.Add(time.Minute)
+	assert.NoError(t, s.Refresh(context.Background()))
+	assert.Equal(t, 2, upstream.count(latestPath))
+
+	// The response has not been requested since the previous refresh.
+	now = now.Add(time.Minute)
+	assert.NoError(t, s.Refresh(context.Background()))
+	assert.Equal(t, 2, upstream.count(latestPath))
+
+	// The response has not missed a refresh yet, so it is still served from
+	// the cache.
+	assert.Equal(t, cacheHit, doRequest(s, http.MethodGet, latestPath).Header().Get(CacheHeader))
+	assert.Equal(t, 2, upstream.count(latestPath))
+
+	// Once it has, it is fetched again when it is requested.
+	now = now.Add(2 * time.Minute)
+	upstream.set(latestPath, fakeResponse{status: http.StatusOK, body: `{"name":"4.19.4"}`})
+
+	w := doRequest(s, http.MethodGet, latestPath)
+	assert.Equal(t, cacheMiss, w.Header().Get(CacheHeader))
+	assert.Equal(t, `{"name":"4.19.4"}`, w.Body.String())
+	assert.Equal(t, 3, upstream.count(latestPath))
+
+	// The stale response is served if it cannot be fetched again.
+	now = now.Add(3 * time.Minute)
+	upstream.set(latestPath, fakeResponse{status: http.StatusServiceUnavailable})
+
+	w = doRequest(s, http.MethodGet, latestPath)
+	assert.Equal(t, cacheHit, w.Header().Get(CacheHeader))
+	assert.Equal(t, `{"name":"4.19.4"}`, w.Body.String())
+	assert.Equal(t, "180", w.Header().Get("Age"))
+	assert.Equal(t, 4, upstream.count(latestPath))
+}
+
+func TestServerMaxEntries(t *testing.T) {
+	const (
+		aPath = "/api/v1/releasestream/a/latest"
+		bPath = "/api/v1/releasestream/b/latest"
+		cPath = "/api/v1/releasestream/c/latest"
+	)
+
+	upstream := newFakeUpstream(map[string]fakeResponse{
+		aPath: {status: http.StatusOK, body: `{"name":"a"}`},
+		bPath: {status: http.StatusOK, body: `{"name":"b"}`},
+		cPath: {status: http.StatusOK, body: `{"name":"c"}`},
+	})
+
+	now := time.Date(2026, time.March, 5, 12, 0, 0, 0, time.UTC)
+
+	s := newTestServer(t, upstream, ServerOpts{MaxEntries: 2})
+	s.now = func() time.Time { return now }
+
+	for _, uri := range []string{aPath, bPath, aPath, cPath} {
+		now = now.Add(time.Second)
+		require.Equal(t, http.StatusOK, doRequest(s, http.MethodGet, uri).Code)
+	}
+
+	// The response for b was the least recently requested, so it made room
+	// for the response for c.
+	assert.Equal(t, 2, s.len())
+	assert.Equal(t, cacheHit, doRequest(s, http.MethodGet, aPath).Header().Get(CacheHeader))
+	assert.Equal(t, cacheHit, doRequest(s, http.MethodGet, cPath).Header().Get(CacheHeader))
+	assert.Equal(t, cacheMiss, doRequest(s, http.MethodGet, bPath).Header().Get(CacheHeader))
+}
+
+func TestServerLatest(t *testing.T) {
+	upstream := newFakeUpstream(map[string]fakeResponse{
+		acceptedPath: {status: http.StatusOK, body: `{"4-stable":["4.19.3","4.19.2"],"4.20.0-0.nightly":["4.20.0-0.nightly-2026-03-05-153752"],"4-dev-preview":[]}`},
+	})
+
+	s := newTestServer(t, upstream, ServerOpts{})
+
+	for _, expectedCache := range []string{cacheMiss, cacheHit} {
+		w := doRequest(s, http.MethodGet, LatestPath)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, expectedCache, w.Header().Get(CacheHeader))
+
+		latest := map[string]string{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &latest))
+		assert.Equal(t, map[string]string{
+			"4-stable":         "4.19.3",
+			"4.20.0-0.nightly": "4.20.0-0.nightly-2026-03-05-153752",
+		}, latest)
+	}
+
+	assert.Equal(t, 1, upstream.count(acceptedPath))
+}
+
+func TestServerHealthzAndMetrics(t *testing.T) {
+	upstream := newFakeUpstream(map[string]fakeResponse{
+		acceptedPath: {status: http.StatusOK, body: `{}`},
+	})
+
+	s := newTestServer(t, upstream, ServerOpts{})
+
+	w := doRequest(s, http.MethodGet, HealthzPath)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok\n", w.Body.String())
+
+	doRequest(s, http.MethodGet, acceptedPath)
+	doRequest(s, http.MethodGet, acceptedPath)
+
+	w = doRequest(s, http.MethodGet, MetricsPath)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `rcctl_serve_requests_total{cache="hit"} 1`)
+	assert.Contains(t, w.Body.String(), `rcctl_serve_requests_total{cache="miss"} 1`)
+	assert.Contains(t, w.Body.String(), `rcctl_serve_upstream_requests_total{code="200"} 1`)
+	assert.Contains(t, w.Body.String(), `rcctl_serve_cache_entries 1`)
+
+	// The health and metrics endpoints are not proxied.
+	assert.Equal(t, 0, upstream.count(HealthzPath))
+	assert.Equal(t, 0, upstream.count(MetricsPath))
+}
+
+func TestServerUpstreamUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	rc, err := releasecontroller.NewFromURL(srv.URL, nil)
+	require.NoError(t, err)
+
+	s := NewServer(rc, ServerOpts{})
+
+	w := doRequest(s, http.MethodGet, acceptedPath)
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.Equal(t, cacheError, w.Header().Get(CacheHeader))
+
+	assert.Equal(t, http.StatusBadGateway, doRequest(s, http.MethodGet, LatestPath).Code)
+}
+
+func TestGetUpstream(t *testing.T) {
+	upstream := newFakeUpstream(map[string]fakeResponse{})
+	s := newTestServer(t, upstream, ServerOpts{})
+
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+
+	rc, err := releasecontroller.NewFromURL(srv.URL, nil)
+	require.NoError(t, err)
+
+	got, err := GetUpstream(context.Background(), rc)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, s.upstream.String(), got.String())
+
+	// The release controller being cached is not a proxy.
+	got, err = GetUpstream(context.Background(), s.upstream)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+	assert.Equal(t, 1, upstream.count(UpstreamPath))
+}
+
+func TestServerRunWithoutRefreshInterval(t *testing.T) {
+	s := newTestServer(t, newFakeUpstream(map[string]fakeResponse{}), ServerOpts{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, s.Run(ctx), context.Canceled)
+}
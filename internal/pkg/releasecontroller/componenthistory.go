@@ -0,0 +1,203 @@
+package releasecontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/filecache"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/klog"
+)
+
+const (
+	defaultComponentHistoryLast        int = 50
+	defaultComponentHistoryConcurrency int = 10
+)
+
+// ComponentHistoryOpts controls how much of a release stream's history is
+// walked by ComponentHistory.
+type ComponentHistoryOpts struct {
+	// Last is the number of most recent tags to walk. Defaults to 50.
+	Last int
+	// Concurrency is the maximum number of release info lookups that may be
+	// in-flight at once. Defaults to 10.
+	Concurrency int
+	// Cache, if given, is used to cache the release info for each tag. Since
+	// the payload for a release tag never changes, a long TTL is safe.
+	Cache *filecache.Cache
+}
+
+// ComponentChange describes the first release tag to carry a new image or
+// source commit for a component.
+type ComponentChange struct {
+	Component
+	// Tag is the first release tag which carried this image.
+	Tag   string `json:"tag"`
+	Phase string `json:"phase"`
+	// Tags is the number of consecutive release tags which carried this
+	// image, starting with Tag.
+	Tags int `json:"tags"`
+	// PreviousTag is the last release tag which carried the previous image.
+	// It is empty for the oldest release tag walked.
+	PreviousTag    string `json:"previousTag,omitempty"`
+	PreviousDigest string `json:"previousDigest,omitempty"`
+	PreviousCommit string `json:"previousCommit,omitempty"`
+	// DigestChanged is true when the image digest differs from the previous
+	// one.
+	DigestChanged bool `json:"digestChanged"`
+	// CommitChanged is true when the source commit differs from the previous
+	// one. The image may be rebuilt without a source change, e.g., when its
+	// base image changes.
+	CommitChanged bool `json:"commitChanged"`
+	// CompareURL links to the source changes between the previous and current
+	// commit, if the source is hosted on GitHub.
+	CompareURL string `json:"compareURL,omitempty"`
+}
+
+// GetReleaseInfoWithCache gets the release info for the given release tag
+// from the release controller, using the given cache if it is not nil.
+func (r *ReleaseController) GetReleaseInfoWithCache(ctx context.Context, tag string, cache *filecache.Cache) (*ReleaseInfo, error) {
+	fetch := func() (*ReleaseInfo, error) {
+		return r.GetReleaseInfo(ctx, tag)
+	}
+
+	if cache == nil {
+		return fetch()
+	}
+
+	return filecache.GetOrFetch(cache, path.Join(r.host, "releasetag", tag), fetch)
+}
+
+// ComponentHistory walks the most recent tags in the release stream from
+// oldest to newest and returns every point where the image digest or source
+// commit for the given component changed, newest first. The oldest tag walked
+// is always included as the starting point. Tags whose release info cannot be
+// retrieved (e.g., because they were garbage-collected) are skipped.
+func (r *ReleaseStream) ComponentHistory(ctx context.Context, component string, opts ComponentHistoryOpts) ([]ComponentChange, error) {
+	if opts.Last <= 0 {
+		opts.Last = defaultComponentHistoryLast
+	}
+
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultComponentHistoryConcurrency
+	}
+
+	tags, err := r.Tags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get tags for release stream %q: %w", r.name, err)
+	}
+
+	// The release controller returns tags in newest-first order.
+	toWalk := tags.Tags
+	if len(toWalk) > opts.Last {
+		toWalk = toWalk[:opts.Last]
+	}
+
+	components := make([]*Component, len(toWalk))
+	errs := make([]error, len(toWalk))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(opts.Concurrency)
+
+	for i, tag := range toWalk {
+		g.Go(func() error {
+			ri, err := r.rc.GetReleaseInfoWithCache(gctx, tag.Name, opts.Cache)
+			if err != nil {
+				errs[i] = err
+				return nil
+			}
+
+			for _, c := range ri.Components() {
+				if c.Name == component {
+					components[i] = &c
+					break
+				}
+			}
+
+			return nil
+		})
+	}
+
+	// Errors are recorded per tag so that one missing tag does not fail the
+	// whole walk.
+	_ = g.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	out := []ComponentChange{}
+	found := false
+	// The last tag walked, which carries the state of the last change.
+	previousTag := ""
+
+	for i := len(toWalk) - 1; i >= 0; i-- {
+		tag := toWalk[i]
+
+		if errs[i] != nil {
+			klog.Warningf("Could not get release info for release tag %q: %s", tag.Name, errs[i])
+			continue
+		}
+
+		found = found || components[i] != nil
+
+		current := Component{Name: component}
+		if components[i] != nil {
+			current = *components[i]
+		}
+
+		if len(out) != 0 && out[len(out)-1].Digest == current.Digest && out[len(out)-1].Commit == current.Commit {
+			out[len(out)-1].Tags++
+		} else {
+			out = append(out, newComponentChange(tag, current, previousTag, out))
+		}
+
+		previousTag = tag.Name
+	}
+
+	if len(out) == 0 && len(toWalk) != 0 {
+		return nil, fmt.Errorf("could not get release info for any tags in release stream %q: %w", r.name, errors.Join(errs...))
+	}
+
+	if !found {
+		return nil, fmt.Errorf("component %q not found in the last %d tags of release stream %q", component, len(toWalk), r.name)
+	}
+
+	// Return the changes newest first to match the release controller.
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+
+	return out, nil
+}
+
+// Describes how the component in the given tag differs from the last change
+// seen, if any.
+func newComponentChange(tag Release, current Component, previousTag string, seen []ComponentChange) ComponentChange {
+	out := ComponentChange{
+		Component: current,
+		Tag:       tag.Name,
+		Phase:     tag.Phase,
+		Tags:      1,
+	}
+
+	if len(seen) == 0 {
+		return out
+	}
+
+	previous := seen[len(seen)-1]
+
+	out.PreviousTag = previousTag
+	out.PreviousDigest = previous.Digest
+	out.PreviousCommit = previous.Commit
+	out.DigestChanged = previous.Digest != current.Digest
+	out.CommitChanged = previous.Commit != current.Commit
+
+	if out.CommitChanged && previous.Commit != "" && current.CommitURL != "" {
+		out.CompareURL = compareURL(current.Repo, previous.Commit, current.Commit)
+	}
+
+	return out
+}
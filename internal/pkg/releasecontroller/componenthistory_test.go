@@ -0,0 +1,141 @@
+package releasecontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/filecache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	mcoDigestThree = "sha256:4444444444444444444444444444444444444444444444444444444444444444"
+	mcoDigestZero  = "sha256:5555555555555555555555555555555555555555555555555555555555555555"
+	mcoRepo        = "https://github.com/openshift/machine-config-operator"
+	mcoPullspec    = "quay.io/openshift-release-dev/ocp-v4.0-art-dev@"
+)
+
+func newReleaseInfoWithMCO(tag, mcoDigest, commit string) *ReleaseInfo {
+	ri := newReleaseInfoWithComponents(tag, map[string]string{
+		"machine-config-operator": mcoPullspec + mcoDigest,
+	})
+
+	ri.References.Spec.Tags[0].Annotations = map[string]string{
+		sourceLocationAnnotation: mcoRepo,
+		commitIDAnnotation:       commit,
+	}
+
+	return ri
+}
+
+func newFakeReleaseControllerForComponentHistory() *fakeReleaseController {
+	f := newFakeReleaseController()
+
+	stream := "4.19.0-0.nightly"
+
+	// Tags are added newest first.
+	for _, tag := range []struct {
+		name   string
+		digest string
+		commit string
+	}{
+		{name: "4.19.0-0.nightly-2025-01-07-000000", digest: mcoDigestThree, commit: "bbbb"},
+		{name: "4.19.0-0.nightly-2025-01-06-000000", digest: mcoDigestThree, commit: "bbbb"},
+		// Rebuilt without a source change.
+		{name: "4.19.0-0.nightly-2025-01-05-000000", digest: mcoDigestTwo, commit: "aaaa"},
+		// Garbage-collected.
+		{name: "4.19.0-0.nightly-2025-01-04-000000"},
+		{name: "4.19.0-0.nightly-2025-01-03-000000", digest: mcoDigestOne, commit: "aaaa"},
+		{name: "4.19.0-0.nightly-2025-01-02-000000", digest: mcoDigestOne, commit: "aaaa"},
+		// Older than the tags which are walked.
+		{name: "4.19.0-0.nightly-2025-01-01-000000", digest: mcoDigestZero, commit: "0000"},
+	} {
+		var ri *ReleaseInfo
+		if tag.digest != "" {
+			ri = newReleaseInfoWithMCO(tag.name, tag.digest, tag.commit)
+		}
+
+		f.addTag(stream, Release{Name: tag.name, Phase: string(PhaseAccepted)}, ri)
+	}
+
+	return f
+}
+
+func TestComponentHistory(t *testing.T) {
+	rc := newFakeReleaseControllerForComponentHistory().start(t)
+
+	changes, err := rc.ReleaseStream("4.19.0-0.nightly").ComponentHistory(context.Background(), "machine-config-operator", ComponentHistoryOpts{Last: 6})
+	require.NoError(t, err)
+
+	newComponent := func(d, commit string) Component {
+		return Component{
+			Name:           "machine-config-operator",
+			SourceLocation: mcoRepo,
+			Repo:           "openshift/machine-config-operator",
+			Commit:         commit,
+			CommitURL:      mcoRepo + "/commit/" + commit,
+			Pullspec:       mcoPullspec + d,
+			Digest:         d,
+		}
+	}
+
+	assert.Equal(t, []ComponentChange{
+		{
+			Component:      newComponent(mcoDigestThree, "bbbb"),
+			Tag:            "4.19.0-0.nightly-2025-01-06-000000",
+			Phase:          string(PhaseAccepted),
+			Tags:           2,
+			PreviousTag:    "4.19.0-0.nightly-2025-01-05-000000",
+			PreviousDigest: mcoDigestTwo,
+			PreviousCommit: "aaaa",
+			DigestChanged:  true,
+			CommitChanged:  true,
+			CompareURL:     mcoRepo + "/compare/aaaa...bbbb",
+		},
+		{
+			Component:      newComponent(mcoDigestTwo, "aaaa"),
+			Tag:            "4.19.0-0.nightly-2025-01-05-000000",
+			Phase:          string(PhaseAccepted),
+			Tags:           1,
+			PreviousTag:    "4.19.0-0.nightly-2025-01-03-000000",
+			PreviousDigest: mcoDigestOne,
+			PreviousCommit: "aaaa",
+			DigestChanged:  true,
+		},
+		{
+			Component: newComponent(mcoDigestOne, "aaaa"),
+			Tag:       "4.19.0-0.nightly-2025-01-02-000000",
+			Phase:     string(PhaseAccepted),
+			Tags:      2,
+		},
+	}, changes)
+
+	_, err = rc.ReleaseStream("4.19.0-0.nightly").ComponentHistory(context.Background(), "unknown", ComponentHistoryOpts{})
+	assert.Error(t, err)
+
+	_, err = rc.ReleaseStream("unknown").ComponentHistory(context.Background(), "machine-config-operator", ComponentHistoryOpts{})
+	assert.Error(t, err)
+}
+
+func TestGetReleaseInfoWithCache(t *testing.T) {
+	f := newFakeReleaseControllerForComponentHistory()
+	rc := f.start(t)
+
+	cache := filecache.New(t.TempDir(), time.Hour)
+	tag := "4.19.0-0.nightly-2025-01-07-000000"
+
+	ri, err := rc.GetReleaseInfoWithCache(context.Background(), tag, cache)
+	require.NoError(t, err)
+
+	// Once cached, the release controller is no longer consulted.
+	delete(f.releaseInfos, tag)
+
+	cached, err := rc.GetReleaseInfoWithCache(context.Background(), tag, cache)
+	require.NoError(t, err)
+	assert.Equal(t, ri.Components(), cached.Components())
+
+	_, err = rc.GetReleaseInfoWithCache(context.Background(), tag, nil)
+	assert.Error(t, err)
+}
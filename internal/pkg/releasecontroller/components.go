@@ -0,0 +1,188 @@
+package releasecontroller
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	sourceLocationAnnotation string = "io.openshift.build.source-location"
+	commitIDAnnotation       string = "io.openshift.build.commit.id"
+)
+
+// Component describes a component image within a release payload along with
+// the source it was built from.
+type Component struct {
+	// Name is the name of the component, e.g., machine-config-operator.
+	Name string `json:"name"`
+	// SourceLocation is the URL of the source repository the component image
+	// was built from, e.g., https://github.com/openshift/machine-config-operator.
+	SourceLocation string `json:"sourceLocation,omitempty"`
+	// Repo is the source repository without its host, e.g.,
+	// openshift/machine-config-operator.
+	Repo string `json:"repo,omitempty"`
+	// Commit is the commit the component image was built from.
+	Commit string `json:"commit,omitempty"`
+	// CommitURL links to the commit, if the source is hosted on GitHub.
+	CommitURL string `json:"commitURL,omitempty"`
+	// Pullspec is the digested pullspec for the component image.
+	Pullspec string `json:"pullspec"`
+	// Digest is the digest of the component image.
+	Digest string `json:"digest,omitempty"`
+}
+
+// Components returns each of the component images within the release payload
+// along with the source repository and commit they were built from, as
+// recorded by the image-references annotations. The source is empty for
+// components which were not built from source (e.g., rhel-coreos).
+func (ri *ReleaseInfo) Components() []Component {
+	out := []Component{}
+
+	if ri.References == nil {
+		return out
+	}
+
+	for _, tag := range ri.References.Spec.Tags {
+		out = append(out, newComponent(tag))
+	}
+
+	return out
+}
+
+// ComponentsFromRepo returns the components which were built from the given
+// source repository. The repository may be given as a URL
+// (https://github.com/openshift/machine-config-operator), with its host
+// (github.com/openshift/machine-config-operator), or as just the org and repo
+// (openshift/machine-config-operator).
+func (ri *ReleaseInfo) ComponentsFromRepo(repo string) []Component {
+	out := []Component{}
+
+	want := normalizeRepo(repo)
+
+	for _, component := range ri.Components() {
+		if component.SourceLocation == "" {
+			continue
+		}
+
+		if normalizeRepo(component.SourceLocation) == want || strings.EqualFold(component.Repo, want) {
+			out = append(out, component)
+		}
+	}
+
+	return out
+}
+
+func newComponent(tag imagev1.TagReference) Component {
+	out := Component{
+		Name:           tag.Name,
+		SourceLocation: tag.Annotations[sourceLocationAnnotation],
+		Commit:         tag.Annotations[commitIDAnnotation],
+	}
+
+	if tag.From != nil {
+		out.Pullspec = tag.From.Name
+
+		if d, err := ParseImageDigest(tag.From.Name); err == nil {
+			out.Digest = d.String()
+		}
+	}
+
+	if out.SourceLocation == "" {
+		return out
+	}
+
+	u, err := url.Parse(out.SourceLocation)
+	if err != nil || u.Host == "" {
+		return out
+	}
+
+	out.Repo = strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+
+	if out.Commit != "" && strings.EqualFold(u.Host, "github.com") {
+		out.CommitURL = fmt.Sprintf("https://github.com/%s/commit/%s", out.Repo, out.Commit)
+	}
+
+	return out
+}
+
+// Strips the scheme, trailing slash and .git suffix from a repository so that
+// the different ways of referring to it compare equal.
+func normalizeRepo(repo string) string {
+	repo = strings.ToLower(repo)
+
+	if _, rest, ok := strings.Cut(repo, "://"); ok {
+		repo = rest
+	}
+
+	return strings.TrimSuffix(strings.Trim(repo, "/"), ".git")
+}
+
+// ComponentDiff describes how a component changed between two releases.
+type ComponentDiff struct {
+	Name string `json:"name"`
+	Repo string `json:"repo,omitempty"`
+	// FromDigest is empty when the component was added.
+	FromDigest string `json:"fromDigest,omitempty"`
+	// ToDigest is empty when the component was removed.
+	ToDigest   string `json:"toDigest,omitempty"`
+	FromCommit string `json:"fromCommit,omitempty"`
+	ToCommit   string `json:"toCommit,omitempty"`
+	// CompareURL links to the source changes between the two commits, if the
+	// source is hosted on GitHub.
+	CompareURL string `json:"compareURL,omitempty"`
+}
+
+// DiffComponents returns the components whose image digest or source commit
+// differs between the two releases, sorted by name.
+func DiffComponents(from, to *ReleaseInfo) []ComponentDiff {
+	fromByName := map[string]Component{}
+	for _, c := range from.Components() {
+		fromByName[c.Name] = c
+	}
+
+	toByName := map[string]Component{}
+	for _, c := range to.Components() {
+		toByName[c.Name] = c
+	}
+
+	names := sets.KeySet(fromByName).Union(sets.KeySet(toByName))
+
+	out := []ComponentDiff{}
+
+	for _, name := range sets.List(names) {
+		older, newer := fromByName[name], toByName[name]
+
+		if older.Digest == newer.Digest && older.Commit == newer.Commit && older.Pullspec == newer.Pullspec {
+			continue
+		}
+
+		diff := ComponentDiff{
+			Name:       name,
+			Repo:       newer.Repo,
+			FromDigest: older.Digest,
+			ToDigest:   newer.Digest,
+			FromCommit: older.Commit,
+			ToCommit:   newer.Commit,
+		}
+
+		if diff.Repo == "" {
+			diff.Repo = older.Repo
+		}
+
+		if older.Commit != "" && newer.Commit != "" && older.Commit != newer.Commit && newer.CommitURL != "" && older.Repo == newer.Repo {
+			diff.CompareURL = compareURL(newer.Repo, older.Commit, newer.Commit)
+		}
+
+		out = append(out, diff)
+	}
+
+	return out
+}
+
+func compareURL(repo, from, to string) string {
+	return fmt.Sprintf("https://github.com/%s/compare/%s...%s", repo, from, to)
+}
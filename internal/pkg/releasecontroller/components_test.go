@@ -0,0 +1,150 @@
+package releasecontroller
+
+import (
+	"testing"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const componentDigest string = "sha256:aa6cd007e204673ceafa266fe1cf359b386cbb1e34c785ae2dd1856e8f61b71c"
+
+func newReleaseInfoWithSources() *ReleaseInfo {
+	newTag := func(name, sourceLocation, commit string) imagev1.TagReference {
+		tag := imagev1.TagReference{
+			Name:        name,
+			Annotations: map[string]string{},
+			From: &corev1.ObjectReference{
+				Kind: "DockerImage",
+				Name: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + componentDigest,
+			},
+		}
+
+		if sourceLocation != "" {
+			tag.Annotations[sourceLocationAnnotation] = sourceLocation
+			tag.Annotations[commitIDAnnotation] = commit
+		}
+
+		return tag
+	}
+
+	return &ReleaseInfo{
+		References: &imagev1.ImageStream{
+			Spec: imagev1.ImageStreamSpec{
+				Tags: []imagev1.TagReference{
+					newTag("machine-config-operator", "https://github.com/openshift/machine-config-operator", "abc123"),
+					newTag("machine-os-images", "https://github.com/openshift/machine-config-operator.git", "abc123"),
+					newTag("cluster-version-operator", "https://github.com/openshift/cluster-version-operator", "def456"),
+					newTag("internal", "https://gitlab.example.com/org/internal", "0123"),
+					newTag("rhel-coreos", "", ""),
+				},
+			},
+		},
+	}
+}
+
+func TestComponents(t *testing.T) {
+	components := newReleaseInfoWithSources().Components()
+
+	assert.Len(t, components, 5)
+
+	assert.Equal(t, Component{
+		Name:           "machine-config-operator",
+		SourceLocation: "https://github.com/openshift/machine-config-operator",
+		Repo:           "openshift/machine-config-operator",
+		Commit:         "abc123",
+		CommitURL:      "https://github.com/openshift/machine-config-operator/commit/abc123",
+		Pullspec:       "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + componentDigest,
+		Digest:         componentDigest,
+	}, components[0])
+
+	// Only GitHub commit URLs are known.
+	assert.Equal(t, "org/internal", components[3].Repo)
+	assert.Empty(t, components[3].CommitURL)
+
+	assert.Equal(t, Component{
+		Name:     "rhel-coreos",
+		Pullspec: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + componentDigest,
+		Digest:   componentDigest,
+	}, components[4])
+
+	assert.Empty(t, (&ReleaseInfo{}).Components())
+}
+
+func TestComponentsFromRepo(t *testing.T) {
+	ri := newReleaseInfoWithSources()
+
+	testCases := []struct {
+		repo     string
+		expected []string
+	}{
+		{
+			repo:     "openshift/machine-config-operator",
+			expected: []string{"machine-config-operator", "machine-os-images"},
+		},
+		{
+			repo:     "github.com/openshift/machine-config-operator",
+			expected: []string{"machine-config-operator", "machine-os-images"},
+		},
+		{
+			repo:     "https://github.com/OpenShift/Machine-Config-Operator.git",
+			expected: []string{"machine-config-operator", "machine-os-images"},
+		},
+		{
+			repo:     "openshift/cluster-version-operator/",
+			expected: []string{"cluster-version-operator"},
+		},
+		{
+			repo:     "openshift/unknown",
+			expected: []string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.repo, func(t *testing.T) {
+			names := []string{}
+			for _, component := range ri.ComponentsFromRepo(testCase.repo) {
+				names = append(names, component.Name)
+			}
+
+			assert.Equal(t, testCase.expected, names)
+		})
+	}
+}
+
+func TestDiffComponents(t *testing.T) {
+	from := newReleaseInfoWithSources()
+	to := newReleaseInfoWithSources()
+
+	to.References.Spec.Tags[0].Annotations[commitIDAnnotation] = "bcd234"
+	to.References.Spec.Tags[2].From.Name = "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + mcoDigestOne
+	// Removes rhel-coreos.
+	to.References.Spec.Tags = to.References.Spec.Tags[:4]
+
+	assert.Equal(t, []ComponentDiff{
+		{
+			Name:       "cluster-version-operator",
+			Repo:       "openshift/cluster-version-operator",
+			FromDigest: componentDigest,
+			ToDigest:   mcoDigestOne,
+			FromCommit: "def456",
+			ToCommit:   "def456",
+		},
+		{
+			Name:       "machine-config-operator",
+			Repo:       "openshift/machine-config-operator",
+			FromDigest: componentDigest,
+			ToDigest:   componentDigest,
+			FromCommit: "abc123",
+			ToCommit:   "bcd234",
+			CompareURL: "https://github.com/openshift/machine-config-operator/compare/abc123...bcd234",
+		},
+		{
+			Name:       "rhel-coreos",
+			FromDigest: componentDigest,
+		},
+	}, DiffComponents(from, to))
+
+	assert.Empty(t, DiffComponents(from, from))
+}
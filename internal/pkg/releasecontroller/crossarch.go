@@ -0,0 +1,250 @@
+package releasecontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+)
+
+// ArchRelease describes a release tag on the release controller for a single
+// architecture.
+type ArchRelease struct {
+	Architecture string `json:"architecture"`
+	Controller   string `json:"controller"`
+	Stream       string `json:"stream"`
+	Tag          string `json:"tag"`
+	Phase        string `json:"phase"`
+	Pullspec     string `json:"pullspec"`
+	// Created is when the release payload was created, as reported by the
+	// release payload config.
+	Created            string    `json:"created,omitempty"`
+	BlockingJobs       JobCounts `json:"blockingJobs"`
+	FailedBlockingJobs []string  `json:"failedBlockingJobs,omitempty"`
+}
+
+// ComponentCommitMismatch is a component which was built from different source
+// commits for different architectures.
+type ComponentCommitMismatch struct {
+	Name string `json:"name"`
+	Repo string `json:"repo,omitempty"`
+	// Commits maps each architecture to the commit its image was built from.
+	Commits map[string]string `json:"commits"`
+}
+
+// CrossArchComparison describes the same release version across the release
+// controllers for each architecture.
+type CrossArchComparison struct {
+	Version  string        `json:"version"`
+	Releases []ArchRelease `json:"releases"`
+	// MismatchedComponents are the components whose source commit differs
+	// between architectures, which signals a broken multi-arch build.
+	MismatchedComponents []ComponentCommitMismatch `json:"mismatchedComponents"`
+}
+
+// CompareAcrossArchitectures looks up the given release version on each of
+// the given release controllers (all of them if none are given) and compares
+// them. Release controllers which do not have the version are omitted, as are
+// release controllers from which the release cannot be retrieved, with a
+// warning. Only versions named the same on every release controller (e.g.,
+// 4.19.3 or 4.19.0-rc.1) can be compared; nightly and CI builds are named and
+// built separately for each architecture.
+func CompareAcrossArchitectures(ctx context.Context, version string, controllers []*ReleaseController) (*CrossArchComparison, error) {
+	if releaseTagTimestampRegex.MatchString(version) {
+		return nil, fmt.Errorf("%q is a nightly or CI build, which are built separately for each architecture; only release versions such as 4.19.3 or 4.19.0-rc.1 can be compared across architectures", version)
+	}
+
+	if len(controllers) == 0 {
+		controllers = All()
+	}
+
+	mu := &sync.Mutex{}
+	releases := []ArchRelease{}
+	componentsByArch := map[string][]Component{}
+	errs := make([]error, len(controllers))
+
+	g := &errgroup.Group{}
+
+	for i, rc := range controllers {
+		g.Go(func() error {
+			release, components, err := getArchRelease(ctx, rc, version)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", rc, err)
+				return nil
+			}
+
+			if release == nil {
+				return nil
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			releases = append(releases, *release)
+			componentsByArch[release.Architecture] = components
+
+			return nil
+		})
+	}
+
+	// Errors are recorded per release controller so that one which cannot be
+	// reached does not fail the whole comparison.
+	_ = g.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			klog.Warningf("Skipping %s", err)
+		}
+	}
+
+	if len(releases) == 0 {
+		if err := errors.Join(errs...); err != nil {
+			return nil, fmt.Errorf("release %q not found on any release controller: %w", version, err)
+		}
+
+		return nil, fmt.Errorf("release %q not found on any release controller", version)
+	}
+
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].Controller < releases[j].Controller
+	})
+
+	return &CrossArchComparison{
+		Version:              version,
+		Releases:             releases,
+		MismatchedComponents: findCommitMismatches(componentsByArch),
+	}, nil
+}
+
+// Returns nil if the release controller does not have the given version.
+func getArchRelease(ctx context.Context, rc *ReleaseController, version string) (*ArchRelease, []Component, error) {
+	streams, err := rc.ReleaseStreams().All(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stream := ""
+	for name, tags := range streams {
+		if sets.New(tags...).Has(version) {
+			stream = name
+			break
+		}
+	}
+
+	if stream == "" {
+		return nil, nil, nil
+	}
+
+	rs := rc.ReleaseStream(stream)
+
+	tag, err := rs.findTag(ctx, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := rs.Tag(ctx, version)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get verification results for %q: %w", version, err)
+	}
+
+	ri, err := rc.GetReleaseInfo(ctx, version)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get release info for %q: %w", version, err)
+	}
+
+	out := &ArchRelease{
+		Architecture: architectureForHost(rc.Host()),
+		Controller:   rc.Host(),
+		Stream:       stream,
+		Tag:          tag.Name,
+		Phase:        tag.Phase,
+		Pullspec:     tag.Pullspec,
+		Created:      ri.Config.Created,
+	}
+
+	if info.Results != nil {
+		out.BlockingJobs = info.Results.BlockingJobs.Counts()
+
+		for job, status := range info.Results.BlockingJobs {
+			if status.State == "Failed" {
+				out.FailedBlockingJobs = append(out.FailedBlockingJobs, job)
+			}
+		}
+
+		sort.Strings(out.FailedBlockingJobs)
+	}
+
+	return out, ri.Components(), nil
+}
+
+// Finds the components which have more than one distinct source commit across
+// architectures. Components without a commit (e.g., rhel-coreos) are ignored.
+func findCommitMismatches(componentsByArch map[string][]Component) []ComponentCommitMismatch {
+	byName := map[string]*ComponentCommitMismatch{}
+
+	for arch, components := range componentsByArch {
+		for _, component := range components {
+			if component.Commit == "" {
+				continue
+			}
+
+			if _, ok := byName[component.Name]; !ok {
+				byName[component.Name] = &ComponentCommitMismatch{
+					Name:    component.Name,
+					Repo:    component.Repo,
+					Commits: map[string]string{},
+				}
+			}
+
+			byName[component.Name].Commits[arch] = component.Commit
+		}
+	}
+
+	out := []ComponentCommitMismatch{}
+
+	for _, mismatch := range byName {
+		commits := sets.New[string]()
+		for _, commit := range mismatch.Commits {
+			commits.Insert(commit)
+		}
+
+		if commits.Len() > 1 {
+			out = append(out, *mismatch)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+
+	return out
+}
+
+// Release controller hosts start with their architecture, e.g.,
+// arm64.ocp.releases.ci.openshift.org. OKD release controllers are suffixed so
+// that they are distinguishable from the OCP ones.
+func architectureForHost(host string) string {
+	arch, rest, _ := strings.Cut(host, ".")
+
+	switch arch {
+	case "amd64", "arm64", "ppc64le", "s390x", "multi":
+	default:
+		return host
+	}
+
+	if strings.HasPrefix(rest, "origin.") {
+		return arch + "-okd"
+	}
+
+	return arch
+}
@@ -0,0 +1,116 @@
+package releasecontroller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFakeReleaseControllerForCrossArch(stream, mcoCommit string, blockingJobs VerificationStatusMap) *fakeReleaseController {
+	f := newFakeReleaseController()
+
+	ri := newReleaseInfoWithMCO("4.19.3", mcoDigestOne, mcoCommit)
+	ri.Config.Created = "2025-01-02T03:04:05Z"
+
+	f.addTag(stream, Release{Name: "4.19.3", Phase: string(PhaseAccepted), Pullspec: "quay.io/openshift-release-dev/ocp-release:4.19.3-" + stream}, ri)
+	f.apiReleaseInfos["4.19.3"] = &APIReleaseInfo{
+		Name:    "4.19.3",
+		Phase:   string(PhaseAccepted),
+		Results: &VerificationJobsSummary{BlockingJobs: blockingJobs},
+	}
+
+	return f
+}
+
+func TestCompareAcrossArchitectures(t *testing.T) {
+	amd64 := newFakeReleaseControllerForCrossArch("4-stable", "aaaa", VerificationStatusMap{
+		"install": {State: "Succeeded"},
+		"upgrade": {State: "Succeeded"},
+	}).start(t)
+
+	arm64 := newFakeReleaseControllerForCrossArch("4-stable-arm64", "bbbb", VerificationStatusMap{
+		"install": {State: "Succeeded"},
+		"upgrade": {State: "Failed"},
+	}).start(t)
+
+	// A release controller without the version is omitted.
+	empty := newFakeReleaseController().start(t)
+
+	// As is a release controller which has the version but cannot provide its
+	// release info.
+	broken := newFakeReleaseController()
+	broken.addTag("4-stable-s390x", Release{Name: "4.19.3", Phase: string(PhaseAccepted)}, nil)
+
+	result, err := CompareAcrossArchitectures(context.Background(), "4.19.3", []*ReleaseController{amd64, arm64, empty, broken.start(t)})
+	require.NoError(t, err)
+
+	require.Len(t, result.Releases, 2)
+
+	byController := map[string]ArchRelease{}
+	for _, release := range result.Releases {
+		byController[release.Controller] = release
+	}
+
+	assert.Equal(t, ArchRelease{
+		Architecture:       arm64.Host(),
+		Controller:         arm64.Host(),
+		Stream:             "4-stable-arm64",
+		Tag:                "4.19.3",
+		Phase:              string(PhaseAccepted),
+		Pullspec:           "quay.io/openshift-release-dev/ocp-release:4.19.3-4-stable-arm64",
+		Created:            "2025-01-02T03:04:05Z",
+		BlockingJobs:       JobCounts{Succeeded: 1, Failed: 1},
+		FailedBlockingJobs: []string{"upgrade"},
+	}, byController[arm64.Host()])
+
+	assert.Equal(t, JobCounts{Succeeded: 2}, byController[amd64.Host()].BlockingJobs)
+
+	assert.Equal(t, []ComponentCommitMismatch{
+		{
+			Name: "machine-config-operator",
+			Repo: "openshift/machine-config-operator",
+			Commits: map[string]string{
+				amd64.Host(): "aaaa",
+				arm64.Host(): "bbbb",
+			},
+		},
+	}, result.MismatchedComponents)
+
+	_, err = CompareAcrossArchitectures(context.Background(), "4.99.0", []*ReleaseController{amd64, arm64})
+	assert.Error(t, err)
+
+	_, err = CompareAcrossArchitectures(context.Background(), "4.19.3", []*ReleaseController{broken.start(t)})
+	assert.Error(t, err)
+}
+
+func TestCompareAcrossArchitecturesNightly(t *testing.T) {
+	amd64 := newFakeReleaseControllerForCrossArch("4-stable", "aaaa", nil).start(t)
+
+	_, err := CompareAcrossArchitectures(context.Background(), "4.19.0-0.nightly-2025-01-01-123456", []*ReleaseController{amd64})
+	assert.ErrorContains(t, err, "built separately for each architecture")
+}
+
+func TestCompareAcrossArchitecturesMatchingCommits(t *testing.T) {
+	amd64 := newFakeReleaseControllerForCrossArch("4-stable", "aaaa", nil).start(t)
+	arm64 := newFakeReleaseControllerForCrossArch("4-stable-arm64", "aaaa", nil).start(t)
+
+	result, err := CompareAcrossArchitectures(context.Background(), "4.19.3", []*ReleaseController{amd64, arm64})
+	require.NoError(t, err)
+	assert.Empty(t, result.MismatchedComponents)
+}
+
+func TestArchitectureForHost(t *testing.T) {
+	testCases := map[string]string{
+		Amd64OcpReleaseController: "amd64",
+		Arm64OcpReleaseController: "arm64",
+		MultiOcpReleaseController: "multi",
+		Amd64OkdReleaseController: "amd64-okd",
+		"127.0.0.1:8443":          "127.0.0.1:8443",
+	}
+
+	for host, expected := range testCases {
+		assert.Equal(t, expected, architectureForHost(host), host)
+	}
+}
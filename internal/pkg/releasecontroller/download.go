@@ -0,0 +1,585 @@
+package releasecontroller
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/containers"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/ghodss/yaml"
+	"github.com/opencontainers/go-digest"
+	imagev1 "github.com/openshift/api/image/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+)
+
+// Tool is a client tool published alongside a release.
+type Tool string
+
+const (
+	OcTool        Tool = "oc"
+	InstallerTool Tool = "openshift-install"
+	CcoctlTool    Tool = "ccoctl"
+)
+
+// Where the release payload image lists its component images.
+const imageReferencesPath string = "release-manifests/image-references"
+
+const (
+	// ToolSourceDownloadServer means that the tool was downloaded from the
+	// release controller's client download page.
+	ToolSourceDownloadServer string = "download-server"
+	// ToolSourcePayload means that the tool was extracted from a component
+	// image of the release payload.
+	ToolSourcePayload string = "payload"
+)
+
+// The archive name prefix, payload component image, and path of the binary
+// within that image for each tool.
+var toolSources = map[Tool]struct {
+	archivePrefix string
+	component     string
+	binaryPath    string
+}{
+	OcTool:        {archivePrefix: "openshift-client", component: "cli", binaryPath: "usr/bin/oc"},
+	InstallerTool: {archivePrefix: "openshift-install", component: "installer", binaryPath: "usr/bin/openshift-install"},
+	CcoctlTool:    {archivePrefix: "ccoctl", component: "cloud-credential-operator", binaryPath: "usr/bin/ccoctl"},
+}
+
+// Tools returns the names of the client tools that can be downloaded.
+func Tools() []string {
+	out := []string{}
+	for tool := range toolSources {
+		out = append(out, string(tool))
+	}
+
+	return sets.List(sets.New(out...))
+}
+
+// DownloadOpts holds the options for downloading the client tools for a
+// release.
+type DownloadOpts struct {
+	Tools []Tool
+	// OS is the operating system to download the tools for; one of linux, mac
+	// (or darwin), or windows.
+	OS string
+	// Arch is the architecture to download the tools for, e.g., arm64.
+	Arch string
+	// Dir is the directory the tools are unpacked into.
+	Dir string
+	// FromPayload extracts the tools from the release payload images instead
+	// of downloading them from the release controller's download page.
+	FromPayload bool
+	// AuthfilePath is the path to a registry auth file used when extracting the
+	// tools from the release payload images.
+	AuthfilePath string
+}
+
+// DownloadedTool describes where a client tool came from and where it was
+// unpacked to.
+type DownloadedTool struct {
+	Tool Tool `json:"tool"`
+	// Source is either download-server or payload.
+	Source string `json:"source"`
+	// From is the URL of the archive or the pullspec of the image that the tool
+	// was extracted from.
+	From string `json:"from"`
+	// SHA256 is the verified checksum of the archive, or the checksum of the
+	// binary when extracted from the release payload.
+	SHA256 string `json:"sha256"`
+	// Files are the paths of the unpacked files.
+	Files []string `json:"files"`
+}
+
+// ToolsDownload describes the client tools downloaded for a release.
+type ToolsDownload struct {
+	Release string           `json:"release"`
+	OS      string           `json:"os"`
+	Arch    string           `json:"arch"`
+	Tools   []DownloadedTool `json:"tools"`
+}
+
+type toolDownloader struct {
+	rc                *ReleaseController
+	opts              DownloadOpts
+	client            *http.Client
+	getImagePlatforms func(ctx context.Context, ref, pullSecretPath string) (*containers.ImagePlatforms, error)
+	readImageFiles    func(ctx context.Context, ref, pullSecretPath string, include func(string) bool) (map[string][]byte, error)
+}
+
+// DownloadTools downloads the given client tools for the given release tag
+// from the release controller's client download page, verifies them against
+// the published sha256sum.txt, and unpacks them. When the download page is
+// unavailable or does not have an archive for a tool, the tool is extracted
+// from the release payload images instead, which is only possible for linux
+// and the architecture of the payload. A release payload pullspec may be given
+// instead of a release tag, in which case the tools are always extracted from
+// the release payload images without talking to the release controller.
+func (r *ReleaseController) DownloadTools(ctx context.Context, tagOrPullspec string, opts DownloadOpts) (*ToolsDownload, error) {
+	return newToolDownloader(r, opts).download(ctx, tagOrPullspec)
+}
+
+func newToolDownloader(rc *ReleaseController, opts DownloadOpts) *toolDownloader {
+	if opts.OS == "darwin" {
+		opts.OS = "mac"
+	}
+
+	return &toolDownloader{
+		rc:   rc,
+		opts: opts,
+		// Archives can take longer to download than the release controller
+		// client timeout allows, so only the context bounds them.
+		client:            &http.Client{Transport: rc.client.Transport},
+		getImagePlatforms: containers.GetImagePlatforms,
+		readImageFiles:    containers.ReadImageFiles,
+	}
+}
+
+func (t *toolDownloader) download(ctx context.Context, tagOrPullspec string) (*ToolsDownload, error) {
+	for _, tool := range t.opts.Tools {
+		if _, ok := toolSources[tool]; !ok {
+			return nil, fmt.Errorf("unknown tool %q, valid tool(s): %v", tool, Tools())
+		}
+	}
+
+	if err := os.MkdirAll(t.opts.Dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	tag := tagOrPullspec
+	payload := ""
+
+	if vk, err := GetVersionKind(tagOrPullspec); err == nil && vk == PullspecVersionKind {
+		tag = ""
+		payload = tagOrPullspec
+	}
+
+	checksums := map[string]string{}
+	downloadURL := ""
+
+	if tag != "" {
+		release, err := t.findRelease(ctx, tag)
+		if err != nil {
+			return nil, fmt.Errorf("could not find release %q, its release payload pullspec may be given instead: %w", tag, err)
+		}
+
+		payload = release.Pullspec
+
+		if !t.opts.FromPayload {
+			downloadURL, checksums, err = t.getChecksums(ctx, release)
+			if err != nil {
+				klog.Warningf("Could not get the published checksums for %q, will extract the tools from the release payload instead: %s", tag, err)
+			}
+		}
+	}
+
+	out := &ToolsDownload{
+		Release: tagOrPullspec,
+		OS:      t.opts.OS,
+		Arch:    t.opts.Arch,
+		Tools:   []DownloadedTool{},
+	}
+
+	var ri *ReleaseInfo
+
+	for _, tool := range t.opts.Tools {
+		archive := t.findArchive(tool, tag, checksums)
+		if archive != "" {
+			downloaded, err := t.downloadArchive(ctx, tool, downloadURL+"/"+archive, checksums[archive])
+			if err != nil {
+				return nil, fmt.Errorf("could not download %s: %w", tool, err)
+			}
+
+			out.Tools = append(out.Tools, *downloaded)
+			continue
+		}
+
+		if len(checksums) != 0 {
+			klog.Warningf("No %s archive for %s/%s is published for %q, will extract it from the release payload instead", tool, t.opts.OS, t.opts.Arch, tag)
+		}
+
+		if t.opts.OS != "linux" {
+			return nil, fmt.Errorf("could not extract %s from the release payload: only linux binaries can be extracted, not %s", tool, t.opts.OS)
+		}
+
+		if ri == nil {
+			var err error
+			ri, err = t.readImageReferences(ctx, payload)
+			if err != nil {
+				return nil, fmt.Errorf("could not read the component images of release payload %s: %w", payload, err)
+			}
+		}
+
+		extracted, err := t.extractFromPayload(ctx, tool, ri)
+		if err != nil {
+			return nil, fmt.Errorf("could not extract %s from the release payload: %w", tool, err)
+		}
+
+		out.Tools = append(out.Tools, *extracted)
+	}
+
+	return out, nil
+}
+
+// Finds the given release tag on the release controller.
+func (t *toolDownloader) findRelease(ctx context.Context, tag string) (*Release, error) {
+	stream, _, err := t.rc.ReleaseStreams().FindReleaseNameAndStream(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.rc.ReleaseStream(stream).findTag(ctx, tag)
+}
+
+// Gets the download URL for the given release tag and the checksums published
+// there, keyed by the archive name.
+func (t *toolDownloader) getChecksums(ctx context.Context, release *Release) (string, map[string]string, error) {
+	if release.DownloadURL == "" {
+		return "", nil, fmt.Errorf("release controller %s has no download URL for %q", t.rc, release.Name)
+	}
+
+	downloadURL := strings.TrimSuffix(release.DownloadURL, "/")
+
+	resp, err := t.get(ctx, downloadURL+"/sha256sum.txt")
+	if err != nil {
+		return "", nil, err
+	}
+
+	defer resp.Body.Close()
+
+	checksums, err := parseChecksums(resp.Body)
+	if err != nil {
+		return "", nil, fmt.Errorf("could not parse sha256sum.txt: %w", err)
+	}
+
+	return downloadURL, checksums, nil
+}
+
+func (t *toolDownloader) get(ctx context.Context, u string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("got HTTP %d from %s", resp.StatusCode, u)
+	}
+
+	return resp, nil
+}
+
+// Parses the output of sha256sum into a map of file name to checksum.
+func parseChecksums(r io.Reader) (map[string]string, error) {
+	out := map[string]string{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("malformed line %q", scanner.Text())
+		}
+
+		// sha256sum prefixes the file name with an asterisk in binary mode.
+		out[strings.TrimPrefix(fields[1], "*")] = fields[0]
+	}
+
+	return out, scanner.Err()
+}
+
+// Finds the archive for the given tool among the published archives, e.g.,
+// openshift-client-linux-arm64-4.18.3.tar.gz. The amd64 archives are
+// sometimes published without an architecture, so that name is also
+// considered for amd64. Returns an empty string if there is no such archive.
+func (t *toolDownloader) findArchive(tool Tool, tag string, checksums map[string]string) string {
+	ext := "tar.gz"
+	if t.opts.OS == "windows" {
+		ext = "zip"
+	}
+
+	prefix := toolSources[tool].archivePrefix
+
+	candidates := []string{fmt.Sprintf("%s-%s-%s-%s.%s", prefix, t.opts.OS, t.opts.Arch, tag, ext)}
+	if t.opts.Arch == "amd64" {
+		candidates = append(candidates, fmt.Sprintf("%s-%s-%s.%s", prefix, t.opts.OS, tag, ext))
+	}
+
+	for _, candidate := range candidates {
+		if _, ok := checksums[candidate]; ok {
+			return candidate
+		}
+	}
+
+	return ""
+}
+
+// Downloads the archive at the given URL, verifies it against the expected
+// checksum, and unpacks it. The archive itself is removed afterward.
+func (t *toolDownloader) downloadArchive(ctx context.Context, tool Tool, u, expectedSHA256 string) (*DownloadedTool, error) {
+	klog.Infof("Downloading %s", u)
+
+	resp, err := t.get(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	archive, err := os.CreateTemp(t.opts.Dir, ".rcctl-download-*")
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	h := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(archive, h), resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not download %s: %w", u, err)
+	}
+
+	actual := hex.EncodeToString(h.Sum(nil))
+	if actual != expectedSHA256 {
+		return nil, fmt.Errorf("checksum mismatch for %s: expected %s, got %s", u, expectedSHA256, actual)
+	}
+
+	var files []string
+	if strings.HasSuffix(u, ".zip") {
+		files, err = unpackZip(archive, size, t.opts.Dir)
+	} else {
+		if _, err := archive.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		files, err = unpackTarGz(archive, t.opts.Dir)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not unpack %s: %w", u, err)
+	}
+
+	return &DownloadedTool{
+		Tool:   tool,
+		Source: ToolSourceDownloadServer,
+		From:   u,
+		SHA256: actual,
+		Files:  files,
+	}, nil
+}
+
+// Reads the component images from the image-references file in the release
+// payload image itself so that neither the release controller nor oc is
+// needed.
+func (t *toolDownloader) readImageReferences(ctx context.Context, payload string) (*ReleaseInfo, error) {
+	files, err := t.readImageFiles(ctx, payload, t.opts.AuthfilePath, func(name string) bool {
+		return name == imageReferencesPath
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	contents, ok := files[imageReferencesPath]
+	if !ok {
+		return nil, fmt.Errorf("image %s has no /%s", payload, imageReferencesPath)
+	}
+
+	references := &imagev1.ImageStream{}
+	if err := yaml.Unmarshal(contents, references); err != nil {
+		return nil, fmt.Errorf("could not parse /%s: %w", imageReferencesPath, err)
+	}
+
+	return &ReleaseInfo{ReleasePullspec: payload, References: references}, nil
+}
+
+// Extracts the binary for the given tool from its component image in the
+// release payload. For multi-arch payloads, the image for the requested
+// architecture is used. Otherwise, the payload must be for the requested
+// architecture.
+func (t *toolDownloader) extractFromPayload(ctx context.Context, tool Tool, ri *ReleaseInfo) (*DownloadedTool, error) {
+	source := toolSources[tool]
+
+	tagRef := ri.GetTagRefForComponentName(source.component)
+	if tagRef == nil || tagRef.From == nil {
+		return nil, fmt.Errorf("release %q has no %s component", ri.name(), source.component)
+	}
+
+	pullspec, err := t.getPullspecForArch(ctx, tagRef.From.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	klog.Infof("Extracting /%s from %s", source.binaryPath, pullspec)
+
+	files, err := t.readImageFiles(ctx, pullspec, t.opts.AuthfilePath, func(name string) bool {
+		return name == source.binaryPath
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	contents, ok := files[source.binaryPath]
+	if !ok {
+		return nil, fmt.Errorf("image %s has no /%s", pullspec, source.binaryPath)
+	}
+
+	dest := filepath.Join(t.opts.Dir, path.Base(source.binaryPath))
+	if err := os.WriteFile(dest, contents, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &DownloadedTool{
+		Tool:   tool,
+		Source: ToolSourcePayload,
+		From:   pullspec,
+		SHA256: digest.SHA256.FromBytes(contents).Encoded(),
+		Files:  []string{dest},
+	}, nil
+}
+
+func (t *toolDownloader) getPullspecForArch(ctx context.Context, pullspec string) (string, error) {
+	platforms, err := t.getImagePlatforms(ctx, pullspec, t.opts.AuthfilePath)
+	if err != nil {
+		return "", err
+	}
+
+	for _, platform := range platforms.Platforms {
+		if platform.OS != "linux" || platform.Architecture != t.opts.Arch {
+			continue
+		}
+
+		if !platforms.IsManifestList {
+			return pullspec, nil
+		}
+
+		named, err := reference.ParseNormalizedNamed(pullspec)
+		if err != nil {
+			return "", err
+		}
+
+		digested, err := reference.WithDigest(reference.TrimNamed(named), platform.Digest)
+		if err != nil {
+			return "", err
+		}
+
+		return digested.String(), nil
+	}
+
+	return "", fmt.Errorf("image %s is not available for %s, only for: %v", pullspec, t.opts.Arch, platforms.Names())
+}
+
+// Unpacks the regular files from a gzipped tarball into the given directory.
+// Client tool archives are flat, so any directories within them are ignored.
+func unpackTarGz(r io.Reader, dir string) ([]string, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer gz.Close()
+
+	files := []string{}
+
+	tr := tar.NewReader(gz)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		dest, err := writeUnpackedFile(dir, hdr.Name, hdr.FileInfo().Mode().Perm(), tr)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, dest)
+	}
+}
+
+// Unpacks the regular files from a zip archive into the given directory.
+func unpackZip(r io.ReaderAt, size int64, dir string) ([]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+
+	for _, f := range zr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		dest, err := writeUnpackedFile(dir, f.Name, f.Mode().Perm(), rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, dest)
+	}
+
+	return files, nil
+}
+
+func writeUnpackedFile(dir, name string, perm os.FileMode, r io.Reader) (string, error) {
+	// Only the base name is used so that archive entries cannot be written
+	// outside of the given directory. Archive entries always use forward
+	// slashes, but a backslash would be a separator on Windows, so any name
+	// which still has one is rejected.
+	base := filepath.Base(filepath.FromSlash(name))
+	if base == "." || base == ".." || strings.ContainsAny(base, `/\`) {
+		return "", fmt.Errorf("invalid archive entry %q", name)
+	}
+
+	dest := filepath.Join(dir, base)
+
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return "", err
+	}
+
+	return dest, f.Close()
+}
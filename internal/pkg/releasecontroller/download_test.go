@@ -0,0 +1,408 @@
+package releasecontroller
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/containers"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	cliPullspec       string = "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	installerPullspec string = "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	payloadPullspec   string = "quay.io/openshift-release-dev/ocp-release:4.19.3-x86_64"
+)
+
+func newTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	buf := bytes.NewBuffer(nil)
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+
+	for name, contents := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(contents)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	return buf.Bytes()
+}
+
+func newZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	buf := bytes.NewBuffer(nil)
+	zw := zip.NewWriter(buf)
+
+	for name, contents := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// Starts a fake release controller with the 4.19.3 tag whose download page
+// serves the given archives along with a sha256sum.txt listing them. The
+// checksums override the listed checksum for an archive.
+func startFakeDownloadServer(t *testing.T, archives map[string][]byte, checksums map[string]string) *ReleaseController {
+	t.Helper()
+
+	f := newFakeReleaseController()
+
+	sums := []string{}
+	for name, contents := range archives {
+		f.files["files/4.19.3/"+name] = contents
+
+		sum, ok := checksums[name]
+		if !ok {
+			sum = sha256Hex(contents)
+		}
+
+		sums = append(sums, fmt.Sprintf("%s  %s", sum, name))
+	}
+
+	f.files["files/4.19.3/sha256sum.txt"] = []byte(strings.Join(sums, "\n") + "\n")
+
+	rc := f.start(t)
+
+	f.addTag("4-stable", Release{
+		Name:        "4.19.3",
+		Phase:       string(PhaseAccepted),
+		Pullspec:    payloadPullspec,
+		DownloadURL: fmt.Sprintf("https://%s/files/4.19.3/", rc.Host()),
+	}, nil)
+
+	return rc
+}
+
+func readDir(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	out := map[string]string{}
+	for _, entry := range entries {
+		b, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		require.NoError(t, err)
+		out[entry.Name()] = string(b)
+	}
+
+	return out
+}
+
+func TestDownloadTools(t *testing.T) {
+	ocLinuxArm64 := newTarGz(t, map[string]string{"oc": "oc-linux-arm64", "kubectl": "kubectl-linux-arm64", "README.md": "readme"})
+	ocLinuxAmd64 := newTarGz(t, map[string]string{"oc": "oc-linux-amd64"})
+	ocWindows := newZip(t, map[string]string{"oc.exe": "oc-windows", "README.md": "readme"})
+	installerMacArm64 := newTarGz(t, map[string]string{"openshift-install": "installer-mac-arm64"})
+
+	archives := map[string][]byte{
+		"openshift-client-linux-arm64-4.19.3.tar.gz": ocLinuxArm64,
+		"openshift-client-linux-4.19.3.tar.gz":       ocLinuxAmd64,
+		"openshift-client-windows-4.19.3.zip":        ocWindows,
+		"openshift-install-mac-arm64-4.19.3.tar.gz":  installerMacArm64,
+		"ccoctl-linux-arm64-4.19.3.tar.gz":           newTarGz(t, map[string]string{"ccoctl": "tampered"}),
+	}
+
+	rc := startFakeDownloadServer(t, archives, map[string]string{
+		"ccoctl-linux-arm64-4.19.3.tar.gz": sha256Hex([]byte("something else")),
+	})
+
+	testCases := []struct {
+		name          string
+		opts          DownloadOpts
+		expectedFiles map[string]string
+		expectedFrom  string
+		expectedSHA   string
+		errExpected   bool
+	}{
+		{
+			name:          "Linux arm64",
+			opts:          DownloadOpts{Tools: []Tool{OcTool}, OS: "linux", Arch: "arm64"},
+			expectedFiles: map[string]string{"oc": "oc-linux-arm64", "kubectl": "kubectl-linux-arm64", "README.md": "readme"},
+			expectedFrom:  "openshift-client-linux-arm64-4.19.3.tar.gz",
+			expectedSHA:   sha256Hex(ocLinuxArm64),
+		},
+		{
+			name:          "Linux amd64 without an architecture in the archive name",
+			opts:          DownloadOpts{Tools: []Tool{OcTool}, OS: "linux", Arch: "amd64"},
+			expectedFiles: map[string]string{"oc": "oc-linux-amd64"},
+			expectedFrom:  "openshift-client-linux-4.19.3.tar.gz",
+			expectedSHA:   sha256Hex(ocLinuxAmd64),
+		},
+		{
+			name:          "Windows zip",
+			opts:          DownloadOpts{Tools: []Tool{OcTool}, OS: "windows", Arch: "amd64"},
+			expectedFiles: map[string]string{"oc.exe": "oc-windows", "README.md": "readme"},
+			expectedFrom:  "openshift-client-windows-4.19.3.zip",
+			expectedSHA:   sha256Hex(ocWindows),
+		},
+		{
+			name:          "Darwin is an alias for mac",
+			opts:          DownloadOpts{Tools: []Tool{InstallerTool}, OS: "darwin", Arch: "arm64"},
+			expectedFiles: map[string]string{"openshift-install": "installer-mac-arm64"},
+			expectedFrom:  "openshift-install-mac-arm64-4.19.3.tar.gz",
+			expectedSHA:   sha256Hex(installerMacArm64),
+		},
+		{
+			name:        "Checksum mismatch",
+			opts:        DownloadOpts{Tools: []Tool{CcoctlTool}, OS: "linux", Arch: "arm64"},
+			errExpected: true,
+		},
+		{
+			name:        "Unknown tool",
+			opts:        DownloadOpts{Tools: []Tool{"kubectl"}, OS: "linux", Arch: "arm64"},
+			errExpected: true,
+		},
+		{
+			name:        "Non-linux tools cannot be extracted from the payload",
+			opts:        DownloadOpts{Tools: []Tool{CcoctlTool}, OS: "mac", Arch: "arm64"},
+			errExpected: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.opts.Dir = t.TempDir()
+
+			result, err := rc.DownloadTools(context.Background(), "4.19.3", testCase.opts)
+			if testCase.errExpected {
+				assert.Error(t, err)
+				assert.Empty(t, readDir(t, testCase.opts.Dir))
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, result.Tools, 1)
+
+			assert.Equal(t, ToolSourceDownloadServer, result.Tools[0].Source)
+			assert.Equal(t, fmt.Sprintf("https://%s/files/4.19.3/%s", rc.Host(), testCase.expectedFrom), result.Tools[0].From)
+			assert.Equal(t, testCase.expectedSHA, result.Tools[0].SHA256)
+			assert.Len(t, result.Tools[0].Files, len(testCase.expectedFiles))
+			assert.Equal(t, testCase.expectedFiles, readDir(t, testCase.opts.Dir))
+		})
+	}
+}
+
+func TestDownloadToolsFromPayload(t *testing.T) {
+	rc := startFakeDownloadServer(t, map[string][]byte{
+		"openshift-client-linux-arm64-4.19.3.tar.gz": newTarGz(t, map[string]string{"oc": "oc-from-download-server"}),
+	}, nil)
+
+	arm64Digest := digest.Digest("sha256:3333333333333333333333333333333333333333333333333333333333333333")
+
+	// The cli image is a single arm64 image whereas the installer image is a
+	// manifest list.
+	getImagePlatforms := func(_ context.Context, ref, _ string) (*containers.ImagePlatforms, error) {
+		if ref == cliPullspec {
+			return &containers.ImagePlatforms{Platforms: []containers.Platform{{OS: "linux", Architecture: "arm64"}}}, nil
+		}
+
+		return &containers.ImagePlatforms{
+			IsManifestList: true,
+			Platforms: []containers.Platform{
+				{OS: "linux", Architecture: "amd64", Digest: "sha256:4444444444444444444444444444444444444444444444444444444444444444"},
+				{OS: "linux", Architecture: "arm64", Digest: arm64Digest},
+			},
+		}, nil
+	}
+
+	expectedInstallerPullspec := "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + arm64Digest.String()
+
+	imageReferences, err := json.Marshal(newReleaseInfoWithComponents("4.19.3", map[string]string{
+		"cli":       cliPullspec,
+		"installer": installerPullspec,
+	}).References)
+	require.NoError(t, err)
+
+	readImageFiles := func(_ context.Context, ref, _ string, include func(string) bool) (map[string][]byte, error) {
+		images := map[string]map[string][]byte{
+			payloadPullspec:           {imageReferencesPath: imageReferences},
+			cliPullspec:               {"usr/bin/oc": []byte("oc-from-payload"), "usr/bin/kubectl": []byte("kubectl")},
+			expectedInstallerPullspec: {"usr/bin/openshift-install": []byte("installer-from-payload")},
+		}
+
+		files, ok := images[ref]
+		if !ok {
+			return nil, fmt.Errorf("unexpected image %s", ref)
+		}
+
+		out := map[string][]byte{}
+		for name, contents := range files {
+			if include(name) {
+				out[name] = contents
+			}
+		}
+
+		return out, nil
+	}
+
+	testCases := []struct {
+		name            string
+		release         string
+		opts            DownloadOpts
+		expectedSources []string
+		expectedFiles   map[string]string
+		errExpected     bool
+	}{
+		{
+			name:            "Only missing archives are extracted from the payload",
+			release:         "4.19.3",
+			opts:            DownloadOpts{Tools: []Tool{OcTool, InstallerTool}, OS: "linux", Arch: "arm64"},
+			expectedSources: []string{ToolSourceDownloadServer, ToolSourcePayload},
+			expectedFiles:   map[string]string{"oc": "oc-from-download-server", "openshift-install": "installer-from-payload"},
+		},
+		{
+			name:            "Everything is extracted from the payload",
+			release:         "4.19.3",
+			opts:            DownloadOpts{Tools: []Tool{OcTool, InstallerTool}, OS: "linux", Arch: "arm64", FromPayload: true},
+			expectedSources: []string{ToolSourcePayload, ToolSourcePayload},
+			expectedFiles:   map[string]string{"oc": "oc-from-payload", "openshift-install": "installer-from-payload"},
+		},
+		{
+			name:            "Payload pullspec is extracted without the release controller",
+			release:         payloadPullspec,
+			opts:            DownloadOpts{Tools: []Tool{OcTool, InstallerTool}, OS: "linux", Arch: "arm64"},
+			expectedSources: []string{ToolSourcePayload, ToolSourcePayload},
+			expectedFiles:   map[string]string{"oc": "oc-from-payload", "openshift-install": "installer-from-payload"},
+		},
+		{
+			name:        "Unknown release tag",
+			release:     "4.19.4",
+			opts:        DownloadOpts{Tools: []Tool{OcTool}, OS: "linux", Arch: "arm64", FromPayload: true},
+			errExpected: true,
+		},
+		{
+			name:        "Payload image is for a different architecture",
+			release:     "4.19.3",
+			opts:        DownloadOpts{Tools: []Tool{OcTool}, OS: "linux", Arch: "s390x", FromPayload: true},
+			errExpected: true,
+		},
+		{
+			name:        "Component is not in the payload",
+			release:     "4.19.3",
+			opts:        DownloadOpts{Tools: []Tool{CcoctlTool}, OS: "linux", Arch: "arm64", FromPayload: true},
+			errExpected: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.opts.Dir = t.TempDir()
+
+			td := newToolDownloader(rc, testCase.opts)
+			td.getImagePlatforms = getImagePlatforms
+			td.readImageFiles = readImageFiles
+
+			result, err := td.download(context.Background(), testCase.release)
+			if testCase.errExpected {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			sources := []string{}
+			for _, tool := range result.Tools {
+				sources = append(sources, tool.Source)
+			}
+
+			assert.Equal(t, testCase.expectedSources, sources)
+			assert.Equal(t, testCase.expectedFiles, readDir(t, testCase.opts.Dir))
+			assert.Equal(t, sha256Hex([]byte("installer-from-payload")), result.Tools[1].SHA256)
+			assert.Equal(t, expectedInstallerPullspec, result.Tools[1].From)
+		})
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	checksums, err := parseChecksums(strings.NewReader("abc  openshift-client-linux-4.19.3.tar.gz\n\ndef *release.txt\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"openshift-client-linux-4.19.3.tar.gz": "abc",
+		"release.txt":                          "def",
+	}, checksums)
+
+	_, err = parseChecksums(strings.NewReader("not a checksum line\n"))
+	assert.Error(t, err)
+}
+
+func TestWriteUnpackedFile(t *testing.T) {
+	testCases := []struct {
+		name         string
+		entry        string
+		expectedFile string
+		errExpected  bool
+	}{
+		{
+			name:         "Plain name",
+			entry:        "oc",
+			expectedFile: "oc",
+		},
+		{
+			name:         "Directories are dropped",
+			entry:        "openshift-client/bin/oc",
+			expectedFile: "oc",
+		},
+		{
+			name:         "Parent directories are dropped",
+			entry:        "../../oc",
+			expectedFile: "oc",
+		},
+		{
+			name:        "Parent directory",
+			entry:       "bin/..",
+			errExpected: true,
+		},
+		{
+			name:        "Windows separators",
+			entry:       `..\oc.exe`,
+			errExpected: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			dest, err := writeUnpackedFile(dir, testCase.entry, 0o755, strings.NewReader("binary"))
+			if testCase.errExpected {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(dir, testCase.expectedFile), dest)
+			assert.FileExists(t, dest)
+		})
+	}
+}
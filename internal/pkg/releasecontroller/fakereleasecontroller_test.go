@@ -0,0 +1,155 @@
+package releasecontroller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// fakeReleaseController serves a subset of the release controller API from
+// in-memory fixtures.
+type fakeReleaseController struct {
+	// Release stream name -> tags in newest-first order.
+	streams map[string][]Release
+	// Release tag name -> release info.
+	releaseInfos map[string]*ReleaseInfo
+	// Release tag name -> verification results.
+	apiReleaseInfos map[string]*APIReleaseInfo
+	// URL path -> contents of any other files to serve, e.g., from a client
+	// download page.
+	files map[string][]byte
+}
+
+func newFakeReleaseController() *fakeReleaseController {
+	return &fakeReleaseController{
+		streams:         map[string][]Release{},
+		releaseInfos:    map[string]*ReleaseInfo{},
+		apiReleaseInfos: map[string]*APIReleaseInfo{},
+		files:           map[string][]byte{},
+	}
+}
+
+func (f *fakeReleaseController) addTag(stream string, tag Release, ri *ReleaseInfo) {
+	f.streams[stream] = append(f.streams[stream], tag)
+	if ri != nil {
+		f.releaseInfos[tag.Name] = ri
+	}
+}
+
+// start starts a TLS server for the fake release controller and returns a
+// ReleaseController client configured to talk to it.
+func (f *fakeReleaseController) start(t *testing.T) *ReleaseController {
+	t.Helper()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(srv.Close)
+
+	return New(srv.Listener.Addr().String(), &ReleaseControllerConfig{Client: srv.Client()})
+}
+
+func (f *fakeReleaseController) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	path := strings.Trim(req.URL.Path, "/")
+	parts := strings.Split(path, "/")
+
+	switch {
+	case path == "api/v1/releasestreams/all" || path == "api/v1/releasestreams/accepted" || path == "api/v1/releasestreams/rejected":
+		f.writeReleaseStreams(w, parts[len(parts)-1])
+	case len(parts) == 5 && parts[2] == "releasestream" && parts[4] == "tags":
+		f.writeTags(w, parts[3], req.URL.Query().Get("phase"))
+	case len(parts) == 5 && parts[2] == "releasestream" && parts[4] == "latest":
+		f.writeLatest(w, parts[3])
+	case len(parts) == 6 && parts[2] == "releasestream" && parts[4] == "release":
+		writeJSONOrNotFound(w, f.apiReleaseInfos[parts[5]])
+	case len(parts) == 3 && parts[0] == "releasetag" && parts[2] == "json":
+		writeJSONOrNotFound(w, f.releaseInfos[parts[1]])
+	case f.files[path] != nil:
+		//nolint:errcheck // This is test code.
+		w.Write(f.files[path])
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func (f *fakeReleaseController) writeReleaseStreams(w http.ResponseWriter, kind string) {
+	out := map[string][]string{}
+	for stream, tags := range f.streams {
+		out[stream] = []string{}
+		for _, tag := range tags {
+			if kind == "all" || strings.EqualFold(tag.Phase, kind) {
+				out[stream] = append(out[stream], tag.Name)
+			}
+		}
+	}
+
+	writeJSONOrNotFound(w, out)
+}
+
+func (f *fakeReleaseController) writeTags(w http.ResponseWriter, stream, phase string) {
+	tags, ok := f.streams[stream]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	out := &ReleaseTags{Name: stream, Tags: []Release{}}
+	for _, tag := range tags {
+		if phase == "" || tag.Phase == phase {
+			out.Tags = append(out.Tags, tag)
+		}
+	}
+
+	writeJSONOrNotFound(w, out)
+}
+
+func (f *fakeReleaseController) writeLatest(w http.ResponseWriter, stream string) {
+	for _, tag := range f.streams[stream] {
+		if tag.Phase == string(PhaseAccepted) {
+			writeJSONOrNotFound(w, tag)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func writeJSONOrNotFound(w http.ResponseWriter, obj interface{}) {
+	b, err := json.Marshal(obj)
+	if err != nil || string(b) == "null" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	//nolint:errcheck // This is test code.
+	w.Write(b)
+}
+
+// newReleaseInfoWithComponents creates a ReleaseInfo whose references map each
+// component name to the given pullspec.
+func newReleaseInfoWithComponents(version string, components map[string]string) *ReleaseInfo {
+	ri := &ReleaseInfo{
+		Metadata: Metadata{
+			Version: version,
+		},
+		References: &imagev1.ImageStream{},
+	}
+
+	ri.References.Name = version
+
+	for name, pullspec := range components {
+		ri.References.Spec.Tags = append(ri.References.Spec.Tags, imagev1.TagReference{
+			Name: name,
+			From: &corev1.ObjectReference{
+				Kind: "DockerImage",
+				Name: pullspec,
+			},
+		})
+	}
+
+	return ri
+}
@@ -37,6 +37,24 @@ func (r *releaseInfoFetcher) FetchReleaseInfo(ctx context.Context, tagOrPullspec
 	return ri, err
 }
 
+// GetReleaseInfo returns the parsed release info for the given release tag or
+// image pullspec.
+func (r *releaseInfoFetcher) GetReleaseInfo(ctx context.Context, tagOrPullspec string) (*ReleaseInfo, error) {
+	ri, pullspec, err := r.getReleaseInfoForPullspec(ctx, tagOrPullspec)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &ReleaseInfo{}
+	if err := json.Unmarshal(ri.ReleaseInfo, out); err != nil {
+		return nil, err
+	}
+
+	out.ReleasePullspec = pullspec
+
+	return out, nil
+}
+
 func (r *releaseInfoFetcher) FetchWithComponents(ctx context.Context, tagOrPullspec string, components []string) (*ReleaseInfoResults, error) {
 	ri, _, err := r.getReleaseInfoForPullspec(ctx, tagOrPullspec)
 	if err != nil {
@@ -64,28 +82,29 @@ func (r *releaseInfoFetcher) FetchWithAllComponents(ctx context.Context, tagOrPu
 	return r.FetchWithComponents(ctx, tagOrPullspec, []string{})
 }
 
-func (r *releaseInfoFetcher) getReleaseInfoForPullspec(ctx context.Context, tagOrPullspec string) (*ReleaseInfoResults, string, error) {
+// GetPullspec returns the release payload pullspec for the given release tag
+// or image pullspec. Unlike GetReleaseInfo, this does not require oc.
+func (r *releaseInfoFetcher) GetPullspec(ctx context.Context, tagOrPullspec string) (string, error) {
 	vk, err := GetVersionKind(tagOrPullspec)
 	if err != nil {
-		return nil, "", err
+		return "", err
 	}
 
-	pullspec := ""
 	if vk == PullspecVersionKind {
-		pullspec = tagOrPullspec
+		return tagOrPullspec, nil
 	}
 
 	if vk == SemverVersionKind {
-		ps, err := r.findPullspecForReleaseTag(ctx, tagOrPullspec)
-		if err != nil {
-			return nil, "", err
-		}
-
-		pullspec = ps
+		return r.findPullspecForReleaseTag(ctx, tagOrPullspec)
 	}
 
-	if pullspec == "" {
-		return nil, "", fmt.Errorf("invalid versionkind %q", vk)
+	return "", fmt.Errorf("invalid versionkind %q", vk)
+}
+
+func (r *releaseInfoFetcher) getReleaseInfoForPullspec(ctx context.Context, tagOrPullspec string) (*ReleaseInfoResults, string, error) {
+	pullspec, err := r.GetPullspec(ctx, tagOrPullspec)
+	if err != nil {
+		return nil, "", err
 	}
 
 	riBytes, err := GetReleaseInfoBytes(ctx, pullspec)
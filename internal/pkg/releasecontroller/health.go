@@ -0,0 +1,249 @@
+package releasecontroller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"k8s.io/klog"
+)
+
+const (
+	defaultHealthWindow int = 20
+	// How many release tags to get the verification results for at once.
+	healthConcurrency int = 5
+	// The layout of the timestamp at the end of most release tag names, e.g.,
+	// 4.18.0-0.nightly-2025-01-01-123456.
+	tagTimestampLayout string = "2006-01-02-150405"
+)
+
+var tagTimestampRegex = regexp.MustCompile(`\d{4}-\d{2}-\d{2}-\d{6}$`)
+
+// StreamHealthOpts holds the options for determining the health of a release
+// stream.
+type StreamHealthOpts struct {
+	// Window is the number of most recent accepted or rejected release tags to
+	// consider. Defaults to 20.
+	Window int
+	// MaxAge is how long ago the last release tag may have been accepted
+	// before the release stream is considered stale. Staleness is not checked
+	// if zero.
+	MaxAge time.Duration
+	// Now is the time the age of the last accepted release tag is relative to.
+	// Defaults to the current time.
+	Now time.Time
+}
+
+// StreamHealth describes how recently a release stream has had a release
+// accepted and how often its releases are accepted.
+type StreamHealth struct {
+	Stream string `json:"stream"`
+	// LastAccepted is the name of the most recently accepted release tag.
+	LastAccepted string `json:"lastAccepted,omitempty"`
+	// LastAcceptedTime is when the last accepted release tag was created. See
+	// GetTagTime.
+	LastAcceptedTime *time.Time `json:"lastAcceptedTime,omitempty"`
+	// SinceLastAccepted is how long ago the last accepted release tag was
+	// created.
+	SinceLastAccepted string `json:"sinceLastAccepted,omitempty"`
+	// Considered is the number of accepted or rejected release tags the
+	// following are computed from.
+	Considered      int     `json:"considered"`
+	Accepted        int     `json:"accepted"`
+	Rejected        int     `json:"rejected"`
+	AcceptanceRatio float64 `json:"acceptanceRatio"`
+	// RejectionStreak is the number of consecutive most recent release tags
+	// which were rejected.
+	RejectionStreak int `json:"rejectionStreak"`
+	// MostCommonBlockingFailure is the blocking job which failed for the most
+	// rejected release tags.
+	MostCommonBlockingFailure      string `json:"mostCommonBlockingFailure,omitempty"`
+	MostCommonBlockingFailureCount int    `json:"mostCommonBlockingFailureCount,omitempty"`
+	// Stale is true when the last release tag was accepted longer ago than the
+	// maximum age, or no release tag has been accepted at all.
+	Stale bool `json:"stale"`
+}
+
+// ParseTagTimestamp parses the timestamp at the end of a release tag name,
+// e.g., 4.18.0-0.nightly-2025-01-01-123456. These timestamps are in UTC.
+func ParseTagTimestamp(name string) (time.Time, error) {
+	ts := tagTimestampRegex.FindString(name)
+	if ts == "" {
+		return time.Time{}, fmt.Errorf("release tag %q does not have a timestamp", name)
+	}
+
+	return time.Parse(tagTimestampLayout, ts)
+}
+
+// GetTagTime returns when the given release tag was created. This is parsed
+// from the release tag name if it has a timestamp. Otherwise (e.g., 4.18.3),
+// it is the creation time of the release payload, which requires getting its
+// release info from the release controller.
+func (r *ReleaseController) GetTagTime(ctx context.Context, tag string) (time.Time, error) {
+	if ts, err := ParseTagTimestamp(tag); err == nil {
+		return ts, nil
+	}
+
+	ri, err := r.GetReleaseInfo(ctx, tag)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not get release info for release tag %q: %w", tag, err)
+	}
+
+	if ri.Config.Created == "" {
+		return time.Time{}, fmt.Errorf("release tag %q does not have a creation time", tag)
+	}
+
+	return time.Parse(time.RFC3339, ri.Config.Created)
+}
+
+// Health determines how recently the release stream has had a release
+// accepted and how often its most recent releases were accepted. Release tags
+// which are still being verified are not considered.
+func (r *ReleaseStream) Health(ctx context.Context, opts StreamHealthOpts) (*StreamHealth, error) {
+	window := opts.Window
+	if window <= 0 {
+		window = defaultHealthWindow
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	out := &StreamHealth{Stream: r.name}
+
+	accepted, err := r.TagsByPhase(ctx, PhaseAccepted)
+	if err != nil {
+		return nil, fmt.Errorf("could not get accepted tags for release stream %q: %w", r.name, err)
+	}
+
+	if len(accepted.Tags) != 0 {
+		out.LastAccepted = accepted.Tags[0].Name
+
+		ts, err := r.rc.GetTagTime(ctx, out.LastAccepted)
+		switch {
+		case err == nil:
+			out.LastAcceptedTime = &ts
+			since := now.Sub(ts)
+			out.SinceLastAccepted = since.Round(time.Minute).String()
+			out.Stale = opts.MaxAge != 0 && since > opts.MaxAge
+		case opts.MaxAge != 0:
+			return nil, fmt.Errorf("could not determine whether release stream %q is stale: %w", r.name, err)
+		default:
+			klog.Warningf("Could not determine when release tag %q was created: %s", out.LastAccepted, err)
+		}
+	} else {
+		out.Stale = opts.MaxAge != 0
+	}
+
+	tags, err := r.Tags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get tags for release stream %q: %w", r.name, err)
+	}
+
+	rejected := []string{}
+	inStreak := true
+
+	for _, tag := range tags.Tags {
+		if out.Considered == window {
+			break
+		}
+
+		switch Phase(tag.Phase) {
+		case PhaseAccepted:
+			out.Accepted++
+			inStreak = false
+		case PhaseRejected:
+			out.Rejected++
+			rejected = append(rejected, tag.Name)
+			if inStreak {
+				out.RejectionStreak++
+			}
+		default:
+			continue
+		}
+
+		out.Considered++
+	}
+
+	if out.Considered != 0 {
+		out.AcceptanceRatio = float64(out.Accepted) / float64(out.Considered)
+	}
+
+	failures, err := r.countBlockingFailures(ctx, rejected)
+	if err != nil {
+		return nil, err
+	}
+
+	if jobs := sortedByCount(failures); len(jobs) != 0 {
+		out.MostCommonBlockingFailure = jobs[0]
+		out.MostCommonBlockingFailureCount = failures[jobs[0]]
+	}
+
+	return out, nil
+}
+
+// Counts how many of the given release tags each blocking job failed for.
+func (r *ReleaseStream) countBlockingFailures(ctx context.Context, tags []string) (map[string]int, error) {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(healthConcurrency)
+
+	mu := &sync.Mutex{}
+	out := map[string]int{}
+
+	for _, tag := range tags {
+		g.Go(func() error {
+			// The most common failure is best-effort, so release tags whose
+			// verification results cannot be retrieved are skipped.
+			info, err := r.Tag(ctx, tag)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+
+				klog.Warningf("Could not get verification results for release tag %q: %s", tag, err)
+				return nil
+			}
+
+			if info.Results == nil {
+				return nil
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			for job, status := range info.Results.BlockingJobs {
+				if status != nil && status.State == "Failed" {
+					out[job]++
+				}
+			}
+
+			return nil
+		})
+	}
+
+	return out, g.Wait()
+}
+
+// Sorts the keys by their count, highest first. Ties are broken by name so
+// that the result is stable.
+func sortedByCount(counts map[string]int) []string {
+	out := []string{}
+	for key := range counts {
+		out = append(out, key)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if counts[out[i]] != counts[out[j]] {
+			return counts[out[i]] > counts[out[j]]
+		}
+
+		return out[i] < out[j]
+	})
+
+	return out
+}
@@ -0,0 +1,205 @@
+package releasecontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTagTimestamp(t *testing.T) {
+	testCases := []struct {
+		name      string
+		expected  time.Time
+		expectErr bool
+	}{
+		{
+			name:     "4.18.0-0.nightly-2025-01-02-123456",
+			expected: time.Date(2025, time.January, 2, 12, 34, 56, 0, time.UTC),
+		},
+		{
+			name:     "4.18.0-0.nightly-arm64-2025-01-02-123456",
+			expected: time.Date(2025, time.January, 2, 12, 34, 56, 0, time.UTC),
+		},
+		{
+			name:      "4.18.3",
+			expectErr: true,
+		},
+		{
+			name:      "4.18.0-0.nightly-2025-13-02-123456",
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ts, err := ParseTagTimestamp(testCase.name)
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, ts)
+		})
+	}
+}
+
+func newFakeReleaseControllerForHealth() *fakeReleaseController {
+	f := newFakeReleaseController()
+
+	// Newest first.
+	for _, tag := range []struct {
+		name   string
+		phase  Phase
+		failed []string
+	}{
+		{name: "4.18.0-0.nightly-2025-01-05-000000", phase: PhaseReady},
+		{name: "4.18.0-0.nightly-2025-01-04-000000", phase: PhaseRejected, failed: []string{"upgrade", "install"}},
+		{name: "4.18.0-0.nightly-2025-01-03-120000", phase: PhaseRejected, failed: []string{"upgrade"}},
+		{name: "4.18.0-0.nightly-2025-01-03-000000", phase: PhaseAccepted},
+		{name: "4.18.0-0.nightly-2025-01-02-000000", phase: PhaseRejected, failed: []string{"install"}},
+		{name: "4.18.0-0.nightly-2025-01-01-000000", phase: PhaseAccepted},
+	} {
+		f.addTag("4.18.0-0.nightly", Release{Name: tag.name, Phase: string(tag.phase)}, nil)
+
+		jobs := VerificationStatusMap{
+			"upgrade": {State: "Succeeded"},
+			"install": {State: "Succeeded"},
+			// Jobs which have not reported a status yet.
+			"serial": nil,
+		}
+
+		for _, job := range tag.failed {
+			jobs[job] = &VerificationStatus{State: "Failed"}
+		}
+
+		f.apiReleaseInfos[tag.name] = &APIReleaseInfo{
+			Name:    tag.name,
+			Phase:   string(tag.phase),
+			Results: &VerificationJobsSummary{BlockingJobs: jobs},
+		}
+	}
+
+	f.addTag("4-stable", Release{Name: "4.18.3", Phase: string(PhaseAccepted)}, &ReleaseInfo{Config: Config{Created: "2025-01-02T00:00:00Z"}})
+	// The release info for this release tag cannot be found.
+	f.addTag("4-dev-preview", Release{Name: "4.19.0-ec.1", Phase: string(PhaseAccepted)}, nil)
+	f.addTag("4.19.0-0.nightly", Release{Name: "4.19.0-0.nightly-2025-01-04-000000", Phase: string(PhaseRejected)}, nil)
+
+	return f
+}
+
+func TestHealth(t *testing.T) {
+	rc := newFakeReleaseControllerForHealth().start(t)
+
+	now := time.Date(2025, time.January, 4, 0, 0, 0, 0, time.UTC)
+	lastAcceptedTime := time.Date(2025, time.January, 3, 0, 0, 0, 0, time.UTC)
+	createdTime := time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		stream    string
+		opts      StreamHealthOpts
+		expected  *StreamHealth
+		expectErr bool
+	}{
+		{
+			name:   "Nightly",
+			stream: "4.18.0-0.nightly",
+			opts:   StreamHealthOpts{Now: now},
+			expected: &StreamHealth{
+				Stream:                         "4.18.0-0.nightly",
+				LastAccepted:                   "4.18.0-0.nightly-2025-01-03-000000",
+				LastAcceptedTime:               &lastAcceptedTime,
+				SinceLastAccepted:              "24h0m0s",
+				Considered:                     5,
+				Accepted:                       2,
+				Rejected:                       3,
+				AcceptanceRatio:                0.4,
+				RejectionStreak:                2,
+				MostCommonBlockingFailure:      "install",
+				MostCommonBlockingFailureCount: 2,
+			},
+		},
+		{
+			name:   "Nightly within window",
+			stream: "4.18.0-0.nightly",
+			opts:   StreamHealthOpts{Now: now, Window: 2, MaxAge: 12 * time.Hour},
+			expected: &StreamHealth{
+				Stream:                         "4.18.0-0.nightly",
+				LastAccepted:                   "4.18.0-0.nightly-2025-01-03-000000",
+				LastAcceptedTime:               &lastAcceptedTime,
+				SinceLastAccepted:              "24h0m0s",
+				Considered:                     2,
+				Rejected:                       2,
+				RejectionStreak:                2,
+				MostCommonBlockingFailure:      "upgrade",
+				MostCommonBlockingFailureCount: 2,
+				Stale:                          true,
+			},
+		},
+		{
+			name:   "Tags without timestamps use the creation time of their payload",
+			stream: "4-stable",
+			opts:   StreamHealthOpts{Now: now, MaxAge: time.Hour},
+			expected: &StreamHealth{
+				Stream:            "4-stable",
+				LastAccepted:      "4.18.3",
+				LastAcceptedTime:  &createdTime,
+				SinceLastAccepted: "48h0m0s",
+				Considered:        1,
+				Accepted:          1,
+				AcceptanceRatio:   1,
+				Stale:             true,
+			},
+		},
+		{
+			name:   "Unknown creation time without a max age",
+			stream: "4-dev-preview",
+			opts:   StreamHealthOpts{Now: now},
+			expected: &StreamHealth{
+				Stream:          "4-dev-preview",
+				LastAccepted:    "4.19.0-ec.1",
+				Considered:      1,
+				Accepted:        1,
+				AcceptanceRatio: 1,
+			},
+		},
+		{
+			name:      "Unknown creation time with a max age",
+			stream:    "4-dev-preview",
+			opts:      StreamHealthOpts{Now: now, MaxAge: time.Hour},
+			expectErr: true,
+		},
+		{
+			name:   "No accepted tags",
+			stream: "4.19.0-0.nightly",
+			opts:   StreamHealthOpts{Now: now, MaxAge: time.Hour},
+			expected: &StreamHealth{
+				Stream:          "4.19.0-0.nightly",
+				Considered:      1,
+				Rejected:        1,
+				RejectionStreak: 1,
+				Stale:           true,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			health, err := rc.ReleaseStream(testCase.stream).Health(context.Background(), testCase.opts)
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, health)
+		})
+	}
+
+	_, err := rc.ReleaseStream("unknown").Health(context.Background(), StreamHealthOpts{})
+	assert.Error(t, err)
+}
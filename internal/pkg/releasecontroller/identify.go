@@ -0,0 +1,136 @@
+package releasecontroller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containers/image/v5/docker/reference"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// IdentifiedRelease describes which release controller, release stream, and
+// tag a release payload pullspec belongs to.
+type IdentifiedRelease struct {
+	ResolvedRelease
+	// Version is the version from the release payload metadata.
+	Version string `json:"version"`
+	// CurrentDigestedPullspec is what the tagged pullspec on the release
+	// controller currently resolves to.
+	CurrentDigestedPullspec string `json:"currentDigestedPullspec"`
+	// TagMatchesDigest is true when the tagged pullspec on the release
+	// controller still resolves to the same digest as the given pullspec.
+	TagMatchesDigest bool `json:"tagMatchesDigest"`
+}
+
+// Identify determines which release controller, release stream, and tag the
+// given release payload pullspec belongs to by reading the version from the
+// release payload and looking it up on each release controller. It also
+// determines whether the tag on the release controller still refers to the
+// same payload. This requires oc.
+func Identify(ctx context.Context, pullspec string, opts ResolveOpts) (*IdentifiedRelease, error) {
+	return newResolver(opts).identify(ctx, pullspec)
+}
+
+func (r *resolver) identify(ctx context.Context, pullspec string) (*IdentifiedRelease, error) {
+	digested, err := r.getDigestedPullspec(pullspec)
+	if err != nil {
+		return nil, err
+	}
+
+	ri, err := r.getReleaseInfo(ctx, digested, r.authfilePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not get release info for %q: %w", digested, err)
+	}
+
+	candidates, err := r.findTagsForReleaseInfo(ctx, ri)
+	if err != nil {
+		return nil, fmt.Errorf("could not identify %q: %w", pullspec, err)
+	}
+
+	d, err := ParseImageDigest(digested)
+	if err != nil {
+		return nil, err
+	}
+
+	var out *IdentifiedRelease
+
+	// The same tag name may exist on more than one release controller, so
+	// prefer the one whose tagged pullspec still refers to this payload.
+	for _, candidate := range candidates {
+		identified := &IdentifiedRelease{
+			ResolvedRelease: *candidate,
+			Version:         ri.Metadata.Version,
+		}
+
+		identified.Input = pullspec
+		identified.Kind = PullspecVersionKind
+		identified.DigestedPullspec = digested
+
+		current, err := r.resolveDigest(candidate.TaggedPullspec, r.authfilePath)
+		if err == nil {
+			identified.CurrentDigestedPullspec = current
+
+			currentDigest, err := ParseImageDigest(current)
+			identified.TagMatchesDigest = err == nil && currentDigest == d
+		}
+
+		if identified.TagMatchesDigest {
+			return identified, nil
+		}
+
+		if out == nil {
+			out = identified
+		}
+	}
+
+	return out, nil
+}
+
+// Looks up the release tag for each of the names the release payload goes by.
+// These are usually the same, but may differ for some OKD releases.
+func (r *resolver) findTagsForReleaseInfo(ctx context.Context, ri *ReleaseInfo) ([]*ResolvedRelease, error) {
+	names := sets.New[string]()
+	if ri.Metadata.Version != "" {
+		names.Insert(ri.Metadata.Version)
+	}
+
+	if ri.References != nil && ri.References.Name != "" {
+		names.Insert(ri.References.Name)
+	}
+
+	if names.Len() == 0 {
+		return nil, fmt.Errorf("release payload does not have a version")
+	}
+
+	var lastErr error
+
+	for _, name := range sets.List(names) {
+		found, err := r.findTag(ctx, name, false)
+		if err == nil {
+			return found, nil
+		}
+
+		lastErr = err
+	}
+
+	return nil, lastErr
+}
+
+// Resolves the given pullspec to its digested form, if it is not already.
+func (r *resolver) getDigestedPullspec(pullspec string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(pullspec)
+	if err != nil {
+		return "", fmt.Errorf("could not parse %q: %w", pullspec, err)
+	}
+
+	if _, ok := named.(reference.Digested); ok {
+		return pullspec, nil
+	}
+
+	digested, err := r.resolveDigest(pullspec, r.authfilePath)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve %q to a digest: %w", pullspec, err)
+	}
+
+	return digested, nil
+}
@@ -0,0 +1,171 @@
+package releasecontroller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	amd64PayloadDigest string = "sha256:aa6cd007e204673ceafa266fe1cf359b386cbb1e34c785ae2dd1856e8f61b71c"
+	arm64PayloadDigest string = "sha256:bec41abb841b042589766901962cf99bb7894bd673d4f71602523aa0c255a4f4"
+	stalePayloadDigest string = "sha256:eccbe17a07f73e67689e2617855525c81de69fcb06f188b29b46c69c95c92242"
+	payloadRepo        string = "quay.io/openshift-release-dev/ocp-release"
+)
+
+func TestIdentify(t *testing.T) {
+	// The same tag exists on both the amd64 and arm64 release controllers.
+	amd64 := newFakeReleaseController()
+	amd64.addTag("4-stable", Release{Name: "4.18.3", Phase: string(PhaseAccepted), Pullspec: payloadRepo + ":4.18.3-x86_64"}, nil)
+	amd64RC := amd64.start(t)
+
+	arm64 := newFakeReleaseController()
+	arm64.addTag("4-stable-arm64", Release{Name: "4.18.3", Phase: string(PhaseAccepted), Pullspec: payloadRepo + ":4.18.3-aarch64"}, nil)
+	arm64RC := arm64.start(t)
+
+	releaseInfos := map[string]*ReleaseInfo{
+		payloadRepo + "@" + amd64PayloadDigest:                                                   newReleaseInfoWithComponents("4.18.3", nil),
+		payloadRepo + "@" + arm64PayloadDigest:                                                   newReleaseInfoWithComponents("4.18.3", nil),
+		payloadRepo + "@" + stalePayloadDigest:                                                   newReleaseInfoWithComponents("4.18.3", nil),
+		payloadRepo + "@sha256:0000000000000000000000000000000000000000000000000000000000000000": newReleaseInfoWithComponents("4.18.99", nil),
+	}
+
+	digests := map[string]string{
+		payloadRepo + ":4.18.3-x86_64":  amd64PayloadDigest,
+		payloadRepo + ":4.18.3-aarch64": arm64PayloadDigest,
+	}
+
+	testCases := []struct {
+		name      string
+		pullspec  string
+		expected  *IdentifiedRelease
+		expectErr bool
+	}{
+		{
+			name:     "Digested amd64 pullspec",
+			pullspec: payloadRepo + "@" + amd64PayloadDigest,
+			expected: &IdentifiedRelease{
+				ResolvedRelease: ResolvedRelease{
+					Tag:              "4.18.3",
+					Stream:           "4-stable",
+					Controller:       amd64RC.Host(),
+					TaggedPullspec:   payloadRepo + ":4.18.3-x86_64",
+					DigestedPullspec: payloadRepo + "@" + amd64PayloadDigest,
+				},
+				CurrentDigestedPullspec: payloadRepo + "@" + amd64PayloadDigest,
+				TagMatchesDigest:        true,
+			},
+		},
+		{
+			name:     "Digested arm64 pullspec",
+			pullspec: payloadRepo + "@" + arm64PayloadDigest,
+			expected: &IdentifiedRelease{
+				ResolvedRelease: ResolvedRelease{
+					Tag:              "4.18.3",
+					Stream:           "4-stable-arm64",
+					Controller:       arm64RC.Host(),
+					TaggedPullspec:   payloadRepo + ":4.18.3-aarch64",
+					DigestedPullspec: payloadRepo + "@" + arm64PayloadDigest,
+				},
+				CurrentDigestedPullspec: payloadRepo + "@" + arm64PayloadDigest,
+				TagMatchesDigest:        true,
+			},
+		},
+		{
+			name:     "Tagged pullspec",
+			pullspec: payloadRepo + ":4.18.3-aarch64",
+			expected: &IdentifiedRelease{
+				ResolvedRelease: ResolvedRelease{
+					Tag:              "4.18.3",
+					Stream:           "4-stable-arm64",
+					Controller:       arm64RC.Host(),
+					TaggedPullspec:   payloadRepo + ":4.18.3-aarch64",
+					DigestedPullspec: payloadRepo + "@" + arm64PayloadDigest,
+				},
+				CurrentDigestedPullspec: payloadRepo + "@" + arm64PayloadDigest,
+				TagMatchesDigest:        true,
+			},
+		},
+		{
+			name:     "Tag no longer refers to the payload",
+			pullspec: payloadRepo + "@" + stalePayloadDigest,
+			expected: &IdentifiedRelease{
+				ResolvedRelease: ResolvedRelease{
+					Tag:              "4.18.3",
+					Stream:           "4-stable",
+					Controller:       amd64RC.Host(),
+					TaggedPullspec:   payloadRepo + ":4.18.3-x86_64",
+					DigestedPullspec: payloadRepo + "@" + stalePayloadDigest,
+				},
+				CurrentDigestedPullspec: payloadRepo + "@" + amd64PayloadDigest,
+				TagMatchesDigest:        false,
+			},
+		},
+		{
+			name:      "Unknown version",
+			pullspec:  payloadRepo + "@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			expectErr: true,
+		},
+		{
+			name:      "Unreadable payload",
+			pullspec:  payloadRepo + "@sha256:1111111111111111111111111111111111111111111111111111111111111111",
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := newResolver(ResolveOpts{Controllers: []*ReleaseController{amd64RC, arm64RC}})
+			r.resolveDigest = func(pullspec, _ string) (string, error) {
+				d, ok := digests[pullspec]
+				if !ok {
+					return "", fmt.Errorf("unknown pullspec %q", pullspec)
+				}
+
+				return payloadRepo + "@" + d, nil
+			}
+
+			r.getReleaseInfo = func(_ context.Context, pullspec, _ string) (*ReleaseInfo, error) {
+				ri, ok := releaseInfos[pullspec]
+				if !ok {
+					return nil, fmt.Errorf("could not read %q", pullspec)
+				}
+
+				return ri, nil
+			}
+
+			result, err := r.identify(context.Background(), testCase.pullspec)
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			testCase.expected.Input = testCase.pullspec
+			testCase.expected.Kind = PullspecVersionKind
+			testCase.expected.Phase = string(PhaseAccepted)
+			testCase.expected.Version = "4.18.3"
+
+			assert.Equal(t, testCase.expected, result)
+
+			// Resolving the pullspec should identify it the same way, unless the
+			// tag no longer refers to it, in which case only the digest is known.
+			resolved, err := r.resolve(context.Background(), testCase.pullspec)
+			require.NoError(t, err)
+
+			if testCase.expected.TagMatchesDigest {
+				assert.Equal(t, &testCase.expected.ResolvedRelease, resolved)
+			} else {
+				assert.Equal(t, &ResolvedRelease{
+					Input:            testCase.pullspec,
+					Kind:             PullspecVersionKind,
+					DigestedPullspec: testCase.pullspec,
+				}, resolved)
+			}
+		})
+	}
+}
@@ -9,13 +9,17 @@ import (
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
 // ReleaseController represents a release controller API client
 type ReleaseController struct {
+	scheme string
 	host   string
 	client *http.Client
+	// The release controller which web page links point at, if not this one.
+	web *ReleaseController
 }
 
 // ReleaseControllerConfig holds configuration options for the ReleaseController
@@ -33,7 +37,37 @@ func New(host string, cfg *ReleaseControllerConfig) *ReleaseController {
 	if client == nil {
 		client = &http.Client{Timeout: cfg.DefaultTimeout}
 	}
-	return &ReleaseController{host: host, client: client}
+	return &ReleaseController{scheme: "https", host: host, client: client}
+}
+
+// NewFromURL creates a new ReleaseController for the given URL, e.g.,
+// http://localhost:8080 for a release controller cache served by rcctl serve.
+// A bare hostname is assumed to be served over HTTPS.
+func NewFromURL(rawURL string, cfg *ReleaseControllerConfig) (*ReleaseController, error) {
+	if !strings.Contains(rawURL, "://") {
+		return New(rawURL, cfg), nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid release controller URL %q: %w", rawURL, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid release controller URL %q: scheme must be http or https", rawURL)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid release controller URL %q: missing host", rawURL)
+	}
+
+	if strings.Trim(u.Path, "/") != "" || u.RawQuery != "" {
+		return nil, fmt.Errorf("invalid release controller URL %q: paths and queries are not supported", rawURL)
+	}
+
+	rc := New(u.Host, cfg)
+	rc.scheme = u.Scheme
+	return rc, nil
 }
 
 // Host returns the hostname of the release controller
@@ -41,9 +75,41 @@ func (r *ReleaseController) Host() string {
 	return r.host
 }
 
-// String returns the hostname of the release controller
+// URL returns the base URL of the release controller, e.g.,
+// https://amd64.ocp.releases.ci.openshift.org.
+func (r *ReleaseController) URL() string {
+	u := r.getURLForPath("", nil)
+	return u.String()
+}
+
+// String returns the hostname of the release controller, or its URL if it is
+// not served over HTTPS so that it can be passed to NewFromURL.
 func (r *ReleaseController) String() string {
-	return r.host
+	if r.scheme == "https" {
+		return r.host
+	}
+
+	return r.URL()
+}
+
+// WithWebController returns a copy of the release controller whose web page
+// links (see ReleaseURL and ChangelogURL) point at the given release
+// controller. This is used for a release controller cache served by rcctl
+// serve so that links which are shared with others point at the release
+// controller it caches rather than at the cache.
+func (r *ReleaseController) WithWebController(web *ReleaseController) *ReleaseController {
+	out := *r
+	out.web = web
+	return &out
+}
+
+// WebController returns the release controller which web page links point at.
+func (r *ReleaseController) WebController() *ReleaseController {
+	if r.web != nil {
+		return r.web
+	}
+
+	return r
 }
 
 func (r *ReleaseController) GraphForChannel(ctx context.Context, channel string) (*ReleaseGraph, error) {
@@ -88,9 +154,23 @@ func (r *ReleaseController) GetReleaseInfo(ctx context.Context, tag string) (*Re
 	return out, err
 }
 
+// ReleaseURL returns the URL of the web page for the given release tag, which
+// includes its verification jobs and changelog.
+func (r *ReleaseController) ReleaseURL(stream, tag string) string {
+	u := r.WebController().getURLForPath(filepath.Join("/releasestream", stream, "release", tag), nil)
+	return u.String()
+}
+
+// ChangelogURL returns the URL of the web page for the changelog between the
+// given release tags.
+func (r *ReleaseController) ChangelogURL(from, to string) string {
+	u := r.WebController().getURLForPath("/changelog", url.Values{"from": []string{from}, "to": []string{to}})
+	return u.String()
+}
+
 func (r *ReleaseController) getURLForPath(path string, vals url.Values) url.URL {
 	u := url.URL{
-		Scheme: "https",
+		Scheme: r.scheme,
 		Host:   r.host,
 		Path:   path,
 	}
@@ -120,6 +200,18 @@ func (r *ReleaseController) doHTTPRequest(ctx context.Context, u url.URL) (*http
 	return resp, nil
 }
 
+// Do performs a GET request for the given path and query (e.g.,
+// /api/v1/releasestream/4-stable/latest) against the release controller and
+// returns the response as-is, regardless of its status code.
+func (r *ReleaseController) Do(ctx context.Context, requestURI string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.URL()+requestURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.client.Do(req)
+}
+
 func (r *ReleaseController) doHTTPRequestIntoStruct(ctx context.Context, path string, vals url.Values, out interface{}) error {
 	resp, err := r.doHTTPRequest(ctx, r.getURLForPath(path, vals))
 	if err != nil {
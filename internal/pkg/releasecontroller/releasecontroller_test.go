@@ -0,0 +1,84 @@
+package releasecontroller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFromURL(t *testing.T) {
+	testCases := []struct {
+		input          string
+		expectedHost   string
+		expectedURL    string
+		expectedString string
+		errExpected    bool
+	}{
+		{
+			input:          Amd64OcpReleaseController,
+			expectedHost:   Amd64OcpReleaseController,
+			expectedURL:    "https://" + Amd64OcpReleaseController,
+			expectedString: Amd64OcpReleaseController,
+		},
+		{
+			input:          "https://" + Arm64OcpReleaseController + "/",
+			expectedHost:   Arm64OcpReleaseController,
+			expectedURL:    "https://" + Arm64OcpReleaseController,
+			expectedString: Arm64OcpReleaseController,
+		},
+		{
+			input:          "http://localhost:8080",
+			expectedHost:   "localhost:8080",
+			expectedURL:    "http://localhost:8080",
+			expectedString: "http://localhost:8080",
+		},
+		{
+			input:       "ftp://localhost:8080",
+			errExpected: true,
+		},
+		{
+			input:       "http://",
+			errExpected: true,
+		},
+		{
+			input:       "http://localhost:8080/api/v1",
+			errExpected: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.input, func(t *testing.T) {
+			rc, err := NewFromURL(testCase.input, nil)
+			if testCase.errExpected {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedHost, rc.Host())
+			assert.Equal(t, testCase.expectedURL, rc.URL())
+			assert.Equal(t, testCase.expectedString, rc.String())
+			assert.Equal(t, testCase.expectedURL+"/releasestream/4-stable/release/4.19.3", rc.ReleaseURL("4-stable", "4.19.3"))
+		})
+	}
+}
+
+func TestWithWebController(t *testing.T) {
+	rc, err := NewFromURL("http://localhost:8080", nil)
+	require.NoError(t, err)
+
+	web := New(Amd64OcpReleaseController, nil)
+
+	linked := rc.WithWebController(web)
+	assert.Equal(t, web, linked.WebController())
+	assert.Equal(t, "https://"+Amd64OcpReleaseController+"/releasestream/4-stable/release/4.19.3", linked.ReleaseURL("4-stable", "4.19.3"))
+	assert.Equal(t, "https://"+Amd64OcpReleaseController+"/changelog?from=4.19.2&to=4.19.3", linked.ChangelogURL("4.19.2", "4.19.3"))
+
+	// Requests are still made to the original release controller.
+	assert.Equal(t, "http://localhost:8080", linked.URL())
+
+	// The original release controller is left as-is.
+	assert.Equal(t, rc, rc.WebController())
+	assert.Equal(t, "http://localhost:8080/releasestream/4-stable/release/4.19.3", rc.ReleaseURL("4-stable", "4.19.3"))
+}
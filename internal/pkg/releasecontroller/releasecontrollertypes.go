@@ -123,6 +123,9 @@ type Release struct {
 type ReleaseNode struct {
 	Version string `json:"version"`
 	Payload string `json:"payload"`
+	// Metadata is only populated by the OpenShift Update Service (Cincinnati)
+	// and contains things such as the channels the release is in.
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 type ReleaseEdge []int
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
 
 	imagev1 "github.com/openshift/api/image/v1"
@@ -73,13 +74,67 @@ type ReleaseInfo struct {
 	DisplayVersions map[string]DisplayVersion `json:"displayVersions,omitempty"`
 }
 
-func (ri *ReleaseInfo) GetMachineOSShortVersion() string {
-	version := ri.DisplayVersions["machine-os"].Version
-	split := strings.Split(version, ".")
-	return strings.Join(split[0:2], ".")
+// GetMachineOSShortVersion returns the major and minor version of the RHEL
+// release the machine-os build is based upon (e.g., 9.6).
+func (ri *ReleaseInfo) GetMachineOSShortVersion() (string, error) {
+	_, stream, _, err := ri.getMachineOSVersion()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimPrefix(stream, "rhel-"), nil
+}
+
+// GetMachineOSInfo returns information about the machine-os (RHCOS) build
+// shipped in the release payload.
+func (ri *ReleaseInfo) GetMachineOSInfo() (*MachineOSInfo, error) {
+	dv, stream, buildID, err := ri.getMachineOSVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	out := &MachineOSInfo{
+		Version:     dv.Version,
+		DisplayName: dv.DisplayName,
+		Stream:      stream,
+		BuildID:     buildID,
+	}
+
+	rhcos := ri.GetTagRefForComponentName(rhelCoreOSComponent)
+	if rhcos == nil || rhcos.From == nil {
+		return nil, fmt.Errorf("release %q does not have a reference for %q", ri.name(), rhelCoreOSComponent)
+	}
+
+	out.RHELCoreOSPullspec = rhcos.From.Name
+
+	// Older releases do not ship an extensions image, so its absence is not an
+	// error.
+	if extensions := ri.GetTagRefForComponentName(rhelCoreOSExtensionsComponent); extensions != nil && extensions.From != nil {
+		out.RHELCoreOSExtensionsPullspec = extensions.From.Name
+	}
+
+	return out, nil
+}
+
+func (ri *ReleaseInfo) getMachineOSVersion() (DisplayVersion, string, string, error) {
+	dv, ok := ri.DisplayVersions[machineOSDisplayVersion]
+	if !ok || dv.Version == "" {
+		return dv, "", "", fmt.Errorf("release %q does not have a %q display version", ri.name(), machineOSDisplayVersion)
+	}
+
+	stream, buildID, err := parseMachineOSVersion(dv.Version)
+	if err != nil {
+		return dv, "", "", fmt.Errorf("could not parse machine-os version for release %q: %w", ri.name(), err)
+	}
+
+	return dv, stream, buildID, nil
 }
 
 func (ri *ReleaseInfo) GetTagRefForComponentName(name string) *imagev1.TagReference {
+	if ri.References == nil {
+		return nil
+	}
+
 	for _, tag := range ri.References.Spec.Tags {
 		if tag.Name == name {
 			return tag.DeepCopy()
@@ -89,6 +144,19 @@ func (ri *ReleaseInfo) GetTagRefForComponentName(name string) *imagev1.TagRefere
 	return nil
 }
 
+// Returns the best available name for the release for use in error messages.
+func (ri *ReleaseInfo) name() string {
+	if ri.Metadata.Version != "" {
+		return ri.Metadata.Version
+	}
+
+	if ri.ReleasePullspec != "" {
+		return ri.ReleasePullspec
+	}
+
+	return ri.Image
+}
+
 type Metadata struct {
 	Kind     string   `json:"kind,omitempty"`
 	Version  string   `json:"version,omitempty"`
@@ -100,6 +168,72 @@ type DisplayVersion struct {
 	DisplayName string `json:"displayName,omitempty"`
 }
 
+const (
+	machineOSDisplayVersion       string = "machine-os"
+	rhelCoreOSComponent           string = "rhel-coreos"
+	rhelCoreOSExtensionsComponent string = "rhel-coreos-extensions"
+)
+
+// MachineOSInfo describes the machine-os (RHCOS) build shipped in a release
+// payload.
+type MachineOSInfo struct {
+	// Version is the full machine-os version, e.g., 9.6.20250701-0.
+	Version string `json:"version"`
+	// DisplayName is the human-readable name of the OS.
+	DisplayName string `json:"displayName,omitempty"`
+	// Stream is the RHEL stream the build is based upon, e.g., rhel-9.6.
+	Stream string `json:"stream"`
+	// BuildID is the build-specific portion of the version, e.g., 20250701-0.
+	BuildID string `json:"buildID"`
+	// RHELCoreOSPullspec is the pullspec for the rhel-coreos component.
+	RHELCoreOSPullspec string `json:"rhelCoreOSPullspec"`
+	// RHELCoreOSExtensionsPullspec is the pullspec for the
+	// rhel-coreos-extensions component, if the release has one.
+	RHELCoreOSExtensionsPullspec string `json:"rhelCoreOSExtensionsPullspec,omitempty"`
+}
+
+// The smallest first field of a legacy machine-os version, i.e., OCP 4.0.
+const legacyMachineOSVersionMin int = 40
+
+// Parses a machine-os version into its RHEL stream and build ID. Two version
+// schemes are understood:
+//
+// - The current scheme, which starts with the RHEL version: 9.6.20250701-0
+// - The legacy scheme, which starts with the OCP version followed by the RHEL
+// version without a dot: 418.94.202410090804-0 or 48.84.202208021106-0
+//
+// The schemes are told apart by the value of the first field rather than its
+// length since RHEL majors are well below the OCP versions which prefix the
+// legacy scheme.
+func parseMachineOSVersion(version string) (string, string, error) {
+	split := strings.SplitN(version, ".", 3)
+	if len(split) != 3 || split[0] == "" || split[1] == "" || split[2] == "" {
+		return "", "", fmt.Errorf("malformed version %q", version)
+	}
+
+	first, err := strconv.Atoi(split[0])
+	if err != nil {
+		return "", "", fmt.Errorf("malformed version %q: %w", version, err)
+	}
+
+	if _, err := strconv.Atoi(split[1]); err != nil {
+		return "", "", fmt.Errorf("malformed version %q: %w", version, err)
+	}
+
+	if first < legacyMachineOSVersionMin {
+		return fmt.Sprintf("rhel-%s.%s", split[0], split[1]), split[2], nil
+	}
+
+	if len(split[1]) < 2 {
+		return "", "", fmt.Errorf("malformed legacy version %q", version)
+	}
+
+	rhelMajor := split[1][:len(split[1])-1]
+	rhelMinor := split[1][len(split[1])-1:]
+
+	return fmt.Sprintf("rhel-%s.%s", rhelMajor, rhelMinor), split[2], nil
+}
+
 func GetReleaseInfo(ctx context.Context, releasePullspec string) (*ReleaseInfo, error) {
 	return getReleaseInfo(ctx, releasePullspec, "")
 }
@@ -0,0 +1,175 @@
+package releasecontroller
+
+import (
+	"testing"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func newReleaseInfoForTest(machineOSVersion string, components ...string) *ReleaseInfo {
+	ri := &ReleaseInfo{
+		Metadata: Metadata{
+			Version: "4.21.0-0.nightly-2026-03-05-153752",
+		},
+		DisplayVersions: map[string]DisplayVersion{},
+		References:      &imagev1.ImageStream{},
+	}
+
+	if machineOSVersion != "" {
+		ri.DisplayVersions["machine-os"] = DisplayVersion{
+			Version:     machineOSVersion,
+			DisplayName: "Red Hat Enterprise Linux CoreOS",
+		}
+	}
+
+	for _, component := range components {
+		ri.References.Spec.Tags = append(ri.References.Spec.Tags, imagev1.TagReference{
+			Name: component,
+			From: &corev1.ObjectReference{
+				Kind: "DockerImage",
+				Name: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:" + component,
+			},
+		})
+	}
+
+	return ri
+}
+
+func TestGetMachineOSShortVersionErrors(t *testing.T) {
+	_, err := newReleaseInfoForTest("", "rhel-coreos").GetMachineOSShortVersion()
+	assert.Error(t, err)
+
+	_, err = newReleaseInfoForTest("9", "rhel-coreos").GetMachineOSShortVersion()
+	assert.Error(t, err)
+
+	// The short version does not depend upon the rhel-coreos component.
+	shortVersion, err := newReleaseInfoForTest("9.6.20250701-0").GetMachineOSShortVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "9.6", shortVersion)
+}
+
+func TestGetMachineOSInfo(t *testing.T) {
+	testCases := []struct {
+		name              string
+		releaseInfo       *ReleaseInfo
+		expected          *MachineOSInfo
+		expectedShortVers string
+		expectErr         bool
+	}{
+		{
+			name:              "Current version scheme",
+			releaseInfo:       newReleaseInfoForTest("9.6.20250701-0", "rhel-coreos", "rhel-coreos-extensions"),
+			expectedShortVers: "9.6",
+			expected: &MachineOSInfo{
+				Version:                      "9.6.20250701-0",
+				DisplayName:                  "Red Hat Enterprise Linux CoreOS",
+				Stream:                       "rhel-9.6",
+				BuildID:                      "20250701-0",
+				RHELCoreOSPullspec:           "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:rhel-coreos",
+				RHELCoreOSExtensionsPullspec: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:rhel-coreos-extensions",
+			},
+		},
+		{
+			name:              "RHEL 10 stream",
+			releaseInfo:       newReleaseInfoForTest("10.1.20251010-0", "rhel-coreos"),
+			expectedShortVers: "10.1",
+			expected: &MachineOSInfo{
+				Version:            "10.1.20251010-0",
+				DisplayName:        "Red Hat Enterprise Linux CoreOS",
+				Stream:             "rhel-10.1",
+				BuildID:            "20251010-0",
+				RHELCoreOSPullspec: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:rhel-coreos",
+			},
+		},
+		{
+			name:              "Legacy version scheme",
+			releaseInfo:       newReleaseInfoForTest("418.94.202410090804-0", "rhel-coreos", "rhel-coreos-extensions"),
+			expectedShortVers: "9.4",
+			expected: &MachineOSInfo{
+				Version:                      "418.94.202410090804-0",
+				DisplayName:                  "Red Hat Enterprise Linux CoreOS",
+				Stream:                       "rhel-9.4",
+				BuildID:                      "202410090804-0",
+				RHELCoreOSPullspec:           "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:rhel-coreos",
+				RHELCoreOSExtensionsPullspec: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:rhel-coreos-extensions",
+			},
+		},
+		{
+			name:              "Legacy version scheme for OCP 4.8",
+			releaseInfo:       newReleaseInfoForTest("48.84.202208021106-0", "rhel-coreos"),
+			expectedShortVers: "8.4",
+			expected: &MachineOSInfo{
+				Version:            "48.84.202208021106-0",
+				DisplayName:        "Red Hat Enterprise Linux CoreOS",
+				Stream:             "rhel-8.4",
+				BuildID:            "202208021106-0",
+				RHELCoreOSPullspec: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:rhel-coreos",
+			},
+		},
+		{
+			name:              "Legacy version scheme for OCP 4.9",
+			releaseInfo:       newReleaseInfoForTest("49.84.202207192205-0", "rhel-coreos"),
+			expectedShortVers: "8.4",
+			expected: &MachineOSInfo{
+				Version:            "49.84.202207192205-0",
+				DisplayName:        "Red Hat Enterprise Linux CoreOS",
+				Stream:             "rhel-8.4",
+				BuildID:            "202207192205-0",
+				RHELCoreOSPullspec: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:rhel-coreos",
+			},
+		},
+		{
+			name:        "Missing machine-os display version",
+			releaseInfo: newReleaseInfoForTest("", "rhel-coreos"),
+			expectErr:   true,
+		},
+		{
+			name:        "Version with too few dots",
+			releaseInfo: newReleaseInfoForTest("9.6", "rhel-coreos"),
+			expectErr:   true,
+		},
+		{
+			name:        "Version without dots",
+			releaseInfo: newReleaseInfoForTest("96", "rhel-coreos"),
+			expectErr:   true,
+		},
+		{
+			name:        "Non-numeric version",
+			releaseInfo: newReleaseInfoForTest("nine.six.20250701-0", "rhel-coreos"),
+			expectErr:   true,
+		},
+		{
+			name:        "Missing rhel-coreos component",
+			releaseInfo: newReleaseInfoForTest("9.6.20250701-0", "rhel-coreos-extensions"),
+			expectErr:   true,
+		},
+		{
+			name: "Missing references",
+			releaseInfo: &ReleaseInfo{
+				DisplayVersions: map[string]DisplayVersion{
+					"machine-os": {Version: "9.6.20250701-0"},
+				},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mosInfo, err := testCase.releaseInfo.GetMachineOSInfo()
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, mosInfo)
+
+			shortVersion, err := testCase.releaseInfo.GetMachineOSShortVersion()
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedShortVers, shortVersion)
+		})
+	}
+}
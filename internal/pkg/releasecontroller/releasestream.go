@@ -2,6 +2,7 @@ package releasecontroller
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"path/filepath"
 )
@@ -35,6 +36,22 @@ func (r *ReleaseStream) Tags(ctx context.Context) (*ReleaseTags, error) {
 	return out, err
 }
 
+// Finds the given tag, regardless of its phase.
+func (r *ReleaseStream) findTag(ctx context.Context, name string) (*Release, error) {
+	tags, err := r.Tags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tag := range tags.Tags {
+		if tag.Name == name {
+			return &tag, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown tag %q for release stream %q", name, r.name)
+}
+
 func (r *ReleaseStream) Latest(ctx context.Context) (*Release, error) {
 	out := &Release{}
 	err := r.rc.doHTTPRequestIntoStruct(ctx, filepath.Join("/api/v1/releasestream", r.name, "latest"), nil, out)
@@ -0,0 +1,300 @@
+package releasecontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/containers"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/coreos/go-semver/semver"
+)
+
+// ResolvedRelease is the canonical description of a release, regardless of
+// how it was originally referred to.
+type ResolvedRelease struct {
+	// Input is what was resolved.
+	Input string `json:"input"`
+	// Kind is how the input was interpreted.
+	Kind VersionKind `json:"kind"`
+	// Tag is the release tag name, e.g., 4.18.0-0.nightly-2025-01-01-123456.
+	Tag string `json:"tag,omitempty"`
+	// Stream is the release stream the tag belongs to.
+	Stream string `json:"stream,omitempty"`
+	// Controller is the hostname of the release controller the tag was found on.
+	Controller string `json:"controller,omitempty"`
+	// Phase is the phase of the release tag, e.g., Accepted.
+	Phase string `json:"phase,omitempty"`
+	// TaggedPullspec is the pullspec of the release payload by tag, if known.
+	TaggedPullspec string `json:"taggedPullspec,omitempty"`
+	// DigestedPullspec is the pullspec of the release payload by digest.
+	DigestedPullspec string `json:"digestedPullspec"`
+}
+
+// ResolveOpts holds the options for resolving a release.
+type ResolveOpts struct {
+	// Controllers are the release controllers to search, in order of
+	// preference. All known release controllers are searched if empty.
+	Controllers []*ReleaseController
+	// AuthfilePath is the path to a registry auth file used to resolve
+	// pullspecs to their digests.
+	AuthfilePath string
+}
+
+type resolver struct {
+	controllers    []*ReleaseController
+	authfilePath   string
+	resolveDigest  func(pullspec, authfilePath string) (string, error)
+	getReleaseInfo func(ctx context.Context, pullspec, authfilePath string) (*ReleaseInfo, error)
+}
+
+// Resolve resolves a release given in any of the following forms into its
+// canonical description:
+//
+// - A major and minor version (e.g., 4.18), meaning the latest accepted
+// z-stream release.
+// - A release stream name (e.g., 4.18.0-0.nightly), meaning the latest accepted
+// release in that stream.
+// - A release tag (e.g., 4.18.0-0.nightly-2025-01-01-123456 or 4.18.3).
+// - A tagged or digested release pullspec.
+func Resolve(ctx context.Context, in string, opts ResolveOpts) (*ResolvedRelease, error) {
+	return newResolver(opts).resolve(ctx, in)
+}
+
+func newResolver(opts ResolveOpts) *resolver {
+	controllers := opts.Controllers
+	if len(controllers) == 0 {
+		controllers = All()
+	}
+
+	return &resolver{
+		controllers:    controllers,
+		authfilePath:   opts.AuthfilePath,
+		resolveDigest:  containers.ResolveToDigestedPullspec,
+		getReleaseInfo: GetReleaseInfoWithAuthfile,
+	}
+}
+
+func (r *resolver) resolve(ctx context.Context, in string) (*ResolvedRelease, error) {
+	vk, err := GetVersionKind(in)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve %q: %w", in, err)
+	}
+
+	var out *ResolvedRelease
+
+	switch vk {
+	case PullspecVersionKind:
+		out, err = r.resolvePullspec(ctx, in)
+	case SemverVersionKind:
+		out, err = r.resolveTag(ctx, in)
+	case ReleaseStreamVersionKind:
+		out, err = r.resolveReleaseStream(ctx, in)
+	case MinorVersionKind:
+		out, err = r.resolveMinorVersion(ctx, in)
+	default:
+		err = fmt.Errorf("unknown version kind %q", vk)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve %q: %w", in, err)
+	}
+
+	out.Input = in
+	out.Kind = vk
+
+	if out.DigestedPullspec != "" {
+		return out, nil
+	}
+
+	digested, err := r.resolveDigest(out.TaggedPullspec, r.authfilePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve %q to a digest: %w", out.TaggedPullspec, err)
+	}
+
+	out.DigestedPullspec = digested
+
+	return out, nil
+}
+
+// Resolves a pullspec to its digest. The release controller the pullspec
+// came from is identified on a best-effort basis since doing so requires
+// reading the release payload, which is not always possible (e.g., when the
+// payload has been garbage-collected). It is only used when the tag on the
+// release controller still refers to the same payload; otherwise, the tag
+// describes a different payload than the one that was given.
+func (r *resolver) resolvePullspec(ctx context.Context, in string) (*ResolvedRelease, error) {
+	if identified, err := r.identify(ctx, in); err == nil && identified.TagMatchesDigest {
+		return &identified.ResolvedRelease, nil
+	}
+
+	named, err := reference.ParseNormalizedNamed(in)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &ResolvedRelease{}
+
+	if _, ok := named.(reference.Digested); ok {
+		out.DigestedPullspec = in
+	}
+
+	if _, ok := named.(reference.Tagged); ok {
+		out.TaggedPullspec = in
+	}
+
+	return out, nil
+}
+
+// Searches each release controller for the given tag and returns the first
+// match.
+func (r *resolver) resolveTag(ctx context.Context, tag string) (*ResolvedRelease, error) {
+	found, err := r.findTag(ctx, tag, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return found[0], nil
+}
+
+// Searches each release controller for the given tag and returns the matches
+// in release controller order, stopping at the first one if firstOnly is true.
+// The same tag name may exist on more than one release controller, e.g.,
+// 4.18.3 on both the amd64 and arm64 release controllers.
+func (r *resolver) findTag(ctx context.Context, tag string, firstOnly bool) ([]*ResolvedRelease, error) {
+	errs := []error{}
+	out := []*ResolvedRelease{}
+
+	for _, rc := range r.controllers {
+		stream, _, err := rc.ReleaseStreams().FindReleaseNameAndStream(ctx, tag)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", rc, err))
+			continue
+		}
+
+		release, err := rc.ReleaseStream(stream).findTag(ctx, tag)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, newResolvedRelease(rc, stream, release))
+
+		if firstOnly {
+			break
+		}
+	}
+
+	if len(out) == 0 {
+		return nil, fmt.Errorf("tag %q not found on any release controller: %w", tag, errors.Join(errs...))
+	}
+
+	return out, nil
+}
+
+// Finds the first release controller which has the given release stream and
+// gets the latest accepted release from it.
+func (r *resolver) resolveReleaseStream(ctx context.Context, stream string) (*ResolvedRelease, error) {
+	errs := []error{}
+
+	for _, rc := range r.controllers {
+		streams, err := rc.ReleaseStreams().All(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", rc, err))
+			continue
+		}
+
+		if _, ok := streams[stream]; !ok {
+			continue
+		}
+
+		release, err := rc.ReleaseStream(stream).Latest(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not get latest release for stream %q from %s: %w", stream, rc, err)
+		}
+
+		return newResolvedRelease(rc, stream, release), nil
+	}
+
+	if len(errs) != 0 {
+		return nil, fmt.Errorf("release stream not found on any release controller: %w", errors.Join(errs...))
+	}
+
+	return nil, fmt.Errorf("release stream not found on any release controller")
+}
+
+// Finds the latest accepted z-stream release for the given major and minor
+// version within the stable release streams (e.g., 4-stable).
+func (r *resolver) resolveMinorVersion(ctx context.Context, in string) (*ResolvedRelease, error) {
+	minor, err := semver.NewVersion(strings.TrimPrefix(in, "v") + ".0")
+	if err != nil {
+		return nil, err
+	}
+
+	stablePrefix := fmt.Sprintf("%d-stable", minor.Major)
+
+	errs := []error{}
+
+	for _, rc := range r.controllers {
+		accepted, err := rc.ReleaseStreams().Accepted(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", rc, err))
+			continue
+		}
+
+		var latest *semver.Version
+		latestStream := ""
+		latestTag := ""
+
+		for stream, tags := range accepted {
+			if !strings.HasPrefix(stream, stablePrefix) {
+				continue
+			}
+
+			for _, tag := range tags {
+				ver, err := semver.NewVersion(tag)
+				if err != nil {
+					continue
+				}
+
+				// Only GA z-stream releases are considered.
+				if ver.Major != minor.Major || ver.Minor != minor.Minor || ver.PreRelease != "" {
+					continue
+				}
+
+				if latest == nil || latest.LessThan(*ver) {
+					latest = ver
+					latestStream = stream
+					latestTag = tag
+				}
+			}
+		}
+
+		if latest == nil {
+			continue
+		}
+
+		release, err := rc.ReleaseStream(latestStream).findTag(ctx, latestTag)
+		if err != nil {
+			return nil, err
+		}
+
+		return newResolvedRelease(rc, latestStream, release), nil
+	}
+
+	if len(errs) != 0 {
+		return nil, fmt.Errorf("no accepted z-stream release found: %w", errors.Join(errs...))
+	}
+
+	return nil, fmt.Errorf("no accepted z-stream release found")
+}
+
+func newResolvedRelease(rc *ReleaseController, stream string, release *Release) *ResolvedRelease {
+	return &ResolvedRelease{
+		Tag:            release.Name,
+		Stream:         stream,
+		Controller:     rc.Host(),
+		Phase:          release.Phase,
+		TaggedPullspec: release.Pullspec,
+	}
+}
@@ -0,0 +1,162 @@
+package releasecontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const resolvedDigest string = "sha256:aa6cd007e204673ceafa266fe1cf359b386cbb1e34c785ae2dd1856e8f61b71c"
+
+// Resolves tagged pullspecs to a fixed digest without talking to a registry.
+func fakeResolveDigest(pullspec, _ string) (string, error) {
+	repo, _, ok := strings.Cut(pullspec, ":")
+	if !ok {
+		return "", fmt.Errorf("pullspec %q is not tagged", pullspec)
+	}
+
+	return repo + "@" + resolvedDigest, nil
+}
+
+func newFakeReleaseControllerForResolver() *fakeReleaseController {
+	f := newFakeReleaseController()
+
+	for _, tag := range []Release{
+		{Name: "4.18.0-0.nightly-2025-01-02-123456", Phase: string(PhaseRejected)},
+		{Name: "4.18.0-0.nightly-2025-01-01-123456", Phase: string(PhaseAccepted)},
+	} {
+		tag.Pullspec = "registry.ci.openshift.org/ocp/release:" + tag.Name
+		f.addTag("4.18.0-0.nightly", tag, nil)
+	}
+
+	for _, tag := range []Release{
+		{Name: "4.19.0-rc.1", Phase: string(PhaseAccepted)},
+		{Name: "4.18.10", Phase: string(PhaseAccepted)},
+		{Name: "4.18.9", Phase: string(PhaseAccepted)},
+		{Name: "4.18.11", Phase: string(PhaseRejected)},
+		{Name: "4.17.20", Phase: string(PhaseAccepted)},
+	} {
+		tag.Pullspec = "quay.io/openshift-release-dev/ocp-release:" + tag.Name + "-x86_64"
+		f.addTag("4-stable", tag, nil)
+	}
+
+	return f
+}
+
+func TestResolve(t *testing.T) {
+	rc := newFakeReleaseControllerForResolver().start(t)
+
+	// A second release controller which does not have any of the releases
+	// should be skipped over.
+	emptyRC := newFakeReleaseController().start(t)
+
+	testCases := []struct {
+		name      string
+		input     string
+		expected  *ResolvedRelease
+		expectErr bool
+	}{
+		{
+			name:  "Minor version",
+			input: "4.18",
+			expected: &ResolvedRelease{
+				Kind:             MinorVersionKind,
+				Tag:              "4.18.10",
+				Stream:           "4-stable",
+				Phase:            string(PhaseAccepted),
+				TaggedPullspec:   "quay.io/openshift-release-dev/ocp-release:4.18.10-x86_64",
+				DigestedPullspec: "quay.io/openshift-release-dev/ocp-release@" + resolvedDigest,
+			},
+		},
+		{
+			name:      "Minor version without GA releases",
+			input:     "4.19",
+			expectErr: true,
+		},
+		{
+			name:  "Release stream",
+			input: "4.18.0-0.nightly",
+			expected: &ResolvedRelease{
+				Kind:             ReleaseStreamVersionKind,
+				Tag:              "4.18.0-0.nightly-2025-01-01-123456",
+				Stream:           "4.18.0-0.nightly",
+				Phase:            string(PhaseAccepted),
+				TaggedPullspec:   "registry.ci.openshift.org/ocp/release:4.18.0-0.nightly-2025-01-01-123456",
+				DigestedPullspec: "registry.ci.openshift.org/ocp/release@" + resolvedDigest,
+			},
+		},
+		{
+			name:      "Unknown release stream",
+			input:     "4.99.0-0.nightly",
+			expectErr: true,
+		},
+		{
+			name:  "Rejected release tag",
+			input: "4.18.0-0.nightly-2025-01-02-123456",
+			expected: &ResolvedRelease{
+				Kind:             SemverVersionKind,
+				Tag:              "4.18.0-0.nightly-2025-01-02-123456",
+				Stream:           "4.18.0-0.nightly",
+				Phase:            string(PhaseRejected),
+				TaggedPullspec:   "registry.ci.openshift.org/ocp/release:4.18.0-0.nightly-2025-01-02-123456",
+				DigestedPullspec: "registry.ci.openshift.org/ocp/release@" + resolvedDigest,
+			},
+		},
+		{
+			name:      "Unknown release tag",
+			input:     "4.18.99",
+			expectErr: true,
+		},
+		{
+			name:  "Tagged pullspec",
+			input: "quay.io/openshift-release-dev/ocp-release:4.18.10-x86_64",
+			expected: &ResolvedRelease{
+				Kind:             PullspecVersionKind,
+				TaggedPullspec:   "quay.io/openshift-release-dev/ocp-release:4.18.10-x86_64",
+				DigestedPullspec: "quay.io/openshift-release-dev/ocp-release@" + resolvedDigest,
+			},
+		},
+		{
+			name:  "Digested pullspec",
+			input: "quay.io/openshift-release-dev/ocp-release@" + resolvedDigest,
+			expected: &ResolvedRelease{
+				Kind:             PullspecVersionKind,
+				DigestedPullspec: "quay.io/openshift-release-dev/ocp-release@" + resolvedDigest,
+			},
+		},
+		{
+			name:      "Invalid",
+			input:     "invalid",
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := newResolver(ResolveOpts{Controllers: []*ReleaseController{emptyRC, rc}})
+			r.resolveDigest = fakeResolveDigest
+			r.getReleaseInfo = func(_ context.Context, pullspec, _ string) (*ReleaseInfo, error) {
+				return nil, fmt.Errorf("could not read %q", pullspec)
+			}
+
+			result, err := r.resolve(context.Background(), testCase.input)
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			testCase.expected.Input = testCase.input
+			if testCase.expected.Tag != "" {
+				testCase.expected.Controller = rc.Host()
+			}
+
+			assert.Equal(t, testCase.expected, result)
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package releasecontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ComponentSize is the compressed size of a single component image.
+type ComponentSize struct {
+	Name string `json:"name"`
+	// Layers is the number of layers in the component image.
+	Layers int `json:"layers"`
+	// Bytes is the sum of the compressed sizes of the component image layers.
+	Bytes int64 `json:"bytes"`
+	// UniqueBytes is the sum of the compressed sizes of the layers which no
+	// other component image shares.
+	UniqueBytes int64 `json:"uniqueBytes"`
+}
+
+// PayloadSize describes the compressed size of the component images within a
+// release payload.
+type PayloadSize struct {
+	Release string `json:"release"`
+	// Components are sorted largest first.
+	Components []ComponentSize `json:"components"`
+	// Layers is the number of distinct layers across all component images.
+	Layers int `json:"layers"`
+	// TotalBytes is the sum of the sizes of every component image, counting
+	// shared layers once per component.
+	TotalBytes int64 `json:"totalBytes"`
+	// UniqueBytes is the sum of the sizes of every distinct layer, which is
+	// roughly how much must be pulled to mirror the payload.
+	UniqueBytes int64 `json:"uniqueBytes"`
+}
+
+// ComponentSizeDiff describes how the size of a component image changed.
+type ComponentSizeDiff struct {
+	Name      string `json:"name"`
+	FromBytes int64  `json:"fromBytes"`
+	ToBytes   int64  `json:"toBytes"`
+	// Delta is positive when the component image grew.
+	Delta int64 `json:"delta"`
+}
+
+// PayloadSizeDiff describes how the size of a release payload changed.
+type PayloadSizeDiff struct {
+	From             string `json:"from"`
+	To               string `json:"to"`
+	FromUniqueBytes  int64  `json:"fromUniqueBytes"`
+	ToUniqueBytes    int64  `json:"toUniqueBytes"`
+	UniqueBytesDelta int64  `json:"uniqueBytesDelta"`
+	// Components are the component images whose size changed, sorted by the
+	// largest growth first so that regressions come first.
+	Components []ComponentSizeDiff `json:"components"`
+}
+
+type layerData struct {
+	Digest string `json:"Digest"`
+	Size   int64  `json:"Size"`
+}
+
+// The subset of the skopeo inspect output needed to determine image sizes.
+type inspectedLayers struct {
+	LayersData []layerData `json:"LayersData"`
+}
+
+// GetPayloadSize determines the compressed size of each component image in
+// the given release tag or pullspec from its image manifest, de-duplicating
+// layers which are shared between component images. This requires oc and
+// skopeo.
+func (r *releaseInfoFetcher) GetPayloadSize(ctx context.Context, tagOrPullspec string) (*PayloadSize, error) {
+	ri, err := r.GetReleaseInfo(ctx, tagOrPullspec)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := r.fetchAllComponentMetadata(ctx, ri, []string{})
+	if err != nil {
+		return nil, err
+	}
+
+	layers := map[string][]layerData{}
+
+	for _, cim := range metadata {
+		inspected := &inspectedLayers{}
+		if err := json.Unmarshal(cim.data, inspected); err != nil {
+			return nil, fmt.Errorf("could not parse metadata for component %s: %w", cim.name, err)
+		}
+
+		layers[cim.name] = inspected.LayersData
+	}
+
+	out := computePayloadSize(layers)
+	out.Release = tagOrPullspec
+
+	return out, nil
+}
+
+func computePayloadSize(layersByComponent map[string][]layerData) *PayloadSize {
+	// Layer digest -> number of component images which have it.
+	refCounts := map[string]int{}
+	sizes := map[string]int64{}
+
+	for _, layers := range layersByComponent {
+		seen := map[string]bool{}
+		for _, layer := range layers {
+			if seen[layer.Digest] {
+				continue
+			}
+
+			seen[layer.Digest] = true
+			refCounts[layer.Digest]++
+			sizes[layer.Digest] = layer.Size
+		}
+	}
+
+	out := &PayloadSize{
+		Components: []ComponentSize{},
+		Layers:     len(sizes),
+	}
+
+	for _, size := range sizes {
+		out.UniqueBytes += size
+	}
+
+	for name, layers := range layersByComponent {
+		cs := ComponentSize{Name: name, Layers: len(layers)}
+
+		for _, layer := range layers {
+			cs.Bytes += layer.Size
+			if refCounts[layer.Digest] == 1 {
+				cs.UniqueBytes += layer.Size
+			}
+		}
+
+		out.TotalBytes += cs.Bytes
+		out.Components = append(out.Components, cs)
+	}
+
+	sort.Slice(out.Components, func(i, j int) bool {
+		if out.Components[i].Bytes != out.Components[j].Bytes {
+			return out.Components[i].Bytes > out.Components[j].Bytes
+		}
+
+		return out.Components[i].Name < out.Components[j].Name
+	})
+
+	return out
+}
+
+// DiffPayloadSizes returns the component images whose size changed between
+// the two release payloads. Components which were added or removed are
+// included with a size of zero on the side they are missing from.
+func DiffPayloadSizes(from, to *PayloadSize) *PayloadSizeDiff {
+	out := &PayloadSizeDiff{
+		From:             from.Release,
+		To:               to.Release,
+		FromUniqueBytes:  from.UniqueBytes,
+		ToUniqueBytes:    to.UniqueBytes,
+		UniqueBytesDelta: to.UniqueBytes - from.UniqueBytes,
+		Components:       []ComponentSizeDiff{},
+	}
+
+	byName := map[string]*ComponentSizeDiff{}
+
+	for _, cs := range from.Components {
+		byName[cs.Name] = &ComponentSizeDiff{Name: cs.Name, FromBytes: cs.Bytes}
+	}
+
+	for _, cs := range to.Components {
+		if _, ok := byName[cs.Name]; !ok {
+			byName[cs.Name] = &ComponentSizeDiff{Name: cs.Name}
+		}
+
+		byName[cs.Name].ToBytes = cs.Bytes
+	}
+
+	for _, diff := range byName {
+		diff.Delta = diff.ToBytes - diff.FromBytes
+		if diff.Delta != 0 {
+			out.Components = append(out.Components, *diff)
+		}
+	}
+
+	sort.Slice(out.Components, func(i, j int) bool {
+		if out.Components[i].Delta != out.Components[j].Delta {
+			return out.Components[i].Delta > out.Components[j].Delta
+		}
+
+		return out.Components[i].Name < out.Components[j].Name
+	})
+
+	return out
+}
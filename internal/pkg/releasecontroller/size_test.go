@@ -0,0 +1,67 @@
+package releasecontroller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputePayloadSize(t *testing.T) {
+	base := layerData{Digest: "sha256:base", Size: 100}
+
+	size := computePayloadSize(map[string][]layerData{
+		"machine-config-operator":  {base, {Digest: "sha256:mco", Size: 30}},
+		"cluster-version-operator": {base, {Digest: "sha256:cvo", Size: 10}},
+		// A layer repeated within the same image is only shared with itself.
+		"rhel-coreos": {{Digest: "sha256:rhcos", Size: 500}, {Digest: "sha256:rhcos", Size: 500}},
+	})
+
+	assert.Equal(t, &PayloadSize{
+		Components: []ComponentSize{
+			{Name: "rhel-coreos", Layers: 2, Bytes: 1000, UniqueBytes: 1000},
+			{Name: "machine-config-operator", Layers: 2, Bytes: 130, UniqueBytes: 30},
+			{Name: "cluster-version-operator", Layers: 2, Bytes: 110, UniqueBytes: 10},
+		},
+		Layers:      4,
+		TotalBytes:  1240,
+		UniqueBytes: 640,
+	}, size)
+}
+
+func TestDiffPayloadSizes(t *testing.T) {
+	from := &PayloadSize{
+		Release: "4.19.0",
+		Components: []ComponentSize{
+			{Name: "machine-config-operator", Bytes: 130},
+			{Name: "cluster-version-operator", Bytes: 110},
+			{Name: "removed", Bytes: 50},
+			{Name: "unchanged", Bytes: 5},
+		},
+		UniqueBytes: 295,
+	}
+
+	to := &PayloadSize{
+		Release: "4.19.1",
+		Components: []ComponentSize{
+			{Name: "machine-config-operator", Bytes: 200},
+			{Name: "cluster-version-operator", Bytes: 100},
+			{Name: "added", Bytes: 20},
+			{Name: "unchanged", Bytes: 5},
+		},
+		UniqueBytes: 325,
+	}
+
+	assert.Equal(t, &PayloadSizeDiff{
+		From:             "4.19.0",
+		To:               "4.19.1",
+		FromUniqueBytes:  295,
+		ToUniqueBytes:    325,
+		UniqueBytesDelta: 30,
+		Components: []ComponentSizeDiff{
+			{Name: "machine-config-operator", FromBytes: 130, ToBytes: 200, Delta: 70},
+			{Name: "added", ToBytes: 20, Delta: 20},
+			{Name: "cluster-version-operator", FromBytes: 110, ToBytes: 100, Delta: -10},
+			{Name: "removed", FromBytes: 50, Delta: -50},
+		},
+	}, DiffPayloadSizes(from, to))
+}
@@ -2,6 +2,7 @@ package releasecontroller
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"unicode"
 
@@ -14,6 +15,23 @@ type VersionKind string
 const (
 	SemverVersionKind   VersionKind = "Semver"
 	PullspecVersionKind VersionKind = "Pullspec"
+	// A major and minor version (e.g., 4.18), meaning the latest z-stream.
+	MinorVersionKind VersionKind = "Minor"
+	// A release stream name (e.g., 4.18.0-0.nightly or 4-stable), meaning the
+	// latest accepted release in that stream.
+	ReleaseStreamVersionKind VersionKind = "ReleaseStream"
+)
+
+var (
+	minorVersionRegex = regexp.MustCompile(`^v?\d+\.\d+$`)
+	// Matches release stream names such as 4.18.0-0.nightly,
+	// 4.18.0-0.nightly-arm64, and 4.18.0-0.okd-scos.
+	releaseStreamRegex = regexp.MustCompile(`^\d+\.\d+\.\d+-0\.[a-z][a-z0-9-]*$`)
+	// Matches release stream names such as 4-stable and 4-dev-preview.
+	majorReleaseStreamRegex = regexp.MustCompile(`^\d+-[a-z][a-z0-9-]*$`)
+	// Tags within a release stream end with a timestamp, e.g.,
+	// 4.18.0-0.nightly-2025-01-01-123456.
+	releaseTagTimestampRegex = regexp.MustCompile(`-\d{4}-\d{2}-\d{2}-\d{6}$`)
 )
 
 func GetVersionKind(in string) (VersionKind, error) {
@@ -43,6 +61,14 @@ func getVersionKindFromInputString(in string) (VersionKind, error) {
 		return "", fmt.Errorf("does not start with a digit")
 	}
 
+	if minorVersionRegex.MatchString(in) {
+		return MinorVersionKind, nil
+	}
+
+	if isReleaseStreamName(in) {
+		return ReleaseStreamVersionKind, nil
+	}
+
 	ver, err := semver.NewVersion(in)
 	if err != nil {
 		return "", err
@@ -55,6 +81,14 @@ func getVersionKindFromInputString(in string) (VersionKind, error) {
 	return "", fmt.Errorf("unknown OCP / OKD version kind")
 }
 
+func isReleaseStreamName(in string) bool {
+	if majorReleaseStreamRegex.MatchString(in) {
+		return true
+	}
+
+	return releaseStreamRegex.MatchString(in) && !releaseTagTimestampRegex.MatchString(in)
+}
+
 func getVersionKindFromPullspec(in string) (VersionKind, error) {
 	named, err := reference.ParseNormalizedNamed(in)
 	if err != nil {
@@ -33,6 +33,51 @@ func TestGetVersionKind(t *testing.T) {
 			input:               "4.20.0-okd-scos.17",
 			expectedVersionKind: SemverVersionKind,
 		},
+		{
+			name:                "Nightly release tag",
+			input:               "4.18.0-0.nightly-2025-01-01-123456",
+			expectedVersionKind: SemverVersionKind,
+		},
+		{
+			name:                "Arch-specific nightly release tag",
+			input:               "4.18.0-0.nightly-arm64-2025-01-01-123456",
+			expectedVersionKind: SemverVersionKind,
+		},
+		{
+			name:                "Release candidate",
+			input:               "4.18.0-rc.1",
+			expectedVersionKind: SemverVersionKind,
+		},
+		{
+			name:                "Minor version",
+			input:               "4.18",
+			expectedVersionKind: MinorVersionKind,
+		},
+		{
+			name:                "Minor version with leading v",
+			input:               "v4.18",
+			expectedVersionKind: MinorVersionKind,
+		},
+		{
+			name:                "Nightly release stream",
+			input:               "4.18.0-0.nightly",
+			expectedVersionKind: ReleaseStreamVersionKind,
+		},
+		{
+			name:                "Arch-specific nightly release stream",
+			input:               "4.18.0-0.nightly-arm64",
+			expectedVersionKind: ReleaseStreamVersionKind,
+		},
+		{
+			name:                "OKD release stream",
+			input:               "4.20.0-0.okd-scos",
+			expectedVersionKind: ReleaseStreamVersionKind,
+		},
+		{
+			name:                "Stable release stream",
+			input:               "4-stable",
+			expectedVersionKind: ReleaseStreamVersionKind,
+		},
 		{
 			name:                "Tagged pullspec",
 			input:               "quay.io/org/repo:tag",
@@ -0,0 +1,124 @@
+package releasecontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultWaitInitialInterval time.Duration = 15 * time.Second
+	defaultWaitMaxInterval     time.Duration = 2 * time.Minute
+)
+
+// WaitOpts holds the options for waiting on a release tag.
+type WaitOpts struct {
+	// Phases are the phases which end the wait. Defaults to Accepted and
+	// Rejected.
+	Phases []Phase
+	// InitialInterval is how long to wait between the first polls. The
+	// interval grows by half each time up to MaxInterval.
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	// Progress, if set, is called after each poll with either the current
+	// release info or the error encountered while getting it.
+	Progress func(*APIReleaseInfo, error)
+}
+
+// JobCounts are the number of verification jobs in each state.
+type JobCounts struct {
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Pending   int `json:"pending"`
+}
+
+// Total returns the total number of verification jobs.
+func (j JobCounts) Total() int {
+	return j.Succeeded + j.Failed + j.Pending
+}
+
+func (j JobCounts) String() string {
+	return fmt.Sprintf("%d/%d succeeded, %d failed, %d pending", j.Succeeded, j.Total(), j.Failed, j.Pending)
+}
+
+// Counts returns the number of verification jobs in each state. Jobs in any
+// state other than Succeeded or Failed, as well as jobs without a status, are
+// considered pending.
+func (v VerificationStatusMap) Counts() JobCounts {
+	out := JobCounts{}
+
+	for _, status := range v {
+		if status == nil {
+			out.Pending++
+			continue
+		}
+
+		switch status.State {
+		case "Succeeded":
+			out.Succeeded++
+		case "Failed":
+			out.Failed++
+		default:
+			out.Pending++
+		}
+	}
+
+	return out
+}
+
+// WaitForTag polls the given release tag until it reaches one of the given
+// phases or the context is done. Errors encountered while polling are
+// reported via the Progress func and retried since the release controller is
+// occasionally unavailable.
+func (r *ReleaseStream) WaitForTag(ctx context.Context, tag string, opts WaitOpts) (*APIReleaseInfo, error) {
+	phases := opts.Phases
+	if len(phases) == 0 {
+		phases = []Phase{PhaseAccepted, PhaseRejected}
+	}
+
+	interval := opts.InitialInterval
+	if interval == 0 {
+		interval = defaultWaitInitialInterval
+	}
+
+	maxInterval := opts.MaxInterval
+	if maxInterval == 0 {
+		maxInterval = defaultWaitMaxInterval
+	}
+
+	for {
+		info, err := r.Tag(ctx, tag)
+		if err != nil {
+			info = nil
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(info, err)
+		}
+
+		if err == nil && isOneOfPhases(info.Phase, phases) {
+			return info, nil
+		}
+
+		timer := time.NewTimer(interval)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("release tag %q did not reach phase(s) %v: %w", tag, phases, ctx.Err())
+		case <-timer.C:
+		}
+
+		interval = min(interval+interval/2, maxInterval)
+	}
+}
+
+func isOneOfPhases(phase string, phases []Phase) bool {
+	for _, p := range phases {
+		if phase == string(p) {
+			return true
+		}
+	}
+
+	return false
+}
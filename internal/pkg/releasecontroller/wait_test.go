@@ -0,0 +1,154 @@
+package releasecontroller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Serves the given sequence of release tag states, one per request. The last
+// state is repeated once the sequence is exhausted. A nil state results in an
+// HTTP 500.
+func newSequencedReleaseController(t *testing.T, states []*APIReleaseInfo) *ReleaseController {
+	t.Helper()
+
+	mu := sync.Mutex{}
+	i := 0
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		state := states[min(i, len(states)-1)]
+		i++
+		mu.Unlock()
+
+		if state == nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		writeJSONOrNotFound(w, state)
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return New(srv.Listener.Addr().String(), &ReleaseControllerConfig{Client: srv.Client()})
+}
+
+func newAPIReleaseInfo(phase Phase, blocking ...string) *APIReleaseInfo {
+	jobs := VerificationStatusMap{}
+	for i, state := range blocking {
+		jobs[string(rune('a'+i))] = &VerificationStatus{State: state}
+	}
+
+	return &APIReleaseInfo{
+		Name:    "4.18.0-0.nightly-2025-01-01-123456",
+		Phase:   string(phase),
+		Results: &VerificationJobsSummary{BlockingJobs: jobs},
+	}
+}
+
+func TestWaitForTag(t *testing.T) {
+	testCases := []struct {
+		name          string
+		states        []*APIReleaseInfo
+		phases        []Phase
+		expectedPhase Phase
+		expectedPolls int
+		expectErr     bool
+	}{
+		{
+			name: "Accepted",
+			states: []*APIReleaseInfo{
+				newAPIReleaseInfo(PhaseReady, "Pending", "Pending"),
+				newAPIReleaseInfo(PhaseReady, "Succeeded", "Pending"),
+				newAPIReleaseInfo(PhaseAccepted, "Succeeded", "Succeeded"),
+			},
+			expectedPhase: PhaseAccepted,
+			expectedPolls: 3,
+		},
+		{
+			name: "Rejected",
+			states: []*APIReleaseInfo{
+				newAPIReleaseInfo(PhaseReady, "Pending"),
+				newAPIReleaseInfo(PhaseRejected, "Failed"),
+			},
+			expectedPhase: PhaseRejected,
+			expectedPolls: 2,
+		},
+		{
+			name: "Errors are retried",
+			states: []*APIReleaseInfo{
+				nil,
+				newAPIReleaseInfo(PhaseAccepted, "Succeeded"),
+			},
+			expectedPhase: PhaseAccepted,
+			expectedPolls: 2,
+		},
+		{
+			name: "Waits past other terminal phases",
+			states: []*APIReleaseInfo{
+				newAPIReleaseInfo(PhaseRejected, "Failed"),
+				newAPIReleaseInfo(PhaseAccepted, "Failed"),
+			},
+			phases:        []Phase{PhaseAccepted},
+			expectedPhase: PhaseAccepted,
+			expectedPolls: 2,
+		},
+		{
+			name: "Times out",
+			states: []*APIReleaseInfo{
+				newAPIReleaseInfo(PhaseReady, "Pending"),
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+			defer cancel()
+
+			rc := newSequencedReleaseController(t, testCase.states)
+
+			polls := 0
+
+			opts := WaitOpts{
+				Phases:          testCase.phases,
+				InitialInterval: time.Millisecond,
+				MaxInterval:     10 * time.Millisecond,
+				Progress: func(info *APIReleaseInfo, err error) {
+					polls++
+					assert.True(t, (info == nil) != (err == nil))
+				},
+			}
+
+			info, err := rc.ReleaseStream("4.18.0-0.nightly").WaitForTag(ctx, "4.18.0-0.nightly-2025-01-01-123456", opts)
+			if testCase.expectErr {
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, string(testCase.expectedPhase), info.Phase)
+			assert.Equal(t, testCase.expectedPolls, polls)
+		})
+	}
+}
+
+func TestJobCounts(t *testing.T) {
+	jobs := newAPIReleaseInfo(PhaseReady, "Succeeded", "Failed", "Pending", "Unknown").Results.BlockingJobs
+	// Jobs without a status are pending.
+	jobs["no-status"] = nil
+
+	counts := jobs.Counts()
+
+	assert.Equal(t, JobCounts{Succeeded: 1, Failed: 1, Pending: 3}, counts)
+	assert.Equal(t, 5, counts.Total())
+	assert.Equal(t, "1/5 succeeded, 1 failed, 3 pending", counts.String())
+}
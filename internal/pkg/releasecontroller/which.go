@@ -0,0 +1,205 @@
+package releasecontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/opencontainers/go-digest"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/klog"
+)
+
+// ImageMatch describes a release tag whose payload references a given image.
+type ImageMatch struct {
+	Stream    string `json:"stream"`
+	Tag       string `json:"tag"`
+	Phase     string `json:"phase"`
+	Component string `json:"component"`
+	Image     string `json:"image"`
+}
+
+// FindImageOpts controls which release tags are scanned by
+// FindReleasesContainingImage.
+type FindImageOpts struct {
+	// Streams are the release streams to scan. If empty, all release streams
+	// on the release controller are scanned.
+	Streams []string
+	// Last is the number of most recent tags to scan in each release stream.
+	Last int
+	// Concurrency is the maximum number of release info lookups that may be
+	// in-flight at once.
+	Concurrency int
+}
+
+// ParseImageDigest returns the digest for the given input, which may either be
+// a bare digest (sha256:...) or a digested image pullspec.
+func ParseImageDigest(in string) (digest.Digest, error) {
+	if d, err := digest.Parse(in); err == nil {
+		return d, nil
+	}
+
+	named, err := reference.ParseNormalizedNamed(in)
+	if err != nil {
+		return "", fmt.Errorf("could not parse %q as a digest or image pullspec: %w", in, err)
+	}
+
+	digested, ok := named.(reference.Digested)
+	if !ok {
+		return "", fmt.Errorf("image pullspec %q does not have a digest", in)
+	}
+
+	return digested.Digest(), nil
+}
+
+// FindReleasesContainingImage scans the most recent release tags in the
+// requested release streams and returns every tag whose payload references an
+// image with the given digest, along with the name of the component. Tags whose
+// release info cannot be retrieved (e.g., because they were garbage-collected)
+// are skipped, unless the release info could not be retrieved for any of them.
+func (r *ReleaseController) FindReleasesContainingImage(ctx context.Context, d digest.Digest, opts FindImageOpts) ([]ImageMatch, error) {
+	tagsToScan, err := r.getTagsToScan(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &errgroup.Group{}
+	if opts.Concurrency > 0 {
+		g.SetLimit(opts.Concurrency)
+	}
+
+	mu := &sync.Mutex{}
+	out := []ImageMatch{}
+	errs := make([]error, len(tagsToScan))
+
+	for i, st := range tagsToScan {
+		g.Go(func() error {
+			ri, err := r.GetReleaseInfo(ctx, st.tag.Name)
+			if err != nil {
+				errs[i] = err
+				return nil
+			}
+
+			matches := findImageInReleaseInfo(ri, d)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			for _, match := range matches {
+				match.Stream = st.stream
+				match.Tag = st.tag.Name
+				match.Phase = st.tag.Phase
+				out = append(out, match)
+			}
+
+			return nil
+		})
+	}
+
+	// Errors are recorded per tag so that one missing tag does not fail the
+	// whole scan.
+	_ = g.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	scanned := 0
+
+	for i, st := range tagsToScan {
+		if errs[i] != nil {
+			klog.Warningf("Could not get release info for release tag %q, skipping: %s", st.tag.Name, errs[i])
+			continue
+		}
+
+		scanned++
+	}
+
+	// Otherwise, an unreachable release controller would look the same as no
+	// release containing the image.
+	if scanned == 0 && len(tagsToScan) != 0 {
+		return nil, fmt.Errorf("could not get release info for any tags: %w", errors.Join(errs...))
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Stream != out[j].Stream {
+			return out[i].Stream < out[j].Stream
+		}
+
+		if out[i].Tag != out[j].Tag {
+			return out[i].Tag > out[j].Tag
+		}
+
+		return out[i].Component < out[j].Component
+	})
+
+	return out, nil
+}
+
+type streamTag struct {
+	stream string
+	tag    Release
+}
+
+func (r *ReleaseController) getTagsToScan(ctx context.Context, opts FindImageOpts) ([]streamTag, error) {
+	streams := opts.Streams
+	if len(streams) == 0 {
+		all, err := r.ReleaseStreams().All(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for stream := range all {
+			streams = append(streams, stream)
+		}
+
+		sort.Strings(streams)
+	}
+
+	out := []streamTag{}
+
+	for _, stream := range streams {
+		tags, err := r.ReleaseStream(stream).Tags(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not get tags for release stream %q: %w", stream, err)
+		}
+
+		// The release controller returns tags in newest-first order.
+		for i, tag := range tags.Tags {
+			if opts.Last > 0 && i >= opts.Last {
+				break
+			}
+
+			out = append(out, streamTag{stream: stream, tag: tag})
+		}
+	}
+
+	return out, nil
+}
+
+func findImageInReleaseInfo(ri *ReleaseInfo, d digest.Digest) []ImageMatch {
+	out := []ImageMatch{}
+
+	if ri.References == nil {
+		return out
+	}
+
+	for _, tag := range ri.References.Spec.Tags {
+		if tag.From == nil {
+			continue
+		}
+
+		if strings.HasSuffix(tag.From.Name, "@"+d.String()) {
+			out = append(out, ImageMatch{
+				Component: tag.Name,
+				Image:     tag.From.Name,
+			})
+		}
+	}
+
+	return out
+}
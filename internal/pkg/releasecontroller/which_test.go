@@ -0,0 +1,162 @@
+package releasecontroller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	mcoDigestOne   = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	mcoDigestTwo   = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	rhcosDigestOne = "sha256:3333333333333333333333333333333333333333333333333333333333333333"
+)
+
+func TestParseImageDigest(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     string
+		expected  digest.Digest
+		expectErr bool
+	}{
+		{
+			name:     "Bare digest",
+			input:    mcoDigestOne,
+			expected: mcoDigestOne,
+		},
+		{
+			name:     "Digested pullspec",
+			input:    "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + mcoDigestOne,
+			expected: mcoDigestOne,
+		},
+		{
+			name:      "Tagged pullspec",
+			input:     "quay.io/openshift-release-dev/ocp-release:4.21.4-x86_64",
+			expectErr: true,
+		},
+		{
+			name:      "Invalid",
+			input:     "sha256:invalid",
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			d, err := ParseImageDigest(testCase.input)
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, d)
+		})
+	}
+}
+
+func TestFindReleasesContainingImage(t *testing.T) {
+	fake := newFakeReleaseController()
+
+	tags := []struct {
+		stream string
+		tag    Release
+		mco    string
+	}{
+		{
+			stream: "4.21.0-0.nightly",
+			tag:    Release{Name: "4.21.0-0.nightly-2026-03-03-000000", Phase: "Accepted"},
+			mco:    mcoDigestTwo,
+		},
+		{
+			stream: "4.21.0-0.nightly",
+			tag:    Release{Name: "4.21.0-0.nightly-2026-03-02-000000", Phase: "Rejected"},
+			mco:    mcoDigestOne,
+		},
+		{
+			stream: "4.21.0-0.nightly",
+			tag:    Release{Name: "4.21.0-0.nightly-2026-03-01-000000", Phase: "Accepted"},
+			mco:    mcoDigestOne,
+		},
+		{
+			stream: "4.21.0-0.ci",
+			tag:    Release{Name: "4.21.0-0.ci-2026-03-01-000000", Phase: "Accepted"},
+			mco:    mcoDigestOne,
+		},
+	}
+
+	for _, tag := range tags {
+		fake.addTag(tag.stream, tag.tag, newReleaseInfoWithComponents(tag.tag.Name, map[string]string{
+			"machine-config-operator": "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + tag.mco,
+			"rhel-coreos":             "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + rhcosDigestOne,
+		}))
+	}
+
+	// A garbage-collected tag whose release info can no longer be retrieved.
+	fake.addTag("4.21.0-0.ci", Release{Name: "4.21.0-0.ci-2026-02-28-000000", Phase: "Accepted"}, nil)
+
+	rc := fake.start(t)
+
+	t.Run("All streams", func(t *testing.T) {
+		matches, err := rc.FindReleasesContainingImage(context.Background(), mcoDigestOne, FindImageOpts{Concurrency: 2})
+		require.NoError(t, err)
+
+		assert.Equal(t, []ImageMatch{
+			{
+				Stream:    "4.21.0-0.ci",
+				Tag:       "4.21.0-0.ci-2026-03-01-000000",
+				Phase:     "Accepted",
+				Component: "machine-config-operator",
+				Image:     "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + mcoDigestOne,
+			},
+			{
+				Stream:    "4.21.0-0.nightly",
+				Tag:       "4.21.0-0.nightly-2026-03-02-000000",
+				Phase:     "Rejected",
+				Component: "machine-config-operator",
+				Image:     "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + mcoDigestOne,
+			},
+			{
+				Stream:    "4.21.0-0.nightly",
+				Tag:       "4.21.0-0.nightly-2026-03-01-000000",
+				Phase:     "Accepted",
+				Component: "machine-config-operator",
+				Image:     "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + mcoDigestOne,
+			},
+		}, matches)
+	})
+
+	t.Run("Limited to last tag in a single stream", func(t *testing.T) {
+		matches, err := rc.FindReleasesContainingImage(context.Background(), mcoDigestOne, FindImageOpts{
+			Streams: []string{"4.21.0-0.nightly"},
+			Last:    1,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+
+	t.Run("Image shared by every tag", func(t *testing.T) {
+		matches, err := rc.FindReleasesContainingImage(context.Background(), rhcosDigestOne, FindImageOpts{})
+		require.NoError(t, err)
+		assert.Len(t, matches, 4)
+	})
+
+	t.Run("No release info for any tag", func(t *testing.T) {
+		fake.addTag("4.22.0-0.nightly", Release{Name: "4.22.0-0.nightly-2026-03-01-000000", Phase: "Accepted"}, nil)
+
+		_, err := rc.FindReleasesContainingImage(context.Background(), rhcosDigestOne, FindImageOpts{
+			Streams: []string{"4.22.0-0.nightly"},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown stream", func(t *testing.T) {
+		_, err := rc.FindReleasesContainingImage(context.Background(), rhcosDigestOne, FindImageOpts{
+			Streams: []string{"unknown"},
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,304 @@
+package report
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"golang.org/x/sync/errgroup"
+)
+
+const defaultTags int = 10
+
+//go:embed templates
+var templatesFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"lastAccepted": lastAccepted,
+	"lower":        strings.ToLower,
+	"pageName":     pageName,
+	"timestamp": func(t time.Time) string {
+		return t.UTC().Format(time.RFC3339)
+	},
+}).ParseFS(templatesFS, "templates/*.tmpl"))
+
+// Characters which are not safe to use in a filename.
+var unsafeFilenameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// Opts holds the options for collecting the data for a report.
+type Opts struct {
+	// Streams are the release streams to report on.
+	Streams []string
+	// Tags is the number of most recent release tags to include for each
+	// release stream. Defaults to 10.
+	Tags int
+	// Concurrency is the maximum number of release tags to get the
+	// verification results for at once.
+	Concurrency int
+	// Now is when the report was generated. Defaults to the current time.
+	Now time.Time
+}
+
+// Site is the data a report is rendered from.
+type Site struct {
+	Controller  string    `json:"controller"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	Streams     []Stream  `json:"streams"`
+}
+
+// Stream is a release stream and its most recent release tags.
+type Stream struct {
+	Name string `json:"name"`
+	Tags []Tag  `json:"tags"`
+}
+
+// Tag is a single release tag along with its verification results.
+type Tag struct {
+	Name     string `json:"name"`
+	Phase    string `json:"phase"`
+	Pullspec string `json:"pullspec"`
+	// URL is the release controller page for the release tag.
+	URL string `json:"url"`
+	// ChangelogURL is the changelog from the previous accepted release tag in
+	// the release stream, if there is one.
+	ChangelogURL string        `json:"changelogURL,omitempty"`
+	BlockingJobs []Job         `json:"blockingJobs"`
+	UpgradesTo   []UpgradeEdge `json:"upgradesTo"`
+}
+
+// Job is the state of a single verification job.
+type Job struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+	URL   string `json:"url,omitempty"`
+}
+
+// UpgradeEdge is the result of the upgrade tests from a given release to the
+// release tag.
+type UpgradeEdge struct {
+	From    string `json:"from"`
+	Success int    `json:"success"`
+	Failure int    `json:"failure"`
+	Total   int    `json:"total"`
+}
+
+// The data each page is rendered from.
+type page struct {
+	Title  string
+	Site   *Site
+	Stream *Stream
+}
+
+// Collect gets the most recent release tags and their verification results
+// for each of the given release streams.
+func Collect(ctx context.Context, rc *releasecontroller.ReleaseController, opts Opts) (*Site, error) {
+	numTags := opts.Tags
+	if numTags <= 0 {
+		numTags = defaultTags
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	out := &Site{
+		Controller:  rc.WebController().Host(),
+		GeneratedAt: now,
+		Streams:     make([]Stream, len(opts.Streams)),
+	}
+
+	// Every tag list is fetched before any verification results so that a
+	// failure does not leave goroutines running.
+	streamTags := make([]*releasecontroller.ReleaseTags, len(opts.Streams))
+
+	for i, name := range opts.Streams {
+		tags, err := rc.ReleaseStream(name).Tags(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not get tags for release stream %q: %w", name, err)
+		}
+
+		streamTags[i] = tags
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	if opts.Concurrency > 0 {
+		g.SetLimit(opts.Concurrency)
+	}
+
+	for i, name := range opts.Streams {
+		rs := rc.ReleaseStream(name)
+		tags := streamTags[i]
+
+		releases := tags.Tags
+		if len(releases) > numTags {
+			releases = releases[:numTags]
+		}
+
+		out.Streams[i] = Stream{Name: name, Tags: make([]Tag, len(releases))}
+
+		for j, release := range releases {
+			tag := &out.Streams[i].Tags[j]
+			tag.Name = release.Name
+			tag.Phase = release.Phase
+			tag.Pullspec = release.Pullspec
+			tag.URL = rc.ReleaseURL(name, release.Name)
+
+			if previous := getPreviousAccepted(tags.Tags, release.Name); previous != "" {
+				tag.ChangelogURL = rc.ChangelogURL(previous, release.Name)
+			}
+
+			g.Go(func() error {
+				info, err := rs.Tag(ctx, release.Name)
+				if err != nil {
+					return fmt.Errorf("could not get verification results for release tag %q: %w", release.Name, err)
+				}
+
+				tag.BlockingJobs = getBlockingJobs(info)
+				tag.UpgradesTo = getUpgradeEdges(info)
+
+				return nil
+			})
+		}
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Render writes the report as a static site into the given directory. Each
+// file is written to a temporary file first and then renamed so that a web
+// server serving the directory never serves a partially-written file.
+func Render(site *Site, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	index := &page{Title: "Release streams", Site: site}
+	if err := renderFile(filepath.Join(dir, "index.html"), "index.html.tmpl", index); err != nil {
+		return err
+	}
+
+	for _, stream := range site.Streams {
+		p := &page{Title: stream.Name, Site: site, Stream: &stream}
+		if err := renderFile(filepath.Join(dir, pageName(stream.Name)), "stream.html.tmpl", p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func renderFile(path, tmpl string, data interface{}) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if err := templates.ExecuteTemplate(tmp, tmpl, data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not render %s: %w", filepath.Base(path), err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	// Temp files are only readable by their owner, which would prevent a web
+	// server from reading them.
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Returns the name of the page for the given release stream.
+func pageName(stream string) string {
+	return unsafeFilenameChars.ReplaceAllString(stream, "_") + ".html"
+}
+
+// Returns the newest accepted release tag, if there is one.
+func lastAccepted(tags []Tag) *Tag {
+	for _, tag := range tags {
+		if tag.Phase == string(releasecontroller.PhaseAccepted) {
+			return &tag
+		}
+	}
+
+	return nil
+}
+
+// Finds the newest accepted release tag which is older than the given one.
+// Release tags are ordered newest first.
+func getPreviousAccepted(releases []releasecontroller.Release, name string) string {
+	found := false
+
+	for _, release := range releases {
+		if release.Name == name {
+			found = true
+			continue
+		}
+
+		if found && release.Phase == string(releasecontroller.PhaseAccepted) {
+			return release.Name
+		}
+	}
+
+	return ""
+}
+
+func getBlockingJobs(info *releasecontroller.APIReleaseInfo) []Job {
+	out := []Job{}
+
+	if info.Results == nil {
+		return out
+	}
+
+	for name, status := range info.Results.BlockingJobs {
+		if status == nil {
+			out = append(out, Job{Name: name, State: "Pending"})
+			continue
+		}
+
+		out = append(out, Job{Name: name, State: status.State, URL: status.URL})
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+
+	return out
+}
+
+func getUpgradeEdges(info *releasecontroller.APIReleaseInfo) []UpgradeEdge {
+	out := []UpgradeEdge{}
+
+	for _, upgrade := range info.UpgradesTo {
+		out = append(out, UpgradeEdge{
+			From:    upgrade.From,
+			Success: upgrade.Success,
+			Failure: upgrade.Failure,
+			Total:   upgrade.Total,
+		})
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].From < out[j].From
+	})
+
+	return out
+}
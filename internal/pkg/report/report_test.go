@@ -0,0 +1,191 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cheesesashimi/zacks-openshift-helpers/internal/pkg/releasecontroller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFakeReleaseController(t *testing.T) *releasecontroller.ReleaseController {
+	t.Helper()
+
+	tags := map[string]*releasecontroller.ReleaseTags{
+		"4.18.0-0.nightly": {
+			Name: "4.18.0-0.nightly",
+			Tags: []releasecontroller.Release{
+				{Name: "4.18.0-0.nightly-2025-01-03-000000", Phase: "Rejected", Pullspec: "registry.ci.openshift.org/ocp/release:4.18.0-0.nightly-2025-01-03-000000"},
+				{Name: "4.18.0-0.nightly-2025-01-02-000000", Phase: "Accepted", Pullspec: "registry.ci.openshift.org/ocp/release:4.18.0-0.nightly-2025-01-02-000000"},
+				{Name: "4.18.0-0.nightly-2025-01-01-000000", Phase: "Accepted", Pullspec: "registry.ci.openshift.org/ocp/release:4.18.0-0.nightly-2025-01-01-000000"},
+			},
+		},
+		"4.19.0-0.nightly": {
+			Name: "4.19.0-0.nightly",
+			Tags: []releasecontroller.Release{},
+		},
+	}
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+
+		var out interface{}
+
+		switch {
+		case len(parts) == 5 && parts[4] == "tags":
+			if streamTags, ok := tags[parts[3]]; ok {
+				out = streamTags
+			}
+		case len(parts) == 6 && parts[4] == "release":
+			out = &releasecontroller.APIReleaseInfo{
+				Name: parts[5],
+				Results: &releasecontroller.VerificationJobsSummary{
+					BlockingJobs: releasecontroller.VerificationStatusMap{
+						"upgrade": {State: "Failed", URL: "https://prow.ci.openshift.org/upgrade"},
+						"install": {State: "Succeeded"},
+					},
+				},
+				UpgradesTo: []releasecontroller.UpgradeHistory{
+					{From: "4.17.10", Success: 2, Failure: 1, Total: 3},
+				},
+			}
+		}
+
+		if out == nil {
+			http.NotFound(w, req)
+			return
+		}
+
+		//nolint:errcheck // This is test code.
+		json.NewEncoder(w).Encode(out)
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return releasecontroller.New(srv.Listener.Addr().String(), &releasecontroller.ReleaseControllerConfig{Client: srv.Client()})
+}
+
+func TestCollect(t *testing.T) {
+	rc := newFakeReleaseController(t)
+
+	now := time.Date(2025, time.January, 3, 12, 0, 0, 0, time.UTC)
+
+	site, err := Collect(context.Background(), rc, Opts{Streams: []string{"4.18.0-0.nightly", "4.19.0-0.nightly"}, Tags: 2, Now: now})
+	require.NoError(t, err)
+
+	assert.Equal(t, rc.Host(), site.Controller)
+	assert.Equal(t, now, site.GeneratedAt)
+	require.Len(t, site.Streams, 2)
+
+	nightly := site.Streams[0]
+	assert.Equal(t, "4.18.0-0.nightly", nightly.Name)
+	require.Len(t, nightly.Tags, 2)
+
+	assert.Equal(t, Tag{
+		Name:         "4.18.0-0.nightly-2025-01-03-000000",
+		Phase:        "Rejected",
+		Pullspec:     "registry.ci.openshift.org/ocp/release:4.18.0-0.nightly-2025-01-03-000000",
+		URL:          rc.ReleaseURL("4.18.0-0.nightly", "4.18.0-0.nightly-2025-01-03-000000"),
+		ChangelogURL: rc.ChangelogURL("4.18.0-0.nightly-2025-01-02-000000", "4.18.0-0.nightly-2025-01-03-000000"),
+		BlockingJobs: []Job{
+			{Name: "install", State: "Succeeded"},
+			{Name: "upgrade", State: "Failed", URL: "https://prow.ci.openshift.org/upgrade"},
+		},
+		UpgradesTo: []UpgradeEdge{{From: "4.17.10", Success: 2, Failure: 1, Total: 3}},
+	}, nightly.Tags[0])
+
+	// The changelog is relative to the previous accepted release, even if it
+	// is not included in the report.
+	assert.Equal(t, rc.ChangelogURL("4.18.0-0.nightly-2025-01-01-000000", "4.18.0-0.nightly-2025-01-02-000000"), nightly.Tags[1].ChangelogURL)
+
+	assert.Empty(t, site.Streams[1].Tags)
+
+	// Links point at the web controller, e.g., when collecting from a cache
+	// served by rcctl serve.
+	linked := rc.WithWebController(releasecontroller.New(releasecontroller.Amd64OcpReleaseController, nil))
+	site, err = Collect(context.Background(), linked, Opts{Streams: []string{"4.18.0-0.nightly"}, Tags: 1, Now: now})
+	require.NoError(t, err)
+	assert.Equal(t, releasecontroller.Amd64OcpReleaseController, site.Controller)
+	assert.Equal(t, "https://"+releasecontroller.Amd64OcpReleaseController+"/releasestream/4.18.0-0.nightly/release/4.18.0-0.nightly-2025-01-03-000000", site.Streams[0].Tags[0].URL)
+
+	_, err = Collect(context.Background(), rc, Opts{Streams: []string{"unknown"}})
+	assert.Error(t, err)
+
+	_, err = Collect(context.Background(), rc, Opts{Streams: []string{"4.18.0-0.nightly", "unknown"}, Tags: 2, Now: now})
+	assert.Error(t, err)
+}
+
+func TestRender(t *testing.T) {
+	site := &Site{
+		Controller:  "amd64.ocp.releases.ci.openshift.org",
+		GeneratedAt: time.Date(2025, time.January, 3, 12, 0, 0, 0, time.UTC),
+		Streams: []Stream{
+			{
+				Name: "4.18.0-0.nightly",
+				Tags: []Tag{
+					{
+						Name:         "4.18.0-0.nightly-2025-01-03-000000",
+						Phase:        "Rejected",
+						URL:          "https://amd64.ocp.releases.ci.openshift.org/releasestream/4.18.0-0.nightly/release/4.18.0-0.nightly-2025-01-03-000000",
+						ChangelogURL: "https://amd64.ocp.releases.ci.openshift.org/changelog?from=a&to=b",
+						BlockingJobs: []Job{{Name: "<upgrade>", State: "Failed"}},
+						UpgradesTo:   []UpgradeEdge{{From: "4.17.10", Success: 2, Failure: 1, Total: 3}},
+					},
+					{
+						Name:  "4.18.0-0.nightly-2025-01-02-000000",
+						Phase: "Accepted",
+						URL:   "https://amd64.ocp.releases.ci.openshift.org/releasestream/4.18.0-0.nightly/release/4.18.0-0.nightly-2025-01-02-000000",
+					},
+				},
+			},
+			{
+				Name: "4-dev-preview/arm64",
+			},
+		},
+	}
+
+	dir := filepath.Join(t.TempDir(), "site")
+
+	require.NoError(t, Render(site, dir))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+
+		info, err := entry.Info()
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o644), info.Mode().Perm())
+	}
+
+	assert.ElementsMatch(t, []string{"index.html", "4.18.0-0.nightly.html", "4-dev-preview_arm64.html"}, names)
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(index), `<a href="4.18.0-0.nightly.html">4.18.0-0.nightly</a>`)
+	assert.Contains(t, string(index), `<span class="badge rejected">Rejected</span>`)
+	assert.Contains(t, string(index), `>4.18.0-0.nightly-2025-01-02-000000</a>`)
+	assert.Contains(t, string(index), `No releases`)
+	assert.Contains(t, string(index), `2025-01-03T12:00:00Z`)
+
+	stream, err := os.ReadFile(filepath.Join(dir, "4.18.0-0.nightly.html"))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(stream), `<span class="badge failed">Failed</span> &lt;upgrade&gt;`)
+	assert.Contains(t, string(stream), `4.17.10: 2/3 succeeded, 1 failed`)
+	assert.Contains(t, string(stream), `<a href="https://amd64.ocp.releases.ci.openshift.org/changelog?from=a&amp;to=b">Changes</a>`)
+
+	// Rendering again should replace the existing files.
+	require.NoError(t, Render(site, dir))
+}
@@ -0,0 +1,192 @@
+package signature
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/image"
+	imgsig "github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/types"
+)
+
+// Mode is the kind of signature to verify.
+type Mode string
+
+const (
+	// SimpleSigningMode verifies GPG simple signing signatures, which is how
+	// OpenShift release payloads are signed.
+	SimpleSigningMode Mode = "simple-signing"
+	// SigstoreMode verifies sigstore (cosign) signatures attached to the image
+	// in its registry.
+	SigstoreMode Mode = "sigstore"
+)
+
+const (
+	// DefaultSignatureStore is where signatures for official OpenShift release
+	// payloads are published.
+	DefaultSignatureStore string = "https://mirror.openshift.com/pub/openshift-v4/signatures/openshift/release"
+)
+
+// Opts holds the options for verifying a signature.
+type Opts struct {
+	// Mode is the kind of signature to verify.
+	Mode Mode
+	// PublicKeyPath is the path to a GPG public keyring (for simple signing) or
+	// a PEM-encoded public key (for sigstore).
+	PublicKeyPath string
+	// SignatureStore is where signatures are retrieved from. For simple
+	// signing, this is either an HTTP(S) URL or a directory laid out as
+	// sha256=<digest>/signature-<n>. For sigstore, this optionally overrides
+	// the repository (e.g., a mirror) which the image and its attached
+	// signatures are read from.
+	SignatureStore string
+	// AuthfilePath is the path to a registry auth file.
+	AuthfilePath string
+	// Insecure allows talking to the registry over plain HTTP or with an
+	// untrusted TLS certificate.
+	Insecure bool
+}
+
+// Result describes a verified signature.
+type Result struct {
+	// Pullspec is the digested pullspec that was verified.
+	Pullspec string `json:"pullspec"`
+	// Digest is the verified manifest digest.
+	Digest string `json:"digest"`
+	// Mode is the kind of signature that was verified.
+	Mode Mode `json:"mode"`
+	// PublicKey is the path to the public key (or keyring) the signature was
+	// verified with.
+	PublicKey string `json:"publicKey"`
+	// Identity is the repository which the signature was required to claim.
+	Identity string `json:"identity"`
+	// SignatureStore is where the signatures were read from.
+	SignatureStore string `json:"signatureStore"`
+}
+
+// Verify verifies that the image referred to by the given pullspec has a valid
+// signature from the configured public key. Tagged pullspecs are resolved to
+// their digest first; callers should use the digested pullspec from the result
+// so that what they use is what was verified.
+func Verify(ctx context.Context, pullspec string, opts Opts) (*Result, error) {
+	if opts.PublicKeyPath == "" {
+		return nil, fmt.Errorf("a public key must be provided")
+	}
+
+	if opts.Mode == "" {
+		opts.Mode = SimpleSigningMode
+	}
+
+	sysCtx := &types.SystemContext{
+		AuthFilePath: opts.AuthfilePath,
+	}
+
+	if opts.Insecure {
+		sysCtx.DockerInsecureSkipTLSVerify = types.NewOptionalBool(true)
+	}
+
+	named, err := resolveDigest(ctx, sysCtx, pullspec)
+	if err != nil {
+		return nil, err
+	}
+
+	// The signature must claim the repository the pullspec refers to, even
+	// when the image is read from elsewhere.
+	repo := reference.TrimNamed(named).String()
+
+	identity, err := imgsig.NewPRMExactRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var v *verification
+
+	switch opts.Mode {
+	case SimpleSigningMode:
+		v, err = newSimpleSigningVerification(ctx, sysCtx, named, identity, opts)
+	case SigstoreMode:
+		v, err = newSigstoreVerification(ctx, sysCtx, named, identity, opts)
+	default:
+		return nil, fmt.Errorf("unknown signature mode %q", opts.Mode)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not verify signature for %q: %w", named, err)
+	}
+
+	defer v.imgSrc.Close()
+
+	if err := v.check(ctx); err != nil {
+		return nil, fmt.Errorf("could not verify signature for %q: %w", named, err)
+	}
+
+	return &Result{
+		Pullspec:       named.String(),
+		Digest:         named.Digest().String(),
+		Mode:           opts.Mode,
+		PublicKey:      opts.PublicKeyPath,
+		Identity:       repo,
+		SignatureStore: v.store,
+	}, nil
+}
+
+// An image source along with the policy requirement that its signatures must
+// satisfy.
+type verification struct {
+	imgSrc      types.ImageSource
+	requirement imgsig.PolicyRequirement
+	// Where the signatures are read from.
+	store string
+}
+
+// Evaluates a signature policy which accepts the image only if the
+// requirement is satisfied.
+func (v *verification) check(ctx context.Context) error {
+	policyCtx, err := imgsig.NewPolicyContext(&imgsig.Policy{
+		Default: imgsig.PolicyRequirements{v.requirement},
+	})
+	if err != nil {
+		return err
+	}
+
+	//nolint:errcheck // There is nothing to do if the policy context cannot be destroyed.
+	defer policyCtx.Destroy()
+
+	allowed, err := policyCtx.IsRunningImageAllowed(ctx, image.UnparsedInstance(v.imgSrc, nil))
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return fmt.Errorf("no valid signatures found in %s", v.store)
+	}
+
+	return nil
+}
+
+// Resolves the given pullspec to a digested reference without a tag. Tagged
+// pullspecs are resolved by asking the registry for their current digest.
+func resolveDigest(ctx context.Context, sysCtx *types.SystemContext, pullspec string) (reference.Canonical, error) {
+	named, err := reference.ParseNormalizedNamed(pullspec)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse pullspec %q: %w", pullspec, err)
+	}
+
+	if digested, ok := named.(reference.Digested); ok {
+		return reference.WithDigest(reference.TrimNamed(named), digested.Digest())
+	}
+
+	ref, err := docker.NewReference(reference.TagNameOnly(named))
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := docker.GetDigest(ctx, sysCtx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve %q to a digest: %w", pullspec, err)
+	}
+
+	return reference.WithDigest(reference.TrimNamed(named), d)
+}
@@ -0,0 +1,432 @@
+package signature
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/types"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/opencontainers/go-digest"
+	imgspecs "github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	sigstoresig "github.com/sigstore/sigstore/pkg/signature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	//lint:ignore SA1019 This is test code.
+	"golang.org/x/crypto/openpgp" //nolint:staticcheck
+	//lint:ignore SA1019 This is test code.
+	"golang.org/x/crypto/openpgp/armor" //nolint:staticcheck
+	//lint:ignore SA1019 This is test code.
+	"golang.org/x/crypto/openpgp/packet" //nolint:staticcheck
+)
+
+const (
+	simpleSigningType string = "atomic container signature"
+	sigstoreType      string = "cosign container image signature"
+
+	sigstoreSignatureAnnotation string = "dev.cosignproject.cosign/signature"
+	sigstorePayloadMediaType    string = "application/vnd.dev.cosign.simplesigning.v1+json"
+
+	testSignerEmail string = "release@example.com"
+)
+
+var gpgConfig = &packet.Config{DefaultHash: crypto.SHA256}
+
+var insecureSysCtx = &types.SystemContext{DockerInsecureSkipTLSVerify: types.NewOptionalBool(true)}
+
+// A signature payload; see:
+// https://github.com/containers/image/blob/main/docs/containers-signature.5.md
+func newPayload(t *testing.T, sigType string, d digest.Digest, identity string) []byte {
+	t.Helper()
+
+	critical := map[string]interface{}{
+		"type":     sigType,
+		"image":    map[string]string{"docker-manifest-digest": d.String()},
+		"identity": map[string]string{"docker-reference": identity},
+	}
+
+	out, err := json.Marshal(map[string]interface{}{"critical": critical, "optional": map[string]interface{}{}})
+	require.NoError(t, err)
+	return out
+}
+
+// Starts an in-memory registry and returns its host.
+func newTestRegistry(t *testing.T) string {
+	t.Helper()
+
+	srv := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(srv.Close)
+
+	return strings.TrimPrefix(srv.URL, "http://")
+}
+
+type testLayer struct {
+	mediaType   string
+	data        []byte
+	annotations map[string]string
+}
+
+// Pushes an image with the given layers to the registry and returns the digest
+// of its manifest.
+func pushImage(ctx context.Context, t *testing.T, pullspec string, layers ...testLayer) digest.Digest {
+	t.Helper()
+
+	ref, err := docker.ParseReference("//" + pullspec)
+	require.NoError(t, err)
+
+	dest, err := ref.NewImageDestination(ctx, insecureSysCtx)
+	require.NoError(t, err)
+
+	defer dest.Close()
+
+	putBlob := func(b []byte, mediaType string, isConfig bool) imgspecv1.Descriptor {
+		info, err := dest.PutBlob(ctx, bytes.NewReader(b), types.BlobInfo{Digest: digest.FromBytes(b), Size: int64(len(b))}, none.NoCache, isConfig)
+		require.NoError(t, err)
+		return imgspecv1.Descriptor{MediaType: mediaType, Digest: info.Digest, Size: info.Size}
+	}
+
+	m := imgspecv1.Manifest{
+		Versioned: imgspecs.Versioned{SchemaVersion: 2},
+		MediaType: imgspecv1.MediaTypeImageManifest,
+		Config:    putBlob([]byte("{}"), imgspecv1.MediaTypeImageConfig, true),
+	}
+
+	for _, layer := range layers {
+		desc := putBlob(layer.data, layer.mediaType, false)
+		desc.Annotations = layer.annotations
+		m.Layers = append(m.Layers, desc)
+	}
+
+	rawManifest, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.NoError(t, dest.PutManifest(ctx, rawManifest, nil))
+	require.NoError(t, dest.Commit(ctx, nil))
+
+	return digest.FromBytes(rawManifest)
+}
+
+// Pushes a release image to the given repository and returns its digest.
+func pushTestRelease(ctx context.Context, t *testing.T, repo string) digest.Digest {
+	t.Helper()
+
+	return pushImage(ctx, t, repo+":4.21.4-x86_64", testLayer{mediaType: imgspecv1.MediaTypeImageLayer, data: []byte("release")})
+}
+
+func newGPGKey(t *testing.T, dir, name string) (*openpgp.Entity, string) {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity(name, "", testSignerEmail, gpgConfig)
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer([]byte{})
+	w, err := armor.Encode(buf, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+
+	keyPath := filepath.Join(dir, name+".asc")
+	require.NoError(t, os.WriteFile(keyPath, buf.Bytes(), 0o644))
+
+	return entity, keyPath
+}
+
+func gpgSign(t *testing.T, entity *openpgp.Entity, payload []byte) []byte {
+	t.Helper()
+
+	buf := bytes.NewBuffer([]byte{})
+	w, err := openpgp.Sign(buf, entity, nil, gpgConfig)
+	require.NoError(t, err)
+	_, err = w.Write(payload)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func writeStoredSignature(t *testing.T, storeDir string, d digest.Digest, index int, sig []byte) {
+	t.Helper()
+
+	path := getSignatureLocation(storeDir, d, index)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, sig, 0o644))
+}
+
+func TestVerifySimpleSigning(t *testing.T) {
+	ctx := context.Background()
+
+	keyDir := t.TempDir()
+	signer, keyPath := newGPGKey(t, keyDir, "signer")
+	untrusted, _ := newGPGKey(t, keyDir, "untrusted")
+
+	repo := newTestRegistry(t) + "/ocp/release"
+	d := pushTestRelease(ctx, t, repo)
+	otherDigest := pushImage(ctx, t, repo+":other", testLayer{mediaType: imgspecv1.MediaTypeImageLayer, data: []byte("other")})
+
+	pullspec := repo + "@" + d.String()
+	identity := repo + ":4.21.4-x86_64"
+
+	testCases := []struct {
+		name       string
+		signatures [][]byte
+		expectErr  bool
+	}{
+		{
+			name:       "Valid signature",
+			signatures: [][]byte{gpgSign(t, signer, newPayload(t, simpleSigningType, d, identity))},
+		},
+		{
+			name: "Valid signature after an untrusted one",
+			signatures: [][]byte{
+				gpgSign(t, untrusted, newPayload(t, simpleSigningType, d, identity)),
+				gpgSign(t, signer, newPayload(t, simpleSigningType, d, identity)),
+			},
+		},
+		{
+			name:       "No signatures",
+			signatures: [][]byte{},
+			expectErr:  true,
+		},
+		{
+			name:       "Untrusted signer",
+			signatures: [][]byte{gpgSign(t, untrusted, newPayload(t, simpleSigningType, d, identity))},
+			expectErr:  true,
+		},
+		{
+			name:       "Signature for a different digest",
+			signatures: [][]byte{gpgSign(t, signer, newPayload(t, simpleSigningType, otherDigest, identity))},
+			expectErr:  true,
+		},
+		{
+			name:       "Signature for a different repository",
+			signatures: [][]byte{gpgSign(t, signer, newPayload(t, simpleSigningType, d, "quay.io/example/release:4.21.4-x86_64"))},
+			expectErr:  true,
+		},
+		{
+			name:       "Signature of the wrong type",
+			signatures: [][]byte{gpgSign(t, signer, newPayload(t, sigstoreType, d, identity))},
+			expectErr:  true,
+		},
+		{
+			name:       "Garbage signature",
+			signatures: [][]byte{[]byte("not a signature")},
+			expectErr:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			storeDir := t.TempDir()
+			for i, sig := range testCase.signatures {
+				writeStoredSignature(t, storeDir, d, i+1, sig)
+			}
+
+			srv := httptest.NewServer(http.FileServer(http.Dir(storeDir)))
+			defer srv.Close()
+
+			// Verify against both a directory and an HTTP signature store, as
+			// well as with a tagged pullspec.
+			for _, store := range []string{storeDir, srv.URL} {
+				for _, toVerify := range []string{pullspec, identity} {
+					result, err := Verify(ctx, toVerify, Opts{
+						Mode:           SimpleSigningMode,
+						PublicKeyPath:  keyPath,
+						SignatureStore: store,
+						Insecure:       true,
+					})
+
+					if testCase.expectErr {
+						assert.Error(t, err)
+						continue
+					}
+
+					require.NoError(t, err)
+					assert.Equal(t, pullspec, result.Pullspec)
+					assert.Equal(t, d.String(), result.Digest)
+					assert.Equal(t, SimpleSigningMode, result.Mode)
+					assert.Equal(t, keyPath, result.PublicKey)
+					assert.Equal(t, repo, result.Identity)
+					assert.Equal(t, store, result.SignatureStore)
+				}
+			}
+		})
+	}
+}
+
+func newSigstoreKey(t *testing.T, dir, name string) (sigstoresig.SignerVerifier, string) {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	sv, err := sigstoresig.LoadECDSASignerVerifier(privateKey, crypto.SHA256)
+	require.NoError(t, err)
+
+	pemBytes, err := cryptoutils.MarshalPublicKeyToPEM(privateKey.Public())
+	require.NoError(t, err)
+
+	keyPath := filepath.Join(dir, name+".pub")
+	require.NoError(t, os.WriteFile(keyPath, pemBytes, 0o644))
+
+	return sv, keyPath
+}
+
+// Pushes a sigstore signature for the given digest to the repository under
+// the tag which sigstore attaches signatures with.
+func pushSigstoreSignature(ctx context.Context, t *testing.T, repo string, d digest.Digest, payload, sig []byte) {
+	t.Helper()
+
+	pushImage(ctx, t, fmt.Sprintf("%s:%s-%s.sig", repo, d.Algorithm(), d.Encoded()), testLayer{
+		mediaType: sigstorePayloadMediaType,
+		data:      payload,
+		annotations: map[string]string{
+			sigstoreSignatureAnnotation: base64.StdEncoding.EncodeToString(sig),
+		},
+	})
+}
+
+func TestVerifySigstore(t *testing.T) {
+	ctx := context.Background()
+
+	keyDir := t.TempDir()
+	signer, keyPath := newSigstoreKey(t, keyDir, "signer")
+	untrusted, _ := newSigstoreKey(t, keyDir, "untrusted")
+
+	sign := func(sv sigstoresig.SignerVerifier, payload []byte) []byte {
+		sig, err := sv.SignMessage(bytes.NewReader(payload))
+		require.NoError(t, err)
+		return sig
+	}
+
+	host := newTestRegistry(t)
+
+	testCases := []struct {
+		name string
+		// Returns the payload and its signature for the given digest and
+		// identity.
+		signature func(d digest.Digest, identity string) ([]byte, []byte)
+		// Whether the image and its signature are read from a mirror.
+		mirrored  bool
+		expectErr bool
+	}{
+		{
+			name: "Valid signature",
+			signature: func(d digest.Digest, identity string) ([]byte, []byte) {
+				payload := newPayload(t, sigstoreType, d, identity)
+				return payload, sign(signer, payload)
+			},
+		},
+		{
+			name: "Valid signature read from a mirror",
+			signature: func(d digest.Digest, identity string) ([]byte, []byte) {
+				payload := newPayload(t, sigstoreType, d, identity)
+				return payload, sign(signer, payload)
+			},
+			mirrored: true,
+		},
+		{
+			name: "Untrusted signer",
+			signature: func(d digest.Digest, identity string) ([]byte, []byte) {
+				payload := newPayload(t, sigstoreType, d, identity)
+				return payload, sign(untrusted, payload)
+			},
+			expectErr: true,
+		},
+		{
+			name: "Signature for a different digest",
+			signature: func(_ digest.Digest, identity string) ([]byte, []byte) {
+				payload := newPayload(t, sigstoreType, digest.FromString("other"), identity)
+				return payload, sign(signer, payload)
+			},
+			expectErr: true,
+		},
+		{
+			name: "Signature for a different repository",
+			signature: func(d digest.Digest, _ string) ([]byte, []byte) {
+				payload := newPayload(t, sigstoreType, d, "quay.io/example/release:4.21.4-x86_64")
+				return payload, sign(signer, payload)
+			},
+			expectErr: true,
+		},
+		{
+			name: "Tampered payload",
+			signature: func(d digest.Digest, identity string) ([]byte, []byte) {
+				return newPayload(t, sigstoreType, d, identity), sign(signer, newPayload(t, sigstoreType, digest.FromString("other"), identity))
+			},
+			expectErr: true,
+		},
+		{
+			name:      "Missing signature",
+			expectErr: true,
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Each test case has its own repository since signatures are
+			// attached to the image in its repository.
+			repo := fmt.Sprintf("%s/ocp/release-%d", host, i)
+			d := pushTestRelease(ctx, t, repo)
+
+			opts := Opts{
+				Mode:          SigstoreMode,
+				PublicKeyPath: keyPath,
+				Insecure:      true,
+			}
+
+			sigRepo := repo
+			if testCase.mirrored {
+				sigRepo = fmt.Sprintf("%s/mirror/release-%d", host, i)
+				require.Equal(t, d, pushTestRelease(ctx, t, sigRepo))
+				opts.SignatureStore = sigRepo
+			}
+
+			if testCase.signature != nil {
+				payload, sig := testCase.signature(d, repo+":4.21.4-x86_64")
+				pushSigstoreSignature(ctx, t, sigRepo, d, payload, sig)
+			}
+
+			result, err := Verify(ctx, repo+"@"+d.String(), opts)
+
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, d.String(), result.Digest)
+			assert.Equal(t, SigstoreMode, result.Mode)
+			assert.Equal(t, keyPath, result.PublicKey)
+			assert.Equal(t, repo, result.Identity)
+			assert.Equal(t, sigRepo, result.SignatureStore)
+		})
+	}
+}
+
+func TestVerifyOpts(t *testing.T) {
+	pullspec := "quay.io/openshift-release-dev/ocp-release@" + digest.FromString("release").String()
+
+	_, err := Verify(context.Background(), pullspec, Opts{})
+	assert.Error(t, err)
+
+	_, err = Verify(context.Background(), pullspec, Opts{Mode: "unknown", PublicKeyPath: "/dev/null"})
+	assert.Error(t, err)
+}
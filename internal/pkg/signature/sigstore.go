@@ -0,0 +1,74 @@
+package signature
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/reference"
+	imgsig "github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/types"
+)
+
+// Tells containers/image to read the sigstore signatures attached to images
+// in their repositories (<repo>:sha256-<hex>.sig).
+const sigstoreRegistriesConfig string = `default-docker:
+  use-sigstore-attachments: true
+`
+
+func newSigstoreVerification(ctx context.Context, sysCtx *types.SystemContext, named reference.Canonical, identity imgsig.PolicyReferenceMatch, opts Opts) (*verification, error) {
+	requirement, err := imgsig.NewPRSigstoreSignedKeyPath(opts.PublicKeyPath, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	source := named
+
+	// Sigstore signatures are attached to the image within its repository, so
+	// both are read from the overriding repository.
+	if opts.SignatureStore != "" {
+		storeNamed, err := reference.ParseNormalizedNamed(opts.SignatureStore)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse signature repository %q: %w", opts.SignatureStore, err)
+		}
+
+		source, err = reference.WithDigest(reference.TrimNamed(storeNamed), named.Digest())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	registriesDir, err := os.MkdirTemp("", "rcctl-registries.d-")
+	if err != nil {
+		return nil, err
+	}
+
+	// The configuration is read when the image source is opened, so it is not
+	// needed afterward.
+	defer os.RemoveAll(registriesDir)
+
+	if err := os.WriteFile(filepath.Join(registriesDir, "sigstore.yaml"), []byte(sigstoreRegistriesConfig), 0o644); err != nil {
+		return nil, err
+	}
+
+	sigstoreSysCtx := *sysCtx
+	sigstoreSysCtx.RegistriesDirPath = registriesDir
+
+	ref, err := docker.NewReference(source)
+	if err != nil {
+		return nil, err
+	}
+
+	imgSrc, err := ref.NewImageSource(ctx, &sigstoreSysCtx)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %q: %w", source, err)
+	}
+
+	return &verification{
+		imgSrc:      imgSrc,
+		requirement: requirement,
+		store:       reference.TrimNamed(source).String(),
+	}, nil
+}
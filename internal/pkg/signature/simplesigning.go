@@ -0,0 +1,147 @@
+package signature
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/reference"
+	imgsig "github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+)
+
+const (
+	// The maximum number of signatures to look for in the signature store.
+	maxSignatures int = 10
+)
+
+func newSimpleSigningVerification(ctx context.Context, sysCtx *types.SystemContext, named reference.Canonical, identity imgsig.PolicyReferenceMatch, opts Opts) (*verification, error) {
+	requirement, err := imgsig.NewPRSignedByKeyPath(imgsig.SBKeyTypeGPGKeys, opts.PublicKeyPath, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	store := opts.SignatureStore
+	if store == "" {
+		store = DefaultSignatureStore
+	}
+
+	sigs, err := getSignaturesFromStore(ctx, store, named.Digest())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sigs) == 0 {
+		return nil, fmt.Errorf("no signatures found in %s", store)
+	}
+
+	ref, err := docker.NewReference(named)
+	if err != nil {
+		return nil, err
+	}
+
+	imgSrc, err := ref.NewImageSource(ctx, sysCtx)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %q: %w", named, err)
+	}
+
+	return &verification{
+		imgSrc:      &storeImageSource{ImageSource: imgSrc, signatures: sigs},
+		requirement: requirement,
+		store:       store,
+	}, nil
+}
+
+// Serves the signatures from a signature store in place of any which the
+// registry has, since OpenShift release payload signatures are published
+// separately from the images they sign.
+type storeImageSource struct {
+	types.ImageSource
+	signatures [][]byte
+}
+
+func (s *storeImageSource) GetSignatures(_ context.Context, instanceDigest *digest.Digest) ([][]byte, error) {
+	// Only the top-level manifest is signed.
+	if instanceDigest != nil {
+		return nil, nil
+	}
+
+	return s.signatures, nil
+}
+
+// Retrieves the signatures for the given digest from a signature store which
+// follows the layout used by mirror.openshift.com:
+// <store>/sha256=<hex>/signature-<n>. Signatures are numbered starting at 1
+// and the first missing signature ends the search.
+func getSignaturesFromStore(ctx context.Context, store string, d digest.Digest) ([][]byte, error) {
+	out := [][]byte{}
+
+	for i := 1; i <= maxSignatures; i++ {
+		location := getSignatureLocation(store, d, i)
+
+		data, err := readSignature(ctx, location)
+		if errors.Is(err, os.ErrNotExist) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("could not read signature %s: %w", location, err)
+		}
+
+		out = append(out, data)
+	}
+
+	return out, nil
+}
+
+func getSignatureLocation(store string, d digest.Digest, index int) string {
+	dir := fmt.Sprintf("%s=%s", d.Algorithm(), d.Encoded())
+	name := fmt.Sprintf("signature-%d", index)
+
+	if isURL(store) {
+		return strings.Join([]string{strings.TrimSuffix(store, "/"), dir, name}, "/")
+	}
+
+	return filepath.Join(strings.TrimPrefix(store, "file://"), dir, name)
+}
+
+func isURL(store string) bool {
+	return strings.HasPrefix(store, "https://") || strings.HasPrefix(store, "http://")
+}
+
+// Reads a signature from a URL or local path. Returns an error wrapping
+// os.ErrNotExist if the signature does not exist.
+func readSignature(ctx context.Context, location string) ([]byte, error) {
+	if !isURL(location) {
+		return os.ReadFile(location)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden {
+		return nil, fmt.Errorf("got HTTP %d from %s: %w", resp.StatusCode, location, os.ErrNotExist)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got HTTP %d from %s", resp.StatusCode, location)
+	}
+
+	return io.ReadAll(resp.Body)
+}